@@ -4,9 +4,9 @@ import (
 	"time"
 
 	"github.com/sbasestarter/bizinters/userinters"
-	"github.com/sbasestarter/bizmongolib/mongolib"
-	userpassauthenticator "github.com/sbasestarter/bizmongolib/user/authenticator/userpass"
 	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/gens/cannedresponsepb"
+	"github.com/sbasestarter/customer-service-be/gens/talksearchpb"
 	"github.com/sbasestarter/customer-service-be/internal/controller"
 	"github.com/sbasestarter/customer-service-be/internal/impls"
 	"github.com/sbasestarter/customer-service-be/internal/model"
@@ -49,38 +49,48 @@ func main() {
 		return
 	}
 
-	modelEx := impls.NewModelEx(model.NewMongoModel(&cfg.MongoConfig, logger))
+	modelEx := impls.NewModelEx(model.NewModel(cfg, logger))
 	mdi := impls.NewAllInOneMDI(modelEx, logger)
 
 	customerUserCenter := userlib.NewUserCenter(cfg.CustomerTokenSecret, single.NewPolicy(userinters.AuthMethodNameAnonymous),
 		memorystatuscontroller.NewStatusController(), memoryauthingdatastorage.NewMemoryAuthingDataStorage(), logger)
 	customerUserTokenHelper := impls.NewLocalCustomerUserTokenHelper(customerUserCenter)
-	customerMD := impls.NewCustomerMD(mdi, logger)
+	customerMD := impls.NewCustomerMDEx(mdi, impls.NewServicerProfiles(&cfg.ServicerDisplay), logger)
 	customerController := controller.NewCustomerController(customerMD, modelEx, logger)
-	grpcCustomerServer := server.NewCustomerServer(customerController, customerUserTokenHelper, logger)
+	grpcCustomerServer := server.NewCustomerServer(customerController, modelEx, customerUserTokenHelper, &cfg.PreChat, logger)
 	grpcCustomerUserServer := server.NewCustomerUserServer(customerUserCenter, customerUserTokenHelper)
 
-	mongoCli, mongoOptions, err := mongolib.InitMongo(cfg.UserMongoDSN)
+	servicerUserCenter := userlib.NewUserCenter(cfg.ServicerTokenSecret, single.NewPolicy(userinters.AuthMethodNameUserPassword),
+		memorystatuscontroller.NewStatusController(), memoryauthingdatastorage.NewMemoryAuthingDataStorage(), logger)
+	serviceUserPassModel := model.NewUserPasswordModel(cfg, "servicer_users", logger)
+	servicerManager := userpassmanager.NewManager(cfg.ServicerPasswordSecret, serviceUserPassModel)
+	servicerUserTokenHelper := impls.NewLocalServicerUserTokenHelper(servicerUserCenter, servicerManager)
+
+	routing, err := impls.NewServicerRouting(&cfg.Routing, model.NewServicerCapacityModel(cfg, "servicer_users", logger))
 	if err != nil {
 		logger.Fatal(err)
 
 		return
 	}
 
-	servicerUserCenter := userlib.NewUserCenter(cfg.ServicerTokenSecret, single.NewPolicy(userinters.AuthMethodNameUserPassword),
-		memorystatuscontroller.NewStatusController(), memoryauthingdatastorage.NewMemoryAuthingDataStorage(), logger)
-	serviceUserPassModel := userpassauthenticator.NewMongoUserPasswordModel(mongoCli, mongoOptions.Auth.AuthSource, "servicer_users", logger)
-	servicerManager := userpassmanager.NewManager(cfg.ServicerPasswordSecret, serviceUserPassModel)
-	servicerUserTokenHelper := impls.NewLocalServicerUserTokenHelper(servicerUserCenter, servicerManager)
-
-	servicerMD := impls.NewServicerMD(mdi, logger)
+	servicerMD := impls.NewServicerMDEx(mdi, routing, logger)
 	servicerController := controller.NewServicerController(servicerMD, modelEx, logger)
-	grpcServicerServer := server.NewServicerServer(servicerController, servicerUserTokenHelper, logger)
+
+	outboxRelay := impls.NewOutboxRelay(mdi, logger)
+	defer outboxRelay.StopAndWait()
+
+	grpcServicerServer := server.NewServicerServer(servicerController, modelEx, servicerUserTokenHelper, cfg.SupervisorIDs,
+		&cfg.TalkClassify, logger)
+	grpcCannedResponseServer := server.NewCannedResponseServer(model.NewCannedResponseModel(cfg, logger), modelEx,
+		servicerUserTokenHelper, cfg.SupervisorIDs, cfg.Routing.ServicerSkills, logger)
+	grpcTalkSearchServer := server.NewTalkSearchServer(modelEx, servicerUserTokenHelper, &cfg.TalkClassify, logger)
 	grpcServicerUserServer := server.NewServicerUserServer(servicerManager, servicerUserCenter, servicerUserTokenHelper)
 
 	err = s.Start(func(s *grpc.Server) error {
 		customertalkpb.RegisterCustomerTalkServiceServer(s, grpcCustomerServer)
 		customertalkpb.RegisterServiceTalkServiceServer(s, grpcServicerServer)
+		cannedresponsepb.RegisterCannedResponseServiceServer(s, grpcCannedResponseServer)
+		talksearchpb.RegisterTalkSearchServiceServer(s, grpcTalkSearchServer)
 		customertalkpb.RegisterCustomerUserServicerServer(s, grpcCustomerUserServer)
 		customertalkpb.RegisterServicerUserServicerServer(s, grpcServicerUserServer)
 
@@ -50,14 +50,17 @@ func main() {
 		memorystatuscontroller.NewStatusController(), memoryauthingdatastorage.NewMemoryAuthingDataStorage(), logger)
 	customerUserTokenHelper := impls.NewLocalCustomerUserTokenHelper(customerUserCenter)
 
-	modelEx := impls.NewModelEx(model.NewMongoModel(&cfg.MongoConfig, logger))
+	modelEx := impls.NewModelEx(model.NewModel(cfg, logger))
 	mdi := impls.NewCustomerRabbitMQMDI(cfg.RabbitMQURL, modelEx, logger)
 
-	customerMD := impls.NewCustomerMD(mdi, logger)
+	customerMD := impls.NewCustomerMDEx(mdi, impls.NewServicerProfiles(&cfg.ServicerDisplay), logger)
 
 	customerController := controller.NewCustomerController(customerMD, modelEx, logger)
 
-	grpcCustomerServer := server.NewCustomerServer(customerController, customerUserTokenHelper, logger)
+	outboxRelay := impls.NewOutboxRelay(mdi, logger)
+	defer outboxRelay.StopAndWait()
+
+	grpcCustomerServer := server.NewCustomerServer(customerController, modelEx, customerUserTokenHelper, &cfg.PreChat, logger)
 
 	err = s.Start(func(s *grpc.Server) error {
 		customertalkpb.RegisterCustomerTalkServiceServer(s, grpcCustomerServer)
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/impls"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/sgostarter/i/l"
+)
+
+func main() {
+	talkIDs := flag.String("talks", "", "the comma separated ids of the talks to export")
+	flag.Parse()
+
+	cfg := config.GetConfig()
+
+	logger := cfg.Logger
+
+	if *talkIDs == "" {
+		logger.Fatal("NoTalks")
+	}
+
+	m := model.NewModel(cfg, logger)
+
+	encoder := json.NewEncoder(os.Stdout)
+
+	for _, talkID := range strings.Split(*talkIDs, ",") {
+		talkID = strings.TrimSpace(talkID)
+
+		export, err := impls.ExportTalk(context.Background(), m, talkID)
+		if err != nil {
+			logger.WithFields(l.StringField("talkID", talkID), l.ErrorField(err)).Fatal("ExportTalkFailed")
+		}
+
+		if err = encoder.Encode(export); err != nil {
+			logger.WithFields(l.ErrorField(err)).Fatal("EncodeTalkFailed")
+		}
+	}
+}
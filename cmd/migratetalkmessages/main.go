@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/sgostarter/i/l"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only report what would be migrated")
+	dropLegacy := flag.Bool("drop-legacy", false, "drop talk:<id> collections once they are fully migrated")
+	flag.Parse()
+
+	cfg := config.GetConfig()
+
+	logger := cfg.Logger
+
+	report, err := model.MigrateMongoTalkMessages(context.Background(), &cfg.MongoConfig, *dryRun, *dropLegacy, logger)
+	if report != nil {
+		var total, migrated int64
+
+		var done int
+
+		for _, c := range report.Collections {
+			logger.WithFields(l.StringField("collection", c.Collection), l.Int64Field("total", c.Total),
+				l.Int64Field("migrated", c.Migrated), l.BoolField("done", c.Done)).Info("Collection")
+
+			total += c.Total
+			migrated += c.Migrated
+
+			if c.Done {
+				done++
+			}
+		}
+
+		logger.WithFields(l.BoolField("dryRun", report.DryRun), l.IntField("collections", len(report.Collections)),
+			l.IntField("doneCollections", done), l.Int64Field("total", total), l.Int64Field("migrated", migrated)).
+			Info("Report")
+	}
+
+	if err != nil {
+		logger.Fatal(err)
+	}
+}
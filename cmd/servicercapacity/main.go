@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/sgostarter/i/l"
+)
+
+func main() {
+	servicerID := flag.Uint64("servicer-id", 0, "the servicer user id")
+	maxTalks := flag.Int("max-talks", -1, "max concurrent talks of the servicer, 0 for no limit, show the current one if it's negative")
+	remove := flag.Bool("remove", false, "remove the override, Routing.MaxTalksPerServicer applies again")
+	flag.Parse()
+
+	cfg := config.GetConfig()
+
+	logger := cfg.Logger.WithFields(l.UInt64Field("servicerID", *servicerID))
+
+	if *servicerID == 0 {
+		logger.Fatal("NoServicerID")
+	}
+
+	ctx := context.Background()
+
+	m := model.NewServicerCapacityModel(cfg, "servicer_users", logger)
+
+	var err error
+
+	switch {
+	case *remove:
+		err = m.RemoveServicerMaxTalks(ctx, *servicerID)
+	case *maxTalks >= 0:
+		err = m.SetServicerMaxTalks(ctx, *servicerID, *maxTalks)
+	}
+
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	current, ok, err := m.GetServicerMaxTalks(ctx, *servicerID)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	logger.WithFields(l.BoolField("override", ok), l.IntField("maxTalks", current),
+		l.IntField("default", cfg.Routing.MaxTalksPerServicer)).Info("ServicerCapacity")
+}
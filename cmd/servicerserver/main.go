@@ -4,9 +4,9 @@ import (
 	"time"
 
 	"github.com/sbasestarter/bizinters/userinters"
-	"github.com/sbasestarter/bizmongolib/mongolib"
-	userpassauthenticator "github.com/sbasestarter/bizmongolib/user/authenticator/userpass"
 	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/gens/cannedresponsepb"
+	"github.com/sbasestarter/customer-service-be/gens/talksearchpb"
 	"github.com/sbasestarter/customer-service-be/internal/controller"
 	"github.com/sbasestarter/customer-service-be/internal/impls"
 	"github.com/sbasestarter/customer-service-be/internal/model"
@@ -49,29 +49,39 @@ func main() {
 		return
 	}
 
-	mongoCli, mongoOptions, err := mongolib.InitMongo(cfg.UserMongoDSN)
-	if err != nil {
-		logger.Fatal(err)
-
-		return
-	}
 	servicerUserCenter := userlib.NewUserCenter(cfg.ServicerTokenSecret, single.NewPolicy(userinters.AuthMethodNameUserPassword),
 		memorystatuscontroller.NewStatusController(), memoryauthingdatastorage.NewMemoryAuthingDataStorage(), logger)
-	serviceUserPassModel := userpassauthenticator.NewMongoUserPasswordModel(mongoCli, mongoOptions.Auth.AuthSource, "servicer_users", logger)
+	serviceUserPassModel := model.NewUserPasswordModel(cfg, "servicer_users", logger)
 	servicerManager := userpassmanager.NewManager(cfg.ServicerPasswordSecret, serviceUserPassModel)
 	servicerUserTokenHelper := impls.NewLocalServicerUserTokenHelper(servicerUserCenter, servicerManager)
 
-	modelEx := impls.NewModelEx(model.NewMongoModel(&cfg.MongoConfig, logger))
+	modelEx := impls.NewModelEx(model.NewModel(cfg, logger))
 	mdi := impls.NewServicerRabbitMQMDI(cfg.RabbitMQURL, modelEx, logger)
 
-	servicerMD := impls.NewServicerMD(mdi, logger)
+	routing, err := impls.NewServicerRouting(&cfg.Routing, model.NewServicerCapacityModel(cfg, "servicer_users", logger))
+	if err != nil {
+		logger.Fatal(err)
+
+		return
+	}
+
+	servicerMD := impls.NewServicerMDEx(mdi, routing, logger)
 
 	servicerController := controller.NewServicerController(servicerMD, modelEx, logger)
 
-	grpcServicerServer := server.NewServicerServer(servicerController, servicerUserTokenHelper, logger)
+	outboxRelay := impls.NewOutboxRelay(mdi, logger)
+	defer outboxRelay.StopAndWait()
+
+	grpcServicerServer := server.NewServicerServer(servicerController, modelEx, servicerUserTokenHelper, cfg.SupervisorIDs,
+		&cfg.TalkClassify, logger)
+	grpcCannedResponseServer := server.NewCannedResponseServer(model.NewCannedResponseModel(cfg, logger), modelEx,
+		servicerUserTokenHelper, cfg.SupervisorIDs, cfg.Routing.ServicerSkills, logger)
+	grpcTalkSearchServer := server.NewTalkSearchServer(modelEx, servicerUserTokenHelper, &cfg.TalkClassify, logger)
 
 	err = s.Start(func(s *grpc.Server) error {
 		customertalkpb.RegisterServiceTalkServiceServer(s, grpcServicerServer)
+		cannedresponsepb.RegisterCannedResponseServiceServer(s, grpcCannedResponseServer)
+		talksearchpb.RegisterTalkSearchServiceServer(s, grpcTalkSearchServer)
 
 		return nil
 	})
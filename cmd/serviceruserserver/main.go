@@ -4,10 +4,9 @@ import (
 	"time"
 
 	"github.com/sbasestarter/bizinters/userinters"
-	"github.com/sbasestarter/bizmongolib/mongolib"
-	userpassauthenticator "github.com/sbasestarter/bizmongolib/user/authenticator/userpass"
 	"github.com/sbasestarter/customer-service-be/config"
 	"github.com/sbasestarter/customer-service-be/internal/impls"
+	"github.com/sbasestarter/customer-service-be/internal/model"
 	"github.com/sbasestarter/customer-service-be/internal/server"
 	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
 	"github.com/sbasestarter/userlib"
@@ -47,16 +46,9 @@ func main() {
 		return
 	}
 
-	mongoCli, mongoOptions, err := mongolib.InitMongo(cfg.UserMongoDSN)
-	if err != nil {
-		logger.Fatal(err)
-
-		return
-	}
-
 	servicerUserCenter := userlib.NewUserCenter(cfg.ServicerTokenSecret, single.NewPolicy(userinters.AuthMethodNameUserPassword),
 		memorystatuscontroller.NewStatusController(), memoryauthingdatastorage.NewMemoryAuthingDataStorage(), logger)
-	serviceUserPassModel := userpassauthenticator.NewMongoUserPasswordModel(mongoCli, mongoOptions.Auth.AuthSource, "servicer_users", logger)
+	serviceUserPassModel := model.NewUserPasswordModel(cfg, "servicer_users", logger)
 	servicerManager := userpassmanager.NewManager(cfg.ServicerPasswordSecret, serviceUserPassModel)
 	servicerUserTokenHelper := impls.NewLocalServicerUserTokenHelper(servicerUserCenter, servicerManager)
 
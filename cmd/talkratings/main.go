@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/impls"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/sgostarter/i/l"
+)
+
+const dateLayout = "2006-01-02"
+
+func main() {
+	now := time.Now()
+
+	from := flag.String("from", now.AddDate(0, 0, -30).Format(dateLayout), "the first day of the report")
+	to := flag.String("to", now.Format(dateLayout), "the last day of the report")
+	period := flag.String("period", string(defs.TalkRatingPeriodDay), "day, week or month")
+	flag.Parse()
+
+	cfg := config.GetConfig()
+
+	logger := cfg.Logger
+
+	fromTime, err := time.ParseInLocation(dateLayout, *from, time.Local)
+	if err != nil {
+		logger.WithFields(l.ErrorField(err)).Fatal("InvalidFrom")
+	}
+
+	toTime, err := time.ParseInLocation(dateLayout, *to, time.Local)
+	if err != nil {
+		logger.WithFields(l.ErrorField(err)).Fatal("InvalidTo")
+	}
+
+	m := model.NewModel(cfg, logger)
+
+	talkInfos, err := m.GetRatedTalkInfos(context.Background(), fromTime.Unix(), toTime.AddDate(0, 0, 1).Unix())
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	stats, err := impls.AggregateTalkRatings(talkInfos, defs.TalkRatingPeriod(*period), time.Local)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	for _, stat := range stats {
+		logger.WithFields(l.StringField("period", time.Unix(stat.PeriodStart, 0).Format(dateLayout)),
+			l.UInt64Field("servicerID", stat.ServicerID), l.IntField("count", stat.Count),
+			l.StringField("average", fmt.Sprintf("%.2f", stat.Average())),
+			l.StringField("scores", fmt.Sprint(stat.ScoreCounts))).Info("TalkRatings")
+	}
+}
@@ -139,6 +139,8 @@ func wS(gRpcClient customertalkpb.CustomerTalkServiceClient, logger l.Wrapper) f
 			"token": kv["token"],
 		})
 
+		setClientContext(md, kv, r)
+
 		stream, err := gRpcClient.Talk(metadata.NewOutgoingContext(context.TODO(), md))
 		if err != nil {
 			logger.WithFields(l.ErrorField(err)).Error("GRPCServiceFailed")
@@ -152,6 +154,25 @@ func wS(gRpcClient customertalkpb.CustomerTalkServiceClient, logger l.Wrapper) f
 	}
 }
 
+// setClientContext passes where the customer talks from, the page itself knows its url and locale better than the
+// websocket handshake does.
+func setClientContext(md metadata.MD, kv map[string]string, r *http.Request) {
+	set := func(key, value, fallback string) {
+		if value == "" {
+			value = fallback
+		}
+
+		if value != "" {
+			md.Set(key, value)
+		}
+	}
+
+	set("client-page-url", kv["page_url"], r.Referer())
+	set("client-user-agent", r.UserAgent(), "")
+	set("client-locale", kv["locale"], r.Header.Get("Accept-Language"))
+	set("client-referrer", kv["referrer"], "")
+}
+
 func checkHandler(gRpcClient customertalkpb.CustomerUserServicerClient, logger l.Wrapper) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		md := metadata.New(map[string]string{
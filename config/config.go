@@ -20,7 +20,9 @@ type Config struct {
 	ServicerListen     string `yaml:"ServicerListen"`
 	ServicerUserListen string `yaml:"ServicerUserListen"`
 
+	ModelType   string      `yaml:"ModelType"` // mongo(default), memory, sqlite, postgres
 	MongoConfig MongoConfig `yaml:"MongoConfig"`
+	SQLDSN      string      `yaml:"SQLDSN"` // for sqlite and postgres
 	RabbitMQURL string      `yaml:"RabbitMQURL"`
 
 	UserMongoDSN string `yaml:"UserMongoDSN"`
@@ -28,8 +30,92 @@ type Config struct {
 	CustomerTokenSecret    string `yaml:"CustomerTokenSecret"`
 	ServicerTokenSecret    string `yaml:"ServicerTokenSecret"`
 	ServicerPasswordSecret string `yaml:"ServicerPasswordSecret"`
+
+	SupervisorIDs []uint64 `yaml:"SupervisorIDs"` // servicer user ids with supervisor rights
+
+	Routing RoutingConfig `yaml:"Routing"`
+
+	ServicerDisplay ServicerDisplayConfig `yaml:"ServicerDisplay"`
+
+	PreChat PreChatConfig `yaml:"PreChat"`
+
+	TalkClassify TalkClassifyConfig `yaml:"TalkClassify"`
+}
+
+type RoutingConfig struct {
+	// Strategy is one of roundRobin, leastActiveTalks, longestIdle and skillsMatch,
+	// new talks stay pending for the servicers to attach if it's empty.
+	Strategy string `yaml:"Strategy"`
+	// MaxTalksPerServicer limits the talks attached to a servicer, 0 for no limit,
+	// cmd/servicercapacity overrides it per servicer.
+	MaxTalksPerServicer int `yaml:"MaxTalksPerServicer"`
+	// ServicerSkills are the teams talks can be transferred to too.
+	ServicerSkills         map[uint64][]string `yaml:"ServicerSkills"`
+	TransferTimeoutSeconds int                 `yaml:"TransferTimeoutSeconds"` // 60 if not set
+}
+
+// ServicerDisplayConfig is how the servicers are shown to the customers.
+type ServicerDisplayConfig struct {
+	DefaultName   string                           `yaml:"DefaultName"` // 客服 if empty
+	DefaultAvatar string                           `yaml:"DefaultAvatar"`
+	Profiles      map[uint64]ServicerProfileConfig `yaml:"Profiles"` // servicer user id - profile
+}
+
+type ServicerProfileConfig struct {
+	Name   string `yaml:"Name"`
+	Avatar string `yaml:"Avatar"`
+}
+
+// PreChatConfig is the form the customers fill in on creating a talk.
+type PreChatConfig struct {
+	Fields []PreChatFieldConfig `yaml:"Fields"`
+	// MaxExtraFields limits the free-form fields besides Fields, 0 rejects them.
+	MaxExtraFields int `yaml:"MaxExtraFields"`
 }
 
+const (
+	PreChatFieldTypeText        = "text"
+	PreChatFieldTypeEmail       = "email"
+	PreChatFieldTypeOrderNumber = "orderNumber"
+	PreChatFieldTypeChoice      = "choice"
+)
+
+type PreChatFieldConfig struct {
+	Key      string   `yaml:"Key"`
+	Type     string   `yaml:"Type"` // text(default), email, orderNumber or choice
+	Required bool     `yaml:"Required"`
+	Choices  []string `yaml:"Choices"` // for choice
+	Pattern  string   `yaml:"Pattern"` // regexp the value must match, ^[A-Za-z0-9-]{4,64}$ for orderNumber if empty
+	MaxLen   int      `yaml:"MaxLen"`  // 256 if not set
+}
+
+// TalkClassifyConfig is how the servicers classify the talks, the tags are free-form.
+type TalkClassifyConfig struct {
+	Categories   []string                `yaml:"Categories"` // any category if empty
+	CustomFields []TalkCustomFieldConfig `yaml:"CustomFields"`
+}
+
+const (
+	TalkFieldTypeText   = "text"
+	TalkFieldTypeNumber = "number"
+	TalkFieldTypeBool   = "bool"
+	TalkFieldTypeDate   = "date" // YYYY-MM-DD
+	TalkFieldTypeChoice = "choice"
+)
+
+type TalkCustomFieldConfig struct {
+	Key     string   `yaml:"Key"`
+	Type    string   `yaml:"Type"`    // text(default), number, bool, date or choice
+	Choices []string `yaml:"Choices"` // for choice
+}
+
+const (
+	ModelTypeMongo    = "mongo"
+	ModelTypeMemory   = "memory"
+	ModelTypeSQLite   = "sqlite"
+	ModelTypePostgres = "postgres"
+)
+
 type MongoConfig struct {
 	Server   string `yaml:"Server"`
 	DB       string `yaml:"DB"`
@@ -7,6 +7,8 @@ require (
 	github.com/godruoyi/go-snowflake v0.0.1
 	github.com/golang/protobuf v1.5.2
 	github.com/gorilla/websocket v1.4.1
+	github.com/lib/pq v1.9.0
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/sbasestarter/bizinters v0.0.0-20221110133957-3b904f49ce7f
 	github.com/sbasestarter/bizmongolib v0.0.0-20221111041737-b64ad80f1a29
 	github.com/sbasestarter/customer-service-proto v0.0.8
@@ -6,12 +6,14 @@ import (
 	"github.com/sgostarter/libeasygo/commerr"
 )
 
-func NewCustomer(uniqueID uint64, talkID string, createTalkFlag bool, userID uint64, chSendMessage chan *customertalkpb.TalkResponse) defs.Customer {
+func NewCustomer(uniqueID uint64, talkID string, createTalkFlag bool, userID uint64, resumePoint *defs.ResumePoint,
+	chSendMessage chan *customertalkpb.TalkResponse) defs.Customer {
 	return &customerImpl{
 		uniqueID:       uniqueID,
 		talkID:         talkID,
 		createTalkFlag: createTalkFlag,
 		userID:         userID,
+		resumePoint:    resumePoint,
 		chSendMessage:  chSendMessage,
 	}
 }
@@ -21,6 +23,7 @@ type customerImpl struct {
 	talkID         string
 	createTalkFlag bool
 	userID         uint64
+	resumePoint    *defs.ResumePoint
 	chSendMessage  chan *customertalkpb.TalkResponse
 }
 
@@ -28,6 +31,10 @@ func (impl *customerImpl) CreateTalkFlag() bool {
 	return impl.createTalkFlag
 }
 
+func (impl *customerImpl) GetResumePoint() *defs.ResumePoint {
+	return impl.resumePoint
+}
+
 func (impl *customerImpl) GetUniqueID() uint64 {
 	return impl.uniqueID
 }
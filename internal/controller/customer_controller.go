@@ -35,6 +35,7 @@ func NewCustomerControllerEx(maxCache, maxMessageCache int, md defs.CustomerMD,
 		chUninstallCustomer: make(chan defs.Customer, maxCache),
 		chCustomerClose:     make(chan defs.Customer, maxCache),
 		chCustomerMessage:   make(chan *customerMessage, maxMessageCache),
+		chQueryMessages:     make(chan *customerQueryMessages, maxCache),
 		chMainRoutineRunner: make(chan func(), maxMessageCache),
 	}
 
@@ -49,6 +50,13 @@ type customerMessage struct {
 	message  *defs.TalkMessageW
 }
 
+type customerQueryMessages struct {
+	customer  defs.Customer
+	messageID string
+	before    bool
+	count     int64
+}
+
 type CustomerController struct {
 	md         defs.CustomerMD
 	m          defs.ModelEx
@@ -59,6 +67,7 @@ type CustomerController struct {
 	chUninstallCustomer chan defs.Customer
 	chCustomerMessage   chan *customerMessage
 	chCustomerClose     chan defs.Customer
+	chQueryMessages     chan *customerQueryMessages
 
 	chMainRoutineRunner chan func()
 }
@@ -133,6 +142,25 @@ func (c *CustomerController) CustomerMessageIncoming(customer defs.Customer, seq
 	return nil
 }
 
+func (c *CustomerController) CustomerQueryMessages(customer defs.Customer, messageID string, before bool, count int64) error {
+	if customer == nil {
+		return commerr.ErrInvalidArgument
+	}
+
+	select {
+	case c.chQueryMessages <- &customerQueryMessages{
+		customer:  customer,
+		messageID: messageID,
+		before:    before,
+		count:     count,
+	}:
+	default:
+		return commerr.ErrCanceled
+	}
+
+	return nil
+}
+
 func (c *CustomerController) init() {
 	c.md.Setup(c)
 	c.routineMan.StartRoutine(c.mainRoutine, "mainRoutine")
@@ -158,6 +186,8 @@ func (c *CustomerController) mainRoutine(ctx context.Context, exiting func() boo
 			md.CustomerMessageIncoming(ctx, msgD.customer, msgD.seqID, msgD.message)
 		case customer := <-c.chCustomerClose:
 			md.CustomerClose(ctx, customer)
+		case q := <-c.chQueryMessages:
+			md.CustomerQueryMessages(ctx, q.customer, q.messageID, q.before, q.count)
 		case runner := <-c.chMainRoutineRunner:
 			runner()
 		}
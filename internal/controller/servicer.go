@@ -1,23 +1,31 @@
 package controller
 
 import (
+	"sync"
+
 	"github.com/sbasestarter/customer-service-be/internal/defs"
 	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
 	"github.com/sgostarter/libeasygo/commerr"
 )
 
-func NewServicer(userID, uniqueID uint64, chSendMessage chan *customertalkpb.ServiceResponse) defs.Servicer {
+func NewServicer(userID, uniqueID uint64, supervisor bool, chSendMessage chan *customertalkpb.ServiceResponse) defs.Servicer {
 	return &servicerImpl{
 		userID:        userID,
 		uniqueID:      uniqueID,
+		supervisor:    supervisor,
 		chSendMessage: chSendMessage,
+		watchModes:    make(map[string]defs.TalkWatchMode),
 	}
 }
 
 type servicerImpl struct {
 	userID        uint64
 	uniqueID      uint64
+	supervisor    bool
 	chSendMessage chan *customertalkpb.ServiceResponse
+
+	watchModesLock sync.Mutex
+	watchModes     map[string]defs.TalkWatchMode // talkID - mode
 }
 
 func (impl *servicerImpl) GetUserID() uint64 {
@@ -28,6 +36,28 @@ func (impl *servicerImpl) GetUniqueID() uint64 {
 	return impl.uniqueID
 }
 
+func (impl *servicerImpl) IsSupervisor() bool {
+	return impl.supervisor
+}
+
+func (impl *servicerImpl) SetTalkWatchMode(talkID string, mode defs.TalkWatchMode) {
+	impl.watchModesLock.Lock()
+	defer impl.watchModesLock.Unlock()
+
+	if mode == defs.TalkWatchModeNone {
+		delete(impl.watchModes, talkID)
+	} else {
+		impl.watchModes[talkID] = mode
+	}
+}
+
+func (impl *servicerImpl) GetTalkWatchMode(talkID string) defs.TalkWatchMode {
+	impl.watchModesLock.Lock()
+	defer impl.watchModesLock.Unlock()
+
+	return impl.watchModes[talkID]
+}
+
 func (impl *servicerImpl) SendMessage(msg *customertalkpb.ServiceResponse) error {
 	select {
 	case impl.chSendMessage <- msg:
@@ -33,12 +33,21 @@ func NewServicerControllerEx(maxCache, maxMessageCache int, md defs.ServicerMD,
 		routineMan:                   routineman.NewRoutineMan(context.Background(), logger),
 		chInstallServicer:            make(chan defs.Servicer, maxCache),
 		chUninstallServicer:          make(chan defs.Servicer, maxCache),
-		chServicerAttachTalk:         make(chan *servicerWithTalk, maxCache),
+		chServicerAttachTalk:         make(chan *servicerAttachTalk, maxCache),
 		chServicerDetachTalk:         make(chan *servicerWithTalk, maxCache),
+		chServicerCloseTalk:          make(chan *servicerCloseTalk, maxCache),
+		chServicerTransferTalk:       make(chan *servicerTransferTalk, maxCache),
+		chServicerReplyTransfer:      make(chan *servicerReplyTransfer, maxCache),
+		chServicerWatchTalk:          make(chan *servicerWatchTalk, maxCache),
+		chServicerWhisper:            make(chan *servicerMessage, maxMessageCache),
+		chServicerClassifyTalk:       make(chan *servicerClassifyTalk, maxCache),
+		chServicerSetPresence:        make(chan *servicerSetPresence, maxCache),
+		chServicerQueryPresences:     make(chan defs.Servicer),
 		chServicerQueryAttachedTalks: make(chan defs.Servicer),
 		chServicerQueryPendingTalks:  make(chan defs.Servicer),
-		chServicerReloadTalk:         make(chan *servicerWithTalk, maxCache),
+		chServicerReloadTalk:         make(chan *servicerReloadTalk, maxCache),
 		chServicerMessage:            make(chan *servicerMessage, maxMessageCache),
+		chServicerQueryMessages:      make(chan *servicerQueryMessages, maxCache),
 		chMainRoutineRunner:          make(chan func(), maxMessageCache),
 	}
 
@@ -59,6 +68,63 @@ type servicerWithTalk struct {
 	servicer defs.Servicer
 }
 
+type servicerAttachTalk struct {
+	talkID   string
+	force    bool
+	servicer defs.Servicer
+}
+
+type servicerCloseTalk struct {
+	talkID      string
+	disposition string
+	note        string
+	servicer    defs.Servicer
+}
+
+type servicerTransferTalk struct {
+	talkID       string
+	toServicerID uint64
+	toTeam       string
+	reason       string
+	servicer     defs.Servicer
+}
+
+type servicerReplyTransfer struct {
+	talkID   string
+	accept   bool
+	servicer defs.Servicer
+}
+
+type servicerWatchTalk struct {
+	talkID   string
+	mode     defs.TalkWatchMode
+	servicer defs.Servicer
+}
+
+type servicerClassifyTalk struct {
+	classification *defs.TalkClassification
+	servicer       defs.Servicer
+}
+
+type servicerSetPresence struct {
+	presence defs.ServicerPresence
+	servicer defs.Servicer
+}
+
+type servicerReloadTalk struct {
+	talkID      string
+	resumePoint *defs.ResumePoint
+	servicer    defs.Servicer
+}
+
+type servicerQueryMessages struct {
+	servicer  defs.Servicer
+	talkID    string
+	messageID string
+	before    bool
+	count     int64
+}
+
 type ServicerController struct {
 	md         defs.ServicerMD
 	m          defs.ModelEx
@@ -67,12 +133,21 @@ type ServicerController struct {
 
 	chInstallServicer            chan defs.Servicer
 	chUninstallServicer          chan defs.Servicer
-	chServicerAttachTalk         chan *servicerWithTalk
+	chServicerAttachTalk         chan *servicerAttachTalk
 	chServicerDetachTalk         chan *servicerWithTalk
+	chServicerCloseTalk          chan *servicerCloseTalk
+	chServicerTransferTalk       chan *servicerTransferTalk
+	chServicerReplyTransfer      chan *servicerReplyTransfer
+	chServicerWatchTalk          chan *servicerWatchTalk
+	chServicerWhisper            chan *servicerMessage
+	chServicerClassifyTalk       chan *servicerClassifyTalk
+	chServicerSetPresence        chan *servicerSetPresence
+	chServicerQueryPresences     chan defs.Servicer
 	chServicerQueryAttachedTalks chan defs.Servicer
 	chServicerQueryPendingTalks  chan defs.Servicer
-	chServicerReloadTalk         chan *servicerWithTalk
+	chServicerReloadTalk         chan *servicerReloadTalk
 	chServicerMessage            chan *servicerMessage
+	chServicerQueryMessages      chan *servicerQueryMessages
 	chMainRoutineRunner          chan func()
 }
 
@@ -114,15 +189,16 @@ func (c *ServicerController) UninstallServicer(servicer defs.Servicer) error {
 	return nil
 }
 
-func (c *ServicerController) ServicerAttachTalk(servicer defs.Servicer, talkID string) error {
+func (c *ServicerController) ServicerAttachTalk(servicer defs.Servicer, talkID string, force bool) error {
 	if servicer == nil || talkID == "" {
 		return commerr.ErrInvalidArgument
 	}
 
 	select {
-	case c.chServicerAttachTalk <- &servicerWithTalk{
+	case c.chServicerAttachTalk <- &servicerAttachTalk{
 		servicer: servicer,
 		talkID:   talkID,
+		force:    force,
 	}:
 	default:
 		return commerr.ErrCanceled
@@ -148,6 +224,149 @@ func (c *ServicerController) ServicerDetachTalk(servicer defs.Servicer, talkID s
 	return nil
 }
 
+func (c *ServicerController) ServicerCloseTalk(servicer defs.Servicer, talkID, disposition, note string) error {
+	if servicer == nil || talkID == "" || disposition == "" {
+		return commerr.ErrInvalidArgument
+	}
+
+	select {
+	case c.chServicerCloseTalk <- &servicerCloseTalk{
+		servicer:    servicer,
+		talkID:      talkID,
+		disposition: disposition,
+		note:        note,
+	}:
+	default:
+		return commerr.ErrCanceled
+	}
+
+	return nil
+}
+
+// ServicerTransferTalk offers the talk to the servicer toServicerID, or to the team toTeam if toServicerID is 0.
+func (c *ServicerController) ServicerTransferTalk(servicer defs.Servicer, talkID string, toServicerID uint64,
+	toTeam, reason string) error {
+	if servicer == nil || talkID == "" || (toServicerID == 0) == (toTeam == "") {
+		return commerr.ErrInvalidArgument
+	}
+
+	select {
+	case c.chServicerTransferTalk <- &servicerTransferTalk{
+		servicer:     servicer,
+		talkID:       talkID,
+		toServicerID: toServicerID,
+		toTeam:       toTeam,
+		reason:       reason,
+	}:
+	default:
+		return commerr.ErrCanceled
+	}
+
+	return nil
+}
+
+func (c *ServicerController) ServicerReplyTalkTransfer(servicer defs.Servicer, talkID string, accept bool) error {
+	if servicer == nil || talkID == "" {
+		return commerr.ErrInvalidArgument
+	}
+
+	select {
+	case c.chServicerReplyTransfer <- &servicerReplyTransfer{
+		servicer: servicer,
+		talkID:   talkID,
+		accept:   accept,
+	}:
+	default:
+		return commerr.ErrCanceled
+	}
+
+	return nil
+}
+
+func (c *ServicerController) ServicerWatchTalk(servicer defs.Servicer, talkID string, mode defs.TalkWatchMode) error {
+	if servicer == nil || talkID == "" || !mode.Valid() {
+		return commerr.ErrInvalidArgument
+	}
+
+	select {
+	case c.chServicerWatchTalk <- &servicerWatchTalk{
+		servicer: servicer,
+		talkID:   talkID,
+		mode:     mode,
+	}:
+	default:
+		return commerr.ErrCanceled
+	}
+
+	return nil
+}
+
+func (c *ServicerController) ServicerWhisper(servicer defs.Servicer, talkID string, message *defs.TalkMessageW) error {
+	if servicer == nil || talkID == "" || message == nil {
+		return commerr.ErrInvalidArgument
+	}
+
+	select {
+	case c.chServicerWhisper <- &servicerMessage{
+		servicer: servicer,
+		talkID:   talkID,
+		message:  message,
+	}:
+	default:
+		return commerr.ErrCanceled
+	}
+
+	return nil
+}
+
+func (c *ServicerController) ServicerClassifyTalk(servicer defs.Servicer, classification *defs.TalkClassification) error {
+	if servicer == nil || classification == nil || classification.TalkID == "" {
+		return commerr.ErrInvalidArgument
+	}
+
+	select {
+	case c.chServicerClassifyTalk <- &servicerClassifyTalk{
+		servicer:       servicer,
+		classification: classification,
+	}:
+	default:
+		return commerr.ErrCanceled
+	}
+
+	return nil
+}
+
+func (c *ServicerController) ServicerSetPresence(servicer defs.Servicer, presence defs.ServicerPresence) error {
+	if servicer == nil || !presence.Valid() {
+		return commerr.ErrInvalidArgument
+	}
+
+	select {
+	case c.chServicerSetPresence <- &servicerSetPresence{
+		servicer: servicer,
+		presence: presence,
+	}:
+	default:
+		return commerr.ErrCanceled
+	}
+
+	return nil
+}
+
+func (c *ServicerController) ServicerQueryPresences(servicer defs.Servicer) error {
+	if servicer == nil {
+		return commerr.ErrInvalidArgument
+	}
+
+	select {
+	case c.chServicerQueryPresences <- servicer:
+	default:
+		return commerr.ErrCanceled
+	}
+
+	return nil
+}
+
 func (c *ServicerController) ServicerQueryAttachedTalks(servicer defs.Servicer) error {
 	if servicer == nil {
 		return commerr.ErrInvalidArgument
@@ -176,15 +395,16 @@ func (c *ServicerController) ServicerQueryPendingTalks(servicer defs.Servicer) e
 	return nil
 }
 
-func (c *ServicerController) ServicerReloadTalk(servicer defs.Servicer, talkID string) error {
+func (c *ServicerController) ServicerReloadTalk(servicer defs.Servicer, talkID string, resumePoint *defs.ResumePoint) error {
 	if servicer == nil {
 		return commerr.ErrInvalidArgument
 	}
 
 	select {
-	case c.chServicerReloadTalk <- &servicerWithTalk{
-		talkID:   talkID,
-		servicer: servicer,
+	case c.chServicerReloadTalk <- &servicerReloadTalk{
+		talkID:      talkID,
+		resumePoint: resumePoint,
+		servicer:    servicer,
 	}:
 	default:
 		return commerr.ErrCanceled
@@ -212,6 +432,26 @@ func (c *ServicerController) ServicerMessageIncoming(servicer defs.Servicer, seq
 	return nil
 }
 
+func (c *ServicerController) ServicerQueryMessages(servicer defs.Servicer, talkID, messageID string, before bool, count int64) error {
+	if servicer == nil || talkID == "" {
+		return commerr.ErrInvalidArgument
+	}
+
+	select {
+	case c.chServicerQueryMessages <- &servicerQueryMessages{
+		servicer:  servicer,
+		talkID:    talkID,
+		messageID: messageID,
+		before:    before,
+		count:     count,
+	}:
+	default:
+		return commerr.ErrCanceled
+	}
+
+	return nil
+}
+
 func (c *ServicerController) init() {
 	c.md.Setup(c)
 	c.routineMan.StartRoutine(c.mainRoutine, "mainRoutine")
@@ -234,17 +474,35 @@ func (c *ServicerController) mainRoutine(ctx context.Context, exiting func() boo
 		case servicer := <-c.chUninstallServicer:
 			md.UninstallServicer(ctx, servicer)
 		case at := <-c.chServicerAttachTalk:
-			md.ServicerAttachTalk(ctx, at.talkID, at.servicer)
+			md.ServicerAttachTalk(ctx, at.talkID, at.servicer, at.force)
 		case at := <-c.chServicerDetachTalk:
 			md.ServicerDetachTalk(ctx, at.talkID, at.servicer)
+		case ct := <-c.chServicerCloseTalk:
+			md.ServicerCloseTalk(ctx, ct.servicer, ct.talkID, ct.disposition, ct.note)
+		case tt := <-c.chServicerTransferTalk:
+			md.ServicerTransferTalk(ctx, tt.servicer, tt.talkID, tt.toServicerID, tt.toTeam, tt.reason)
+		case rt := <-c.chServicerReplyTransfer:
+			md.ServicerReplyTalkTransfer(ctx, rt.servicer, rt.talkID, rt.accept)
+		case wt := <-c.chServicerWatchTalk:
+			md.ServicerWatchTalk(ctx, wt.servicer, wt.talkID, wt.mode)
+		case msgD := <-c.chServicerWhisper:
+			md.ServicerWhisper(ctx, msgD.servicer, msgD.talkID, msgD.message)
+		case ct := <-c.chServicerClassifyTalk:
+			md.ServicerClassifyTalk(ctx, ct.servicer, ct.classification)
+		case sp := <-c.chServicerSetPresence:
+			md.ServicerSetPresence(ctx, sp.servicer, sp.presence)
+		case servicer := <-c.chServicerQueryPresences:
+			md.ServicerQueryPresences(ctx, servicer)
 		case servicer := <-c.chServicerQueryAttachedTalks:
 			md.ServicerQueryAttachedTalks(ctx, servicer)
 		case servicer := <-c.chServicerQueryPendingTalks:
 			md.ServicerQueryPendingTalks(ctx, servicer)
 		case at := <-c.chServicerReloadTalk:
-			md.ServicerReloadTalk(ctx, at.servicer, at.talkID)
+			md.ServicerReloadTalk(ctx, at.servicer, at.talkID, at.resumePoint)
 		case msgD := <-c.chServicerMessage:
 			md.ServiceMessage(ctx, msgD.servicer, msgD.talkID, msgD.seqID, msgD.message)
+		case q := <-c.chServicerQueryMessages:
+			md.ServicerQueryMessages(ctx, q.servicer, q.talkID, q.messageID, q.before, q.count)
 		case runner := <-c.chMainRoutineRunner:
 			runner()
 		}
@@ -0,0 +1,32 @@
+package defs
+
+import "strings"
+
+// CannedResponse is a snippet a servicer replies with, Text may hold the placeholders
+// {{customer_name}} and {{talk_title}}.
+type CannedResponse struct {
+	ID        string `bson:"_id"`
+	OwnerID   uint64 `bson:"OwnerID"` // 0 for a response shared by Team
+	Team      string `bson:"Team"`
+	Shortcut  string `bson:"Shortcut"`
+	Title     string `bson:"Title"`
+	Text      string `bson:"Text"`
+	UpdatedAt int64  `bson:"UpdatedAt"`
+}
+
+// Shared responses are the ones of a team.
+func (response *CannedResponse) Shared() bool {
+	return response.OwnerID == 0
+}
+
+// Render resolves the placeholders with the talk, unknown placeholders are kept.
+func (response *CannedResponse) Render(talkInfo *TalkInfoR) string {
+	if talkInfo == nil {
+		return response.Text
+	}
+
+	return strings.NewReplacer(
+		"{{customer_name}}", talkInfo.CreatorUserName,
+		"{{talk_title}}", talkInfo.Title,
+	).Replace(response.Text)
+}
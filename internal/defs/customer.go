@@ -2,6 +2,12 @@ package defs
 
 import "github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
 
+// ResumePoint is the last message a reconnecting client already has.
+type ResumePoint struct {
+	MessageID string
+	At        int64
+}
+
 type Customer interface {
 	GetUniqueID() uint64
 	GetTalkID() string
@@ -10,4 +16,5 @@ type Customer interface {
 	Remove(msg string)
 
 	CreateTalkFlag() bool
+	GetResumePoint() *ResumePoint
 }
@@ -15,18 +15,40 @@ type CustomerMD interface {
 	CustomerMessageIncoming(ctx context.Context, customer Customer,
 		seqID uint64, message *TalkMessageW)
 	CustomerClose(ctx context.Context, customer Customer)
+	CustomerQueryMessages(ctx context.Context, customer Customer, messageID string, before bool, count int64)
 }
 
 type ServicerMD interface {
 	Setup(mr MainRoutineRunner)
 	InstallServicer(ctx context.Context, servicer Servicer)
 	UninstallServicer(ctx context.Context, servicer Servicer)
-	ServicerAttachTalk(ctx context.Context, talkID string, servicer Servicer)
+	// ServicerAttachTalk claims the talk if no servicer has it, force takes it over from the current servicer.
+	ServicerAttachTalk(ctx context.Context, talkID string, servicer Servicer, force bool)
 	ServicerDetachTalk(ctx context.Context, talkID string, servicer Servicer)
+	// ServicerCloseTalk resolves the talk attached to servicer with the wrap-up disposition and note.
+	ServicerCloseTalk(ctx context.Context, servicer Servicer, talkID, disposition, note string)
+	// ServicerTransferTalk offers the talk attached to servicer to the servicer toServicerID,
+	// or to the servicers in toTeam if toServicerID is 0.
+	ServicerTransferTalk(ctx context.Context, servicer Servicer, talkID string, toServicerID uint64, toTeam, reason string)
+	ServicerReplyTalkTransfer(ctx context.Context, servicer Servicer, talkID string, accept bool)
+	// ServicerWatchTalk lets the supervisor monitor or barge in the talk, TalkWatchModeNone stops watching it.
+	// Talks not opened can only be monitored, finished ones not at all.
+	ServicerWatchTalk(ctx context.Context, servicer Servicer, talkID string, mode TalkWatchMode)
+	// ServicerWhisper sends the message of the supervisor watching the talk to its servicers only.
+	ServicerWhisper(ctx context.Context, servicer Servicer, talkID string, message *TalkMessageW)
+	// ServicerClassifyTalk changes the tags, the category and the custom fields of the talk attached to servicer,
+	// the supervisors may classify any talk.
+	ServicerClassifyTalk(ctx context.Context, servicer Servicer, classification *TalkClassification)
+	// ServicerSetPresence applies to all the sessions of the servicer on every instance.
+	ServicerSetPresence(ctx context.Context, servicer Servicer, presence ServicerPresence)
+	// ServicerQueryPresences is for supervisors only.
+	ServicerQueryPresences(ctx context.Context, servicer Servicer)
 	ServicerQueryAttachedTalks(ctx context.Context, servicer Servicer)
 	ServicerQueryPendingTalks(ctx context.Context, servicer Servicer)
-	ServicerReloadTalk(ctx context.Context, servicer Servicer, talkID string)
+	// ServicerReloadTalk sends only the messages after resumePoint if it's not nil and the gap is small enough.
+	ServicerReloadTalk(ctx context.Context, servicer Servicer, talkID string, resumePoint *ResumePoint)
 	ServiceMessage(ctx context.Context, servicer Servicer, talkID string, seqID uint64, message *TalkMessageW)
+	ServicerQueryMessages(ctx context.Context, servicer Servicer, talkID, messageID string, before bool, count int64)
 }
 
 type MD interface {
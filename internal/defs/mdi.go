@@ -5,6 +5,10 @@ import "context"
 type CustomerObserver interface {
 	OnMessageIncoming(senderUniqueID uint64, talkID string, message *TalkMessageW)
 	OnTalkClose(talkID string)
+
+	// OnServicerAttachMessage fromServicerID is the servicer the talk is transferred from, 0 for a plain attach.
+	OnServicerAttachMessage(talkID string, servicerID, fromServicerID uint64)
+	OnServicerDetachMessage(talkID string, servicerID uint64)
 }
 
 type ServicerObserver interface {
@@ -13,8 +17,15 @@ type ServicerObserver interface {
 	OnTalkCreate(talkID string)
 	OnTalkClose(talkID string)
 
-	OnServicerAttachMessage(talkID string, servicerID uint64)
+	// OnServicerAttachMessage fromServicerID is the servicer the talk is transferred from, 0 for a plain attach.
+	OnServicerAttachMessage(talkID string, servicerID, fromServicerID uint64)
 	OnServicerDetachMessage(talkID string, servicerID uint64)
+	// OnServicerPresenceMessage disconnected is true if an instance lost the last session of the servicer.
+	OnServicerPresenceMessage(servicerID uint64, presence ServicerPresence, disconnected bool)
+	OnTalkTransferMessage(transfer *TalkTransfer)
+	// OnWhisperMessage is never delivered to the customers.
+	OnWhisperMessage(talkID string, message *TalkMessageW)
+	OnTalkClassifiedMessage(classified *TalkClassified)
 }
 
 type Observer interface {
@@ -43,8 +54,13 @@ type CustomerMDI interface {
 type ServicerMDI interface {
 	MDIBase
 	SetServicerObserver(ob ServicerObserver)
-	SendServicerAttachMessage(talkID string, servicerID uint64)
+	SendServicerAttachMessage(talkID string, servicerID, fromServicerID uint64)
 	SendServiceDetachMessage(talkID string, servicerID uint64)
+	SendTalkCloseMessage(talkID string)
+	SendServicerPresenceMessage(servicerID uint64, presence ServicerPresence, disconnected bool)
+	SendTalkTransferMessage(transfer *TalkTransfer)
+	SendWhisperMessage(talkID string, message *TalkMessageW)
+	SendTalkClassifiedMessage(classified *TalkClassified)
 }
 
 type MDI interface {
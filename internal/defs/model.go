@@ -6,16 +6,63 @@ import (
 
 type Model interface {
 	CreateTalk(ctx context.Context, talkInfo *TalkInfoW) (talkID string, err error)
+	// OpenTalk reopens a resolved or closed talk, it's a no-op on the other unfinished talks.
 	OpenTalk(ctx context.Context, talkID string) (err error)
 	CloseTalk(ctx context.Context, talkID string) error
+	// UpdateTalkStatus moves the talk to status and stamps the transition time, ErrInvalidTalkTransition is returned
+	// if the current status can't move to status.
+	UpdateTalkStatus(ctx context.Context, talkID string, status TalkStatus) (err error)
+	// ResolveTalk moves the talk to resolved with the wrap-up disposition and note.
+	ResolveTalk(ctx context.Context, talkID string, disposition, note string) (err error)
+	// RateTalk stores the customer rating of a closed talk, commerr.ErrAlreadyExists is returned if it's rated already,
+	// ErrInvalidTalkTransition if it's still opened.
+	RateTalk(ctx context.Context, talkID string, score int, comment string) (err error)
+	// ClassifyTalk applies the classification to the talk, talkInfo is the talk after it.
+	ClassifyTalk(ctx context.Context, classification *TalkClassification) (talkInfo *TalkInfoR, err error)
 
+	// AddTalkMessage assigns the next sequence number of the talk to message.Seq atomically and stores the message,
+	// together with a pending outbox entry which is removed by AckOutboxMessage once the message is fanned out.
+	// commerr.ErrAlreadyExists is returned if the sender stored a message with the same ClientSeqID in the talk already,
+	// message is the stored one then.
 	AddTalkMessage(ctx context.Context, talkID string, message *TalkMessageW) (err error)
 	GetTalkMessages(ctx context.Context, talkID string, offset, count int64) (messages []*TalkMessageR, err error)
+	// GetTalkMessagesByCursor returns at most count messages matching filter right before (or after) messageID
+	// in ascending order, an empty messageID stands for the end (or the start) of the talk.
+	GetTalkMessagesByCursor(ctx context.Context, talkID, messageID string, before bool, count int64,
+		filter *TalkMessageFilter) (messages []*TalkMessageR, err error)
+	// GetTalkMessagesSince returns at most count messages matching filter sent at or after at in ascending order.
+	GetTalkMessagesSince(ctx context.Context, talkID string, at int64, count int64,
+		filter *TalkMessageFilter) (messages []*TalkMessageR, err error)
 
+	// QueryTalks filter nil matches any talk.
 	QueryTalks(ctx context.Context, creatorID, serviceID uint64, talkID string,
-		statuses []TalkStatus) (talks []*TalkInfoR, err error)
+		statuses []TalkStatus, filter *TalkFilter) (talks []*TalkInfoR, err error)
+	// SearchTalks returns at most search.Limit talks after search.After in search.Sort order.
+	SearchTalks(ctx context.Context, search *TalkSearch) (talks []*TalkInfoR, err error)
 	GetPendingTalkInfos(ctx context.Context) ([]*TalkInfoR, error)
+	// GetRatedTalkInfos returns the talks rated in [from, to), both in unix seconds.
+	GetRatedTalkInfos(ctx context.Context, from, to int64) ([]*TalkInfoR, error)
 	UpdateTalkServiceID(ctx context.Context, talkID string, serviceID uint64) (err error)
+	// CompareAndSetTalkServiceID sets the service ID to serviceID only if it's expectedServiceID now, curServiceID is
+	// the service ID after the call.
+	CompareAndSetTalkServiceID(ctx context.Context, talkID string, expectedServiceID, serviceID uint64) (swapped bool,
+		curServiceID uint64, err error)
+
+	// OfferTalkTransfer stores the pending transfer offer of the talk, commerr.ErrAlreadyExists is returned if the talk
+	// has one already.
+	OfferTalkTransfer(ctx context.Context, transfer *TalkTransfer) (err error)
+	// GetTalkTransfer returns the pending transfer offer of the talk, commerr.ErrNotFound if there's none.
+	GetTalkTransfer(ctx context.Context, talkID string) (transfer *TalkTransfer, err error)
+	// RemoveTalkTransfer removes the pending transfer offer of the talk expiring at expireAt, removed is false if it's
+	// resolved already, so every offer is resolved once across the instances.
+	RemoveTalkTransfer(ctx context.Context, talkID string, expireAt int64) (removed bool, err error)
+	// GetExpiredTalkTransfers returns at most count pending transfer offers expired at now, in unix milliseconds.
+	GetExpiredTalkTransfers(ctx context.Context, now int64, count int64) (transfers []*TalkTransfer, err error)
+
+	// LeaseOutboxMessages returns at most count messages not fanned out yet whose lease expired at now,
+	// and extends their lease to leaseUntil, both in unix milliseconds.
+	LeaseOutboxMessages(ctx context.Context, now, leaseUntil int64, count int64) (messages []*OutboxMessage, err error)
+	AckOutboxMessage(ctx context.Context, talkID string, seq uint64) (err error)
 }
 
 type ModelEx interface {
@@ -26,3 +73,25 @@ type ModelEx interface {
 	GetServicerTalkInfos(ctx context.Context, servicerID uint64) ([]*TalkInfoR, error)
 	GetTalkServicerID(ctx context.Context, talkID string) (servicerID uint64, err error)
 }
+
+// ServicerCapacityModel keeps the per servicer overrides of the max concurrent talks, next to the servicer accounts.
+type ServicerCapacityModel interface {
+	// GetServicerMaxTalks ok is false if the servicer has no override.
+	GetServicerMaxTalks(ctx context.Context, servicerID uint64) (maxTalks int, ok bool, err error)
+	// SetServicerMaxTalks maxTalks 0 for no limit.
+	SetServicerMaxTalks(ctx context.Context, servicerID uint64, maxTalks int) (err error)
+	RemoveServicerMaxTalks(ctx context.Context, servicerID uint64) (err error)
+}
+
+// CannedResponseModel keeps the snippets the servicers reply with.
+type CannedResponseModel interface {
+	// CreateCannedResponse fills response.ID, commerr.ErrAlreadyExists is returned if the owner
+	// (the team for a shared one) has the shortcut already.
+	CreateCannedResponse(ctx context.Context, response *CannedResponse) (err error)
+	// UpdateCannedResponse updates the shortcut, title, text and update time of the response with response.ID.
+	UpdateCannedResponse(ctx context.Context, response *CannedResponse) (err error)
+	DeleteCannedResponse(ctx context.Context, id string) (err error)
+	GetCannedResponse(ctx context.Context, id string) (response *CannedResponse, err error)
+	// ListCannedResponses returns the personal responses of ownerID and the ones shared by teams, ordered by shortcut.
+	ListCannedResponses(ctx context.Context, ownerID uint64, teams []string) (responses []*CannedResponse, err error)
+}
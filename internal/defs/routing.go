@@ -0,0 +1,14 @@
+package defs
+
+// RoutingCandidate is an online servicer below capacity a new talk may be assigned to.
+type RoutingCandidate struct {
+	ServicerID  uint64
+	ActiveTalks int
+	IdleSince   int64 // unix milliseconds of the last assignment, or of coming online
+	Skills      []string
+}
+
+type RoutingStrategy interface {
+	// Pick returns the candidate to assign talkInfo to, nil keeps the talk pending.
+	Pick(talkInfo *TalkInfoR, candidates []*RoutingCandidate) *RoutingCandidate
+}
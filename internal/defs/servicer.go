@@ -5,6 +5,50 @@ import "github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
 type Servicer interface {
 	GetUserID() uint64
 	GetUniqueID() uint64
+	IsSupervisor() bool
 	SendMessage(msg *customertalkpb.ServiceResponse) error
 	Remove(msg string)
+	// SetTalkWatchMode and GetTalkWatchMode are safe for concurrent use, the server checks the mode of barging supervisors.
+	SetTalkWatchMode(talkID string, mode TalkWatchMode)
+	GetTalkWatchMode(talkID string) TalkWatchMode
+}
+
+// TalkWatchMode is how a supervisor session takes part in a talk not attached to it.
+type TalkWatchMode int
+
+const (
+	TalkWatchModeNone TalkWatchMode = iota
+	// TalkWatchModeMonitor receives the messages of the talk read-only, whispers to the attached servicer are allowed.
+	TalkWatchModeMonitor
+	// TalkWatchModeBarge sends messages to the customer too, as a second visible servicer.
+	TalkWatchModeBarge
+)
+
+func (mode TalkWatchMode) Valid() bool {
+	return mode >= TalkWatchModeNone && mode <= TalkWatchModeBarge
+}
+
+// ServicerProfile is how a servicer is shown to the customers.
+type ServicerProfile struct {
+	Name   string
+	Avatar string // url
+}
+
+type ServicerProfiles interface {
+	GetServicerProfile(servicerID uint64) ServicerProfile
+}
+
+// ServicerPresence is the availability a servicer announces, only online servicers get new talks.
+type ServicerPresence int
+
+const (
+	// ServicerPresenceOffline is also the presence of a servicer having no session.
+	ServicerPresenceOffline ServicerPresence = iota
+	ServicerPresenceOnline
+	ServicerPresenceAway
+	ServicerPresenceBusy
+)
+
+func (presence ServicerPresence) Valid() bool {
+	return presence >= ServicerPresenceOffline && presence <= ServicerPresenceBusy
 }
@@ -0,0 +1,140 @@
+package defs
+
+import (
+	"sort"
+	"strings"
+)
+
+// TalkMaxTags limits the tags on a talk.
+const TalkMaxTags = 20
+
+// TalkClassification changes the tags, the category and the custom fields of a talk, the tags are removed
+// after added.
+type TalkClassification struct {
+	TalkID     string
+	AddTags    []string
+	RemoveTags []string
+	// Category nil keeps the category, empty clears it.
+	Category *string
+	// CustomFields empty values remove the fields.
+	CustomFields map[string]string
+}
+
+func (classification *TalkClassification) Empty() bool {
+	return len(classification.AddTags) == 0 && len(classification.RemoveTags) == 0 && classification.Category == nil &&
+		len(classification.CustomFields) == 0
+}
+
+// TalkClassified is the classification of a talk after servicerID changed it.
+type TalkClassified struct {
+	TalkID       string
+	ServicerID   uint64
+	Tags         []string
+	Category     string
+	CustomFields map[string]string
+}
+
+// TalkFilter narrows QueryTalks down, the zero values match any talk.
+type TalkFilter struct {
+	Title        string   // the talks with the title containing it, case-insensitively
+	Tags         []string // the talks with all the tags
+	Category     string
+	CustomFields map[string]string // the talks with all the fields equal
+	StartAtFrom  int64             // unix seconds, inclusive
+	StartAtTo    int64             // unix seconds, exclusive
+}
+
+func (filter *TalkFilter) Match(talkInfo *TalkInfoR) bool {
+	if filter == nil {
+		return true
+	}
+
+	if filter.Category != "" && talkInfo.Category != filter.Category {
+		return false
+	}
+
+	if filter.Title != "" && !strings.Contains(strings.ToLower(talkInfo.Title), strings.ToLower(filter.Title)) {
+		return false
+	}
+
+	if filter.StartAtFrom > 0 && talkInfo.StartAt < filter.StartAtFrom ||
+		filter.StartAtTo > 0 && talkInfo.StartAt >= filter.StartAtTo {
+		return false
+	}
+
+	for key, value := range filter.CustomFields {
+		if v, ok := talkInfo.CustomFields[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	for _, tag := range filter.Tags {
+		if !talkInfo.HasTag(tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (talkInfo *TalkInfoW) HasTag(tag string) bool {
+	idx := sort.SearchStrings(talkInfo.Tags, tag)
+
+	return idx < len(talkInfo.Tags) && talkInfo.Tags[idx] == tag
+}
+
+// Classify applies classification to copies of the tags and the custom fields, which may be shared with other
+// copies of talkInfo.
+func (talkInfo *TalkInfoW) Classify(classification *TalkClassification) {
+	tagSet := make(map[string]bool, len(talkInfo.Tags)+len(classification.AddTags))
+
+	for _, tag := range talkInfo.Tags {
+		tagSet[tag] = true
+	}
+
+	for _, tag := range classification.AddTags {
+		tagSet[tag] = true
+	}
+
+	for _, tag := range classification.RemoveTags {
+		delete(tagSet, tag)
+	}
+
+	var tags []string
+
+	for tag := range tagSet {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+
+	talkInfo.Tags = tags
+
+	if classification.Category != nil {
+		talkInfo.Category = *classification.Category
+	}
+
+	if len(classification.CustomFields) == 0 {
+		return
+	}
+
+	customFields := make(map[string]string, len(talkInfo.CustomFields)+len(classification.CustomFields))
+
+	for key, value := range talkInfo.CustomFields {
+		customFields[key] = value
+	}
+
+	for key, value := range classification.CustomFields {
+		if value == "" {
+			delete(customFields, key)
+		} else {
+			customFields[key] = value
+		}
+	}
+
+	if len(customFields) == 0 {
+		customFields = nil
+	}
+
+	talkInfo.CustomFields = customFields
+}
@@ -0,0 +1,7 @@
+package defs
+
+// TalkExport is a talk with all its messages, the notes included.
+type TalkExport struct {
+	TalkInfo *TalkInfoR
+	Messages []*TalkMessageR
+}
@@ -1,21 +1,113 @@
 package defs
 
+import "errors"
+
 type TalkStatus int
 
 const (
 	TalkStatusNone TalkStatus = iota
-	TalkStatusOpened
+	// TalkStatusQueued is stored as opened by earlier versions.
+	TalkStatusQueued
+	// TalkStatusClosed may be reopened by the customer like the talks were before the lifecycle statuses.
 	TalkStatusClosed
+	TalkStatusAssigned
+	TalkStatusWaitingOnCustomer
+	// TalkStatusResolved may be reopened by the customer.
+	TalkStatusResolved
+	TalkStatusAbandoned
 )
 
+var ErrInvalidTalkTransition = errors.New("invalidTalkTransition")
+
+// TalkStatusesOpened are the statuses messages are accepted in.
+var TalkStatusesOpened = []TalkStatus{
+	TalkStatusQueued,
+	TalkStatusAssigned,
+	TalkStatusWaitingOnCustomer,
+}
+
+// TalkStatusesClosed are the statuses a talk is closed in.
+var TalkStatusesClosed = []TalkStatus{
+	TalkStatusResolved,
+	TalkStatusClosed,
+	TalkStatusAbandoned,
+}
+
+// TalkStatusesReopenable are the statuses the customer reopens a talk from, OpenTalk is the only way out of them
+// to an opened status.
+var TalkStatusesReopenable = []TalkStatus{
+	TalkStatusResolved,
+	TalkStatusClosed,
+}
+
+var talkStatusTransitions = map[TalkStatus][]TalkStatus{
+	TalkStatusQueued:            {TalkStatusAssigned, TalkStatusWaitingOnCustomer},
+	TalkStatusAssigned:          {TalkStatusQueued, TalkStatusAssigned, TalkStatusWaitingOnCustomer},
+	TalkStatusWaitingOnCustomer: {TalkStatusQueued, TalkStatusAssigned},
+	TalkStatusResolved:          {TalkStatusAssigned, TalkStatusWaitingOnCustomer},
+	TalkStatusClosed:            {TalkStatusQueued, TalkStatusAssigned, TalkStatusWaitingOnCustomer, TalkStatusResolved},
+	TalkStatusAbandoned:         {TalkStatusQueued},
+}
+
+// TalkStatusesTransitTo returns the statuses a talk may move to status from.
+func TalkStatusesTransitTo(status TalkStatus) []TalkStatus {
+	return talkStatusTransitions[status]
+}
+
+func (status TalkStatus) Opened() bool {
+	return status == TalkStatusQueued || status == TalkStatusAssigned || status == TalkStatusWaitingOnCustomer
+}
+
+func (status TalkStatus) Finished() bool {
+	return status == TalkStatusClosed || status == TalkStatusAbandoned
+}
+
 type TalkInfoW struct {
 	Status          TalkStatus `bson:"Status"`
 	Title           string     `bson:"Title"`
 	StartAt         int64      `bson:"StartAt"`
-	FinishedAt      int64      `bson:"FinishedAt"`
+	FinishedAt      int64      `bson:"FinishedAt"` // resolved, closed or abandoned at
 	CreatorID       uint64     `bson:"CreatorID"`
 	ServiceID       uint64     `bson:"ServiceID"`
 	CreatorUserName string     `bson:"CreatorUserName"`
+	QueuedAt        int64      `bson:"QueuedAt,omitempty"`
+	FirstAssignedAt int64      `bson:"FirstAssignedAt,omitempty"`
+	FirstResponseAt int64      `bson:"FirstResponseAt,omitempty"`
+	Disposition     string     `bson:"Disposition,omitempty"` // wrap-up code recorded when a servicer resolves the talk
+	WrapUpNote      string     `bson:"WrapUpNote,omitempty"`
+	Skill           string     `bson:"Skill,omitempty"`       // the servicer skill the talk is routed by
+	RatingScore     int        `bson:"RatingScore,omitempty"` // 1 to 5 by the customer after the talk is finished or resolved
+	RatingComment   string     `bson:"RatingComment,omitempty"`
+	RatedAt         int64      `bson:"RatedAt,omitempty"`
+	// FormFields are the pre-chat form the customer filled in on creating the talk.
+	FormFields    map[string]string `bson:"FormFields,omitempty"`
+	ClientContext TalkClientContext `bson:"ClientContext,omitempty"`
+	// Tags are sorted, the custom field values are in the canonical form of their configured types.
+	Tags         []string          `bson:"Tags,omitempty"`
+	Category     string            `bson:"Category,omitempty"`
+	CustomFields map[string]string `bson:"CustomFields,omitempty"`
+}
+
+// TalkClientContext is where the customer created the talk from, passed by the ws gateway.
+type TalkClientContext struct {
+	PageURL   string `bson:"PageURL,omitempty"`
+	UserAgent string `bson:"UserAgent,omitempty"`
+	Locale    string `bson:"Locale,omitempty"`
+	Referrer  string `bson:"Referrer,omitempty"`
+}
+
+func (clientContext TalkClientContext) IsZero() bool {
+	return clientContext == TalkClientContext{}
+}
+
+const (
+	TalkRatingScoreMin = 1
+	TalkRatingScoreMax = 5
+)
+
+// Rated talks are rated once only.
+func (talkInfo *TalkInfoW) Rated() bool {
+	return talkInfo.RatedAt > 0
 }
 
 type TalkInfoR struct {
@@ -6,19 +6,102 @@ const (
 	TalkMessageTypeUnknown TalkMessageType = iota
 	TalkMessageTypeText
 	TalkMessageTypeImage
+	// TalkMessageTypeSystem is written by the service, Text is the TalkSystemEvent and SenderID the servicer.
+	TalkMessageTypeSystem
+	// TalkMessageTypeNote is an internal note in Text, for the servicers only.
+	TalkMessageTypeNote
+)
+
+type TalkSystemEvent string
+
+const (
+	TalkSystemEventServicerJoined  TalkSystemEvent = "servicerJoined"
+	TalkSystemEventServicerLeft    TalkSystemEvent = "servicerLeft"
+	TalkSystemEventTalkTransferred TalkSystemEvent = "talkTransferred" // SenderID is the servicer taking over
+	// TalkSystemEventTransferDeclined and the other transfer results are kept from the customers.
+	TalkSystemEventTransferDeclined TalkSystemEvent = "transferDeclined" // SenderID is the servicer declining
+	TalkSystemEventTransferExpired  TalkSystemEvent = "transferExpired"  // SenderID is the servicer offering
+	TalkSystemEventTransferCanceled TalkSystemEvent = "transferCanceled" // SenderID is the servicer accepting
 )
 
 type TalkMessageW struct {
-	At              int64           `bson:"At"`
+	Seq             uint64          `bson:"Seq"` // assigned by the model, strictly increasing in a talk
+	At              int64           `bson:"At"`  // unix milliseconds
 	CustomerMessage bool            `bson:"CustomerMessage"`
 	Type            TalkMessageType `bson:"Type"`
 	SenderID        uint64          `bson:"SenderID"`
 	SenderUserName  string          `bson:"SenderUserName"`
 	Text            string          `bson:"Text,omitempty"`
 	Data            []byte          `bson:"Data,omitempty"`
+	// ClientSeqID is the SeqId the sender submitted the message with, 0 if none.
+	ClientSeqID uint64 `bson:"ClientSeqID,omitempty"`
+}
+
+// VisibleToCustomer is false for the messages kept from the customers, they're not delivered or queried by them.
+func (message *TalkMessageW) VisibleToCustomer() bool {
+	return CustomerTalkMessageFilter().Match(message)
+}
+
+// TalkMessageFilter narrows the message queries down, nil matches any message.
+type TalkMessageFilter struct {
+	ExcludedTypes        []TalkMessageType
+	ExcludedSystemEvents []TalkSystemEvent
+}
+
+// CustomerTalkMessageFilter matches the messages VisibleToCustomer.
+func CustomerTalkMessageFilter() *TalkMessageFilter {
+	return &TalkMessageFilter{
+		ExcludedTypes: []TalkMessageType{TalkMessageTypeNote},
+		ExcludedSystemEvents: []TalkSystemEvent{TalkSystemEventTransferDeclined, TalkSystemEventTransferExpired,
+			TalkSystemEventTransferCanceled},
+	}
+}
+
+func (filter *TalkMessageFilter) Match(message *TalkMessageW) bool {
+	if filter == nil {
+		return true
+	}
+
+	for _, messageType := range filter.ExcludedTypes {
+		if message.Type == messageType {
+			return false
+		}
+	}
+
+	if message.Type != TalkMessageTypeSystem {
+		return true
+	}
+
+	for _, event := range filter.ExcludedSystemEvents {
+		if TalkSystemEvent(message.Text) == event {
+			return false
+		}
+	}
+
+	return true
 }
 
 type TalkMessageR struct {
 	MessageID    string `bson:"_id"`
 	TalkMessageW `bson:"inline"`
 }
+
+type OutboxMessage struct {
+	TalkID       string `bson:"TalkID"`
+	TalkMessageR `bson:"inline"`
+}
+
+type MessageFailedReason string
+
+const (
+	MessageFailedReasonStorage     MessageFailedReason = "storageError"
+	MessageFailedReasonNotAttached MessageFailedReason = "notAttached"
+	MessageFailedReasonRateLimited MessageFailedReason = "rateLimited"
+	MessageFailedReasonTooLarge    MessageFailedReason = "tooLarge"
+	MessageFailedReasonTalkClosed  MessageFailedReason = "talkClosed"
+)
+
+// Retryable reports whether sending the same message again may succeed.
+func (reason MessageFailedReason) Retryable() bool {
+	return reason == MessageFailedReasonStorage || reason == MessageFailedReasonRateLimited
+}
@@ -0,0 +1,26 @@
+package defs
+
+type TalkRatingPeriod string
+
+const (
+	TalkRatingPeriodDay   TalkRatingPeriod = "day"
+	TalkRatingPeriodWeek  TalkRatingPeriod = "week" // starts on monday
+	TalkRatingPeriodMonth TalkRatingPeriod = "month"
+)
+
+// TalkRatingStat aggregates the ratings of the talks a servicer served, rated in the period starting at PeriodStart.
+type TalkRatingStat struct {
+	ServicerID  uint64
+	PeriodStart int64 // unix seconds
+	Count       int
+	ScoreSum    int
+	ScoreCounts [TalkRatingScoreMax]int // ScoreCounts[score-1] talks are rated score
+}
+
+func (stat *TalkRatingStat) Average() float64 {
+	if stat.Count == 0 {
+		return 0
+	}
+
+	return float64(stat.ScoreSum) / float64(stat.Count)
+}
@@ -0,0 +1,57 @@
+package defs
+
+// TalkSort orders the searched talks, the talks started at the same time are ordered by TalkID the same way.
+type TalkSort int
+
+const (
+	TalkSortStartAtDesc TalkSort = iota
+	TalkSortStartAtAsc
+)
+
+// TalkCursor is where a page of the searched talks ends.
+type TalkCursor struct {
+	StartAt int64
+	TalkID  string
+}
+
+// TalkSearch is a page of SearchTalks, the zero values match any talk.
+type TalkSearch struct {
+	CreatorID uint64
+	ServiceID uint64
+	Statuses  []TalkStatus
+	Filter    *TalkFilter
+	Sort      TalkSort
+	// After nil for the first page.
+	After *TalkCursor
+	// Limit 0 for no limit.
+	Limit int
+}
+
+// Before reports whether talkInfo a comes before b in search.Sort order.
+func (search *TalkSearch) Before(a, b *TalkInfoR) bool {
+	return search.beforeCursor(a.StartAt, a.TalkID, b.StartAt, b.TalkID)
+}
+
+// IsAfter reports whether talkInfo comes after search.After, any talk does if it's nil.
+func (search *TalkSearch) IsAfter(talkInfo *TalkInfoR) bool {
+	if search.After == nil {
+		return true
+	}
+
+	return search.beforeCursor(search.After.StartAt, search.After.TalkID, talkInfo.StartAt, talkInfo.TalkID)
+}
+
+func (search *TalkSearch) beforeCursor(startAtA int64, talkIDA string, startAtB int64, talkIDB string) bool {
+	if search.Sort == TalkSortStartAtAsc {
+		return startAtA < startAtB || startAtA == startAtB && talkIDA < talkIDB
+	}
+
+	return startAtA > startAtB || startAtA == startAtB && talkIDA > talkIDB
+}
+
+func (talkInfo *TalkInfoR) Cursor() *TalkCursor {
+	return &TalkCursor{
+		StartAt: talkInfo.StartAt,
+		TalkID:  talkInfo.TalkID,
+	}
+}
@@ -0,0 +1,33 @@
+package defs
+
+type TalkTransferState int
+
+const (
+	TalkTransferStateOffered TalkTransferState = iota
+	TalkTransferStateAccepted
+	TalkTransferStateDeclined
+	TalkTransferStateExpired
+	// TalkTransferStateCanceled the talk isn't attached to the offering servicer anymore.
+	TalkTransferStateCanceled
+)
+
+// TalkTransfer is offered to the servicer ToServicerID, or to the servicers having the skill ToTeam.
+// The pending offer is kept by the model until it's resolved.
+type TalkTransfer struct {
+	TalkID         string            `bson:"TalkID"`
+	FromServicerID uint64            `bson:"FromServicerID"`
+	ToServicerID   uint64            `bson:"ToServicerID"`
+	ToTeam         string            `bson:"ToTeam"`
+	Reason         string            `bson:"Reason"`
+	ExpireAt       int64             `bson:"ExpireAt"` // unix milliseconds
+	State          TalkTransferState `bson:"State"`
+	ByServicerID   uint64            `bson:"ByServicerID"` // who accepted or declined
+}
+
+// TalkTransferDetail is kept in Data of the system message of a transfer result, talkTransferred for an accepted one.
+type TalkTransferDetail struct {
+	FromServicerID uint64 `json:"FromServicerID"`
+	ToServicerID   uint64 `json:"ToServicerID,omitempty"`
+	ToTeam         string `json:"ToTeam,omitempty"`
+	Reason         string `json:"Reason,omitempty"`
+}
@@ -43,8 +43,13 @@ func (impl *allInOneMDIImpl) RemoveTrackTalk(ctx context.Context, talkID string)
 }
 
 func (impl *allInOneMDIImpl) SendMessage(senderUniqueID uint64, talkID string, message *defs.TalkMessageW) {
-	impl.customerOb.OnMessageIncoming(senderUniqueID, talkID, message)
+	if message.VisibleToCustomer() {
+		impl.customerOb.OnMessageIncoming(senderUniqueID, talkID, message)
+	}
+
 	impl.servicerOb.OnMessageIncoming(senderUniqueID, talkID, message)
+
+	go ackOutboxMessage(impl.m, talkID, message, impl.logger)
 }
 
 func (impl *allInOneMDIImpl) SetCustomerObserver(ob defs.CustomerObserver) {
@@ -64,10 +69,28 @@ func (impl *allInOneMDIImpl) SetServicerObserver(ob defs.ServicerObserver) {
 	impl.servicerOb = ob
 }
 
-func (impl *allInOneMDIImpl) SendServicerAttachMessage(talkID string, servicerID uint64) {
-	impl.servicerOb.OnServicerAttachMessage(talkID, servicerID)
+func (impl *allInOneMDIImpl) SendServicerAttachMessage(talkID string, servicerID, fromServicerID uint64) {
+	impl.customerOb.OnServicerAttachMessage(talkID, servicerID, fromServicerID)
+	impl.servicerOb.OnServicerAttachMessage(talkID, servicerID, fromServicerID)
 }
 
 func (impl *allInOneMDIImpl) SendServiceDetachMessage(talkID string, servicerID uint64) {
+	impl.customerOb.OnServicerDetachMessage(talkID, servicerID)
 	impl.servicerOb.OnServicerDetachMessage(talkID, servicerID)
 }
+
+func (impl *allInOneMDIImpl) SendWhisperMessage(talkID string, message *defs.TalkMessageW) {
+	impl.servicerOb.OnWhisperMessage(talkID, message)
+}
+
+func (impl *allInOneMDIImpl) SendTalkClassifiedMessage(classified *defs.TalkClassified) {
+	impl.servicerOb.OnTalkClassifiedMessage(classified)
+}
+
+func (impl *allInOneMDIImpl) SendTalkTransferMessage(transfer *defs.TalkTransfer) {
+	impl.servicerOb.OnTalkTransferMessage(transfer)
+}
+
+func (impl *allInOneMDIImpl) SendServicerPresenceMessage(servicerID uint64, presence defs.ServicerPresence, disconnected bool) {
+	impl.servicerOb.OnServicerPresenceMessage(servicerID, presence, disconnected)
+}
@@ -3,6 +3,8 @@ package impls
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/sbasestarter/customer-service-be/internal/defs"
 	"github.com/sbasestarter/customer-service-be/internal/vo"
@@ -11,14 +13,25 @@ import (
 )
 
 func NewCustomerMD(mdi defs.CustomerMDI, logger l.Wrapper) defs.CustomerMD {
+	return NewCustomerMDEx(mdi, nil, logger)
+}
+
+// NewCustomerMDEx shows the servicers to the customers by profiles, by the default name if it's nil.
+func NewCustomerMDEx(mdi defs.CustomerMDI, profiles defs.ServicerProfiles, logger l.Wrapper) defs.CustomerMD {
 	if logger == nil {
 		logger = l.NewNopLoggerWrapper()
 	}
 
+	if profiles == nil {
+		profiles = NewServicerProfiles(nil)
+	}
+
 	impl := &customerMDImpl{
 		mdi:       mdi,
 		logger:    logger,
+		profiles:  profiles,
 		customers: make(map[string]map[uint64]defs.Customer),
+		positions: make(map[string]int),
 	}
 
 	mdi.SetCustomerObserver(impl)
@@ -30,8 +43,11 @@ type customerMDImpl struct {
 	mrRunner defs.MainRoutineRunner
 	mdi      defs.CustomerMDI
 	logger   l.Wrapper
+	profiles defs.ServicerProfiles
 
 	customers map[string]map[uint64]defs.Customer // talkID - customerN - customer
+	positions map[string]int                      // talkID - queue position last sent to the customers
+	rate      assignmentRate
 }
 
 //
@@ -39,10 +55,15 @@ type customerMDImpl struct {
 //
 
 func (impl *customerMDImpl) OnMessageIncoming(senderUniqueID uint64, talkID string, message *defs.TalkMessageW) {
+	// the customers follow the servicers by the typed responses, the system messages are for the history
+	if message.Type == defs.TalkMessageTypeSystem {
+		return
+	}
+
 	impl.mrRunner.Post(func() {
 		impl.sendResponseToCustomers(0, talkID, &customertalkpb.TalkResponse{
 			Talk: &customertalkpb.TalkResponse_Message{
-				Message: vo.TalkMessageDB2Pb4Customer(message),
+				Message: vo.TalkMessageDB2Pb4Customer(message, impl.profiles),
 			},
 		})
 	})
@@ -55,6 +76,34 @@ func (impl *customerMDImpl) OnTalkClose(talkID string) {
 				Close: &customertalkpb.TalkClose{},
 			},
 		})
+
+		impl.sendRatingPrompt(context.TODO(), talkID)
+		impl.refreshQueuePositions(context.TODO())
+	})
+}
+
+func (impl *customerMDImpl) OnServicerAttachMessage(talkID string, servicerID, fromServicerID uint64) {
+	impl.mrRunner.Post(func() {
+		if fromServicerID != 0 {
+			// a transferred talk isn't queued, the assignment rate and the positions stay
+			impl.sendResponseToCustomers(0, talkID, vo.TalkServicerTransferredResponse(fromServicerID, servicerID))
+
+			return
+		}
+
+		impl.rate.add(time.Now().UnixMilli())
+
+		impl.sendResponseToCustomers(0, talkID, vo.TalkServicerJoinedResponse(servicerID))
+
+		impl.refreshQueuePositions(context.TODO())
+	})
+}
+
+func (impl *customerMDImpl) OnServicerDetachMessage(talkID string, servicerID uint64) {
+	impl.mrRunner.Post(func() {
+		impl.sendResponseToCustomers(0, talkID, vo.TalkServicerLeftResponse(servicerID))
+
+		impl.refreshQueuePositions(context.TODO())
 	})
 }
 
@@ -107,36 +156,19 @@ func (impl *customerMDImpl) InstallCustomer(ctx context.Context, customer defs.C
 
 	impl.customers[customer.GetTalkID()][customer.GetUniqueID()] = customer
 
+	impl.sendQueuePosition(ctx, customer)
+
 	if customer.CreateTalkFlag() {
 		impl.mdi.SendTalkCreateMessage(customer.GetTalkID())
 	}
 
-	go func(logger l.Wrapper) {
-		messages, errG := impl.mdi.GetM().GetTalkMessages(context.TODO(), customer.GetTalkID(), 0, 0)
-		if errG != nil {
-			logger.WithFields(l.ErrorField(errG)).Error("GetTalkMessageFailed")
-
-			return
-		}
-
-		var pbMessages []*customertalkpb.TalkMessage
+	if resumePoint := customer.GetResumePoint(); resumePoint != nil {
+		go impl.sendResumeTalkMessages(customer, resumePoint)
 
-		for _, message := range messages {
-			pbMessages = append(pbMessages, vo.TalkMessageDB2Pb4Customer(&message.TalkMessageW))
-		}
+		return
+	}
 
-		if errS := customer.SendMessage(&customertalkpb.TalkResponse{
-			Talk: &customertalkpb.TalkResponse_Messages{
-				Messages: &customertalkpb.TalkMessages{
-					TalkId:   customer.GetTalkID(),
-					Messages: pbMessages,
-				},
-			},
-		}); errS != nil {
-			logger.WithFields(l.ErrorField(errS)).Error("SendMessageFailed")
-		}
-	}(impl.logger.WithFields(l.StringField("customer", fmt.Sprintf("%s-%d", customer.GetTalkID(),
-		customer.GetUniqueID()))))
+	go impl.sendTalkMessages(customer, "", true, defInitialTalkMessageCount)
 }
 
 func (impl *customerMDImpl) UninstallCustomer(ctx context.Context, customer defs.Customer) {
@@ -153,6 +185,7 @@ func (impl *customerMDImpl) UninstallCustomer(ctx context.Context, customer defs
 
 		if len(talkCustomers) == 0 {
 			delete(impl.customers, customer.GetTalkID())
+			delete(impl.positions, customer.GetTalkID())
 		}
 	}
 }
@@ -172,14 +205,7 @@ func (impl *customerMDImpl) CustomerMessageIncoming(ctx context.Context, custome
 
 	customersMap := impl.customers[customer.GetTalkID()]
 
-	if err := customer.SendMessage(&customertalkpb.TalkResponse{
-		Talk: &customertalkpb.TalkResponse_MessageConfirmed{
-			MessageConfirmed: &customertalkpb.TalkMessageConfirmed{
-				SeqId: seqID,
-				At:    uint64(message.At),
-			},
-		},
-	}); err != nil {
+	if err := customer.SendMessage(vo.MessageConfirmed4Customer(seqID, message)); err != nil {
 		impl.logger.WithFields(l.ErrorField(err), l.UInt64Field("id", customer.GetUniqueID())).
 			Error("SendMessageFailed")
 
@@ -202,7 +228,20 @@ func (impl *customerMDImpl) CustomerClose(ctx context.Context, customer defs.Cus
 		return
 	}
 
-	if err := impl.mdi.GetM().CloseTalk(ctx, customer.GetTalkID()); err != nil {
+	talkInfo, err := impl.mdi.GetM().GetTalkInfo(ctx, customer.GetTalkID())
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("GetTalkInfoFailed")
+
+		return
+	}
+
+	// a talk no servicer has picked up yet is abandoned rather than closed
+	status := defs.TalkStatusClosed
+	if talkInfo.Status == defs.TalkStatusQueued && talkInfo.FirstAssignedAt == 0 {
+		status = defs.TalkStatusAbandoned
+	}
+
+	if err = impl.mdi.GetM().UpdateTalkStatus(ctx, customer.GetTalkID(), status); err != nil {
 		impl.logger.WithFields(l.ErrorField(err)).Error("CloseTalkFailed")
 
 		return
@@ -211,10 +250,191 @@ func (impl *customerMDImpl) CustomerClose(ctx context.Context, customer defs.Cus
 	impl.mdi.SendTalkCloseMessage(customer.GetTalkID())
 }
 
+func (impl *customerMDImpl) CustomerQueryMessages(ctx context.Context, customer defs.Customer, messageID string, before bool, count int64) {
+	if customer == nil {
+		impl.logger.Error("noCustomer")
+
+		return
+	}
+
+	go impl.sendTalkMessages(customer, messageID, before, fixQueryMessageCount(count))
+}
+
 //
 //
 //
 
+// queuePositions returns the positions of the unassigned talks in the queue, 1 for the next to be assigned.
+func (impl *customerMDImpl) queuePositions(ctx context.Context) (positions map[string]int, err error) {
+	talkInfos, err := impl.mdi.GetM().GetPendingTalkInfos(ctx)
+	if err != nil {
+		return
+	}
+
+	sort.SliceStable(talkInfos, func(i, j int) bool {
+		return talkInfos[i].QueuedAt < talkInfos[j].QueuedAt
+	})
+
+	positions = make(map[string]int, len(talkInfos))
+
+	for idx, talkInfo := range talkInfos {
+		positions[talkInfo.TalkID] = idx + 1
+	}
+
+	return
+}
+
+func (impl *customerMDImpl) sendQueuePosition(ctx context.Context, customer defs.Customer) {
+	positions, err := impl.queuePositions(ctx)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("GetPendingTalkInfosFailed")
+
+		return
+	}
+
+	position, ok := positions[customer.GetTalkID()]
+	if !ok {
+		return
+	}
+
+	impl.positions[customer.GetTalkID()] = position
+
+	if err = customer.SendMessage(impl.queueStatusResponse(position)); err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.UInt64Field("id", customer.GetUniqueID())).
+			Error("SendMessageFailed")
+	}
+}
+
+// refreshQueuePositions tells the customers on this instance whose talk moved in the queue.
+func (impl *customerMDImpl) refreshQueuePositions(ctx context.Context) {
+	if len(impl.customers) == 0 {
+		return
+	}
+
+	positions, err := impl.queuePositions(ctx)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("GetPendingTalkInfosFailed")
+
+		return
+	}
+
+	for talkID := range impl.customers {
+		position := positions[talkID]
+		if position == impl.positions[talkID] {
+			continue
+		}
+
+		if position == 0 {
+			delete(impl.positions, talkID)
+
+			continue
+		}
+
+		impl.positions[talkID] = position
+
+		impl.sendResponseToCustomers(0, talkID, impl.queueStatusResponse(position))
+	}
+}
+
+// sendRatingPrompt asks the customers of the closed talk to rate the servicer, talks no servicer served aren't rated.
+func (impl *customerMDImpl) sendRatingPrompt(ctx context.Context, talkID string) {
+	if len(impl.customers[talkID]) == 0 {
+		return
+	}
+
+	talkInfo, err := impl.mdi.GetM().GetTalkInfo(ctx, talkID)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("GetTalkInfoFailed")
+
+		return
+	}
+
+	if talkInfo.ServiceID == 0 || talkInfo.Status.Opened() || talkInfo.Rated() {
+		return
+	}
+
+	impl.sendResponseToCustomers(0, talkID, vo.TalkRatingPromptResponse(talkID))
+}
+
+func (impl *customerMDImpl) queueStatusResponse(position int) *customertalkpb.TalkResponse {
+	estimatedWait, _ := impl.rate.estimateWait(position, time.Now().UnixMilli())
+
+	return vo.TalkQueueStatusResponse(position, estimatedWait)
+}
+
+func (impl *customerMDImpl) sendTalkMessages(customer defs.Customer, messageID string, before bool, count int64) {
+	logger := impl.logger.WithFields(l.StringField("customer", fmt.Sprintf("%s-%d", customer.GetTalkID(),
+		customer.GetUniqueID())))
+
+	messages, err := impl.mdi.GetM().GetTalkMessagesByCursor(context.TODO(), customer.GetTalkID(), messageID, before, count,
+		defs.CustomerTalkMessageFilter())
+	if err != nil {
+		logger.WithFields(l.ErrorField(err)).Error("GetTalkMessageFailed")
+
+		return
+	}
+
+	var pbMessages []*customertalkpb.TalkMessage
+
+	for _, message := range messages {
+		pbMessages = append(pbMessages, vo.TalkMessageDB2Pb4Customer(&message.TalkMessageW, impl.profiles))
+	}
+
+	if err = customer.SendMessage(&customertalkpb.TalkResponse{
+		Talk: &customertalkpb.TalkResponse_Messages{
+			Messages: &customertalkpb.TalkMessages{
+				TalkId:   customer.GetTalkID(),
+				Messages: pbMessages,
+			},
+		},
+	}); err != nil {
+		logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+	}
+}
+
+func (impl *customerMDImpl) sendResumeTalkMessages(customer defs.Customer, resumePoint *defs.ResumePoint) {
+	logger := impl.logger.WithFields(l.StringField("customer", fmt.Sprintf("%s-%d", customer.GetTalkID(),
+		customer.GetUniqueID())))
+
+	messages, ok, err := getResumeMessages(context.TODO(), impl.mdi.GetM(), customer.GetTalkID(), resumePoint,
+		defs.CustomerTalkMessageFilter())
+	if err != nil {
+		logger.WithFields(l.ErrorField(err)).Error("GetResumeMessagesFailed")
+
+		return
+	}
+
+	if !ok {
+		if err = customer.SendMessage(&customertalkpb.TalkResponse{
+			Talk: &customertalkpb.TalkResponse_Notify{
+				Notify: &customertalkpb.TalkNotifyResponse{
+					Msg: notifyHistoryGapTooLarge,
+				},
+			},
+		}); err != nil {
+			logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+			return
+		}
+
+		impl.sendTalkMessages(customer, "", true, defInitialTalkMessageCount)
+
+		return
+	}
+
+	for _, message := range messages {
+		if err = customer.SendMessage(&customertalkpb.TalkResponse{
+			Talk: &customertalkpb.TalkResponse_Message{
+				Message: vo.TalkMessageDB2Pb4Customer(&message.TalkMessageW, impl.profiles),
+			},
+		}); err != nil {
+			logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+			return
+		}
+	}
+}
+
 func (impl *customerMDImpl) sendResponseToCustomers(excludedUniqueID uint64, talkID string, resp *customertalkpb.TalkResponse) {
 	customersMap := impl.customers[talkID]
 
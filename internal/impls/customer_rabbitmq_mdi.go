@@ -67,6 +67,11 @@ func (impl *customerRabbitMQImpl) SendMessage(senderUniqueID uint64, talkID stri
 			SenderUniqueID: senderUniqueID,
 			Message:        message,
 		},
+		onPublished: func(err error) {
+			if err == nil {
+				go ackOutboxMessage(impl.m, talkID, message, impl.logger)
+			}
+		},
 	}
 
 	if args.RabbitMQUseSharedChannel {
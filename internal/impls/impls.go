@@ -0,0 +1,77 @@
+package impls
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/i/l"
+	"github.com/sgostarter/libeasygo/commerr"
+)
+
+const (
+	defInitialTalkMessageCount = 50
+	defMaxQueryMessageCount    = 100
+	defMaxResumeMessageCount   = 200
+	defTransferTimeout         = time.Minute
+	defTransferSweepInterval   = 10 * time.Second
+	defTransferSweepBatch      = 100
+
+	notifyHistoryGapTooLarge = "historyGapTooLarge"
+	notifyTalkClosed         = "talkClosed"
+	notifyTalkNotAttached    = "talkNotAttached"
+	notifyPermissionDenied   = "permissionDenied"
+	notifyCapacityReached    = "capacityReached"
+	notifyTalkNotWatched     = "talkNotWatched"
+	notifyTooManyTags        = "tooManyTags"
+
+	notifyTransferPending           = "transferPending"
+	notifyTransferTargetUnavailable = "transferTargetUnavailable"
+	notifyTransferAccepted          = "transferAccepted"
+	notifyTransferDeclined          = "transferDeclined"
+	notifyTransferExpired           = "transferExpired"
+	notifyTransferCanceled          = "transferCanceled"
+)
+
+func fixQueryMessageCount(count int64) int64 {
+	if count <= 0 || count > defMaxQueryMessageCount {
+		return defMaxQueryMessageCount
+	}
+
+	return count
+}
+
+// getResumeMessages returns the messages matching filter missed since resumePoint, ok is false if the gap is
+// too large to replay and the client has to reload the history.
+func getResumeMessages(ctx context.Context, m defs.ModelEx, talkID string, resumePoint *defs.ResumePoint,
+	filter *defs.TalkMessageFilter) (messages []*defs.TalkMessageR, ok bool, err error) {
+	if resumePoint.MessageID != "" {
+		messages, err = m.GetTalkMessagesByCursor(ctx, talkID, resumePoint.MessageID, false, defMaxResumeMessageCount+1, filter)
+	} else {
+		messages, err = m.GetTalkMessagesSince(ctx, talkID, resumePoint.At, defMaxResumeMessageCount+1, filter)
+	}
+
+	if err != nil {
+		if errors.Is(err, commerr.ErrNotFound) {
+			err = nil
+		}
+
+		return
+	}
+
+	ok = len(messages) <= defMaxResumeMessageCount
+
+	return
+}
+
+// ackOutboxMessage marks the message fanned out, so the outbox relay won't publish it again.
+func ackOutboxMessage(m defs.ModelEx, talkID string, message *defs.TalkMessageW, logger l.Wrapper) {
+	if message.Seq == 0 {
+		return
+	}
+
+	if err := m.AckOutboxMessage(context.TODO(), talkID, message.Seq); err != nil {
+		logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("AckOutboxMessageFailed")
+	}
+}
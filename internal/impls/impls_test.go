@@ -0,0 +1,154 @@
+package impls
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/controller"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResumeMessages(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status: defs.TalkStatusQueued,
+		Title:  "resume",
+	})
+	assert.Nil(t, err)
+
+	for idx := 0; idx < defMaxResumeMessageCount+2; idx++ {
+		err = m.AddTalkMessage(ctx, talkID, &defs.TalkMessageW{
+			At:   int64(idx),
+			Type: defs.TalkMessageTypeText,
+			Text: "message",
+		})
+		assert.Nil(t, err)
+	}
+
+	all, err := m.GetTalkMessages(ctx, talkID, 0, 0)
+	assert.Nil(t, err)
+
+	messages, ok, err := getResumeMessages(ctx, m, talkID, &defs.ResumePoint{MessageID: all[len(all)-3].MessageID}, nil)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, len(messages))
+	assert.EqualValues(t, all[len(all)-1].MessageID, messages[1].MessageID)
+
+	messages, ok, err = getResumeMessages(ctx, m, talkID, &defs.ResumePoint{At: int64(len(all) - 1)}, nil)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, len(messages))
+
+	_, ok, err = getResumeMessages(ctx, m, talkID, &defs.ResumePoint{MessageID: all[0].MessageID}, nil)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = getResumeMessages(ctx, m, talkID, &defs.ResumePoint{MessageID: "000000000000000000000000"}, nil)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+type utObserver struct {
+	messages chan *defs.TalkMessageW
+}
+
+func (ob *utObserver) OnMessageIncoming(_ uint64, _ string, message *defs.TalkMessageW) {
+	if ob.messages != nil {
+		ob.messages <- message
+	}
+}
+
+func (ob *utObserver) OnTalkCreate(string)                                           {}
+func (ob *utObserver) OnTalkClose(string)                                            {}
+func (ob *utObserver) OnServicerAttachMessage(string, uint64, uint64)                {}
+func (ob *utObserver) OnServicerDetachMessage(string, uint64)                        {}
+func (ob *utObserver) OnServicerPresenceMessage(uint64, defs.ServicerPresence, bool) {}
+func (ob *utObserver) OnTalkTransferMessage(*defs.TalkTransfer)                      {}
+func (ob *utObserver) OnWhisperMessage(string, *defs.TalkMessageW)                   {}
+func (ob *utObserver) OnTalkClassifiedMessage(*defs.TalkClassified)                  {}
+
+func TestAllInOneMDIAckOutbox(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	ob := &utObserver{messages: make(chan *defs.TalkMessageW, 10)}
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetCustomerObserver(ob)
+	mdi.SetServicerObserver(ob)
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued})
+	assert.Nil(t, err)
+
+	message := &defs.TalkMessageW{Type: defs.TalkMessageTypeText, Text: "outbox"}
+	assert.Nil(t, m.AddTalkMessage(ctx, talkID, message))
+
+	mdi.SendMessage(1, talkID, message)
+
+	assert.EqualValues(t, "outbox", (<-ob.messages).Text)
+	assert.EqualValues(t, "outbox", (<-ob.messages).Text)
+
+	future := time.Now().Add(time.Hour).UnixMilli()
+
+	assert.Eventually(t, func() bool {
+		messages, err := m.LeaseOutboxMessages(ctx, future, future, 10)
+
+		return err == nil && len(messages) == 0
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestCustomerMDNotes(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	servicerOb := &utObserver{messages: make(chan *defs.TalkMessageW, 10)}
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetServicerObserver(servicerOb)
+
+	customerMD := NewCustomerMD(mdi, nil)
+	customerMD.Setup(utMainRoutineRunner{})
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusAssigned, ServiceID: 1})
+	assert.Nil(t, err)
+
+	for _, message := range []*defs.TalkMessageW{
+		{Type: defs.TalkMessageTypeText, SenderID: 1, Text: "hello"},
+		{Type: defs.TalkMessageTypeNote, SenderID: 1, Text: "customer verified"},
+	} {
+		assert.Nil(t, m.AddTalkMessage(ctx, talkID, message))
+	}
+
+	chCustomer := make(chan *customertalkpb.TalkResponse, 10)
+	customerMD.InstallCustomer(ctx, controller.NewCustomer(1, talkID, false, 2, nil, chCustomer))
+
+	var history *customertalkpb.TalkMessages
+
+	for history == nil {
+		select {
+		case resp := <-chCustomer:
+			history = resp.GetMessages()
+		case <-time.After(time.Second):
+			t.Fatal("no history")
+		}
+	}
+
+	assert.EqualValues(t, 1, len(history.GetMessages()))
+	assert.EqualValues(t, "hello", history.GetMessages()[0].GetText())
+
+	mdi.SendMessage(0, talkID, &defs.TalkMessageW{Type: defs.TalkMessageTypeNote, SenderID: 1, Text: "refund approved"})
+	assert.EqualValues(t, "refund approved", (<-servicerOb.messages).Text)
+
+	for len(chCustomer) > 0 {
+		assert.Nil(t, (<-chCustomer).GetMessage(), "notes never reach the customer")
+	}
+}
@@ -29,6 +29,23 @@ func (impl *modelExImpl) CloseTalk(ctx context.Context, talkID string) error {
 	return impl.m.CloseTalk(ctx, talkID)
 }
 
+func (impl *modelExImpl) UpdateTalkStatus(ctx context.Context, talkID string, status defs.TalkStatus) (err error) {
+	return impl.m.UpdateTalkStatus(ctx, talkID, status)
+}
+
+func (impl *modelExImpl) ResolveTalk(ctx context.Context, talkID string, disposition, note string) (err error) {
+	return impl.m.ResolveTalk(ctx, talkID, disposition, note)
+}
+
+func (impl *modelExImpl) RateTalk(ctx context.Context, talkID string, score int, comment string) (err error) {
+	return impl.m.RateTalk(ctx, talkID, score, comment)
+}
+
+func (impl *modelExImpl) ClassifyTalk(ctx context.Context, classification *defs.TalkClassification) (
+	talkInfo *defs.TalkInfoR, err error) {
+	return impl.m.ClassifyTalk(ctx, classification)
+}
+
 func (impl *modelExImpl) AddTalkMessage(ctx context.Context, talkID string, message *defs.TalkMessageW) (err error) {
 	return impl.m.AddTalkMessage(ctx, talkID, message)
 }
@@ -37,8 +54,18 @@ func (impl *modelExImpl) GetTalkMessages(ctx context.Context, talkID string, off
 	return impl.m.GetTalkMessages(ctx, talkID, offset, count)
 }
 
+func (impl *modelExImpl) GetTalkMessagesByCursor(ctx context.Context, talkID, messageID string, before bool,
+	count int64, filter *defs.TalkMessageFilter) (messages []*defs.TalkMessageR, err error) {
+	return impl.m.GetTalkMessagesByCursor(ctx, talkID, messageID, before, count, filter)
+}
+
+func (impl *modelExImpl) GetTalkMessagesSince(ctx context.Context, talkID string, at int64, count int64,
+	filter *defs.TalkMessageFilter) (messages []*defs.TalkMessageR, err error) {
+	return impl.m.GetTalkMessagesSince(ctx, talkID, at, count, filter)
+}
+
 func (impl *modelExImpl) TalkExists(ctx context.Context, talkID string) (exists bool, err error) {
-	talkInfos, err := impl.m.QueryTalks(ctx, 0, 0, talkID, nil)
+	talkInfos, err := impl.m.QueryTalks(ctx, 0, 0, talkID, nil, nil)
 	if err != nil {
 		return
 	}
@@ -53,12 +80,16 @@ func (impl *modelExImpl) TalkExists(ctx context.Context, talkID string) (exists
 }
 
 func (impl *modelExImpl) QueryTalks(ctx context.Context, creatorID, serviceID uint64, talkID string,
-	statuses []defs.TalkStatus) (talks []*defs.TalkInfoR, err error) {
-	return impl.m.QueryTalks(ctx, creatorID, serviceID, talkID, statuses)
+	statuses []defs.TalkStatus, filter *defs.TalkFilter) (talks []*defs.TalkInfoR, err error) {
+	return impl.m.QueryTalks(ctx, creatorID, serviceID, talkID, statuses, filter)
+}
+
+func (impl *modelExImpl) SearchTalks(ctx context.Context, search *defs.TalkSearch) (talks []*defs.TalkInfoR, err error) {
+	return impl.m.SearchTalks(ctx, search)
 }
 
 func (impl *modelExImpl) GetTalkInfo(ctx context.Context, talkID string) (talkInfo *defs.TalkInfoR, err error) {
-	talkInfos, err := impl.m.QueryTalks(ctx, 0, 0, talkID, nil)
+	talkInfos, err := impl.m.QueryTalks(ctx, 0, 0, talkID, nil, nil)
 	if err != nil {
 		return
 	}
@@ -78,12 +109,46 @@ func (impl *modelExImpl) GetPendingTalkInfos(ctx context.Context) ([]*defs.TalkI
 	return impl.m.GetPendingTalkInfos(ctx)
 }
 
+func (impl *modelExImpl) GetRatedTalkInfos(ctx context.Context, from, to int64) ([]*defs.TalkInfoR, error) {
+	return impl.m.GetRatedTalkInfos(ctx, from, to)
+}
+
 func (impl *modelExImpl) UpdateTalkServiceID(ctx context.Context, talkID string, serviceID uint64) (err error) {
 	return impl.m.UpdateTalkServiceID(ctx, talkID, serviceID)
 }
 
+func (impl *modelExImpl) CompareAndSetTalkServiceID(ctx context.Context, talkID string, expectedServiceID, serviceID uint64) (swapped bool,
+	curServiceID uint64, err error) {
+	return impl.m.CompareAndSetTalkServiceID(ctx, talkID, expectedServiceID, serviceID)
+}
+
+func (impl *modelExImpl) OfferTalkTransfer(ctx context.Context, transfer *defs.TalkTransfer) (err error) {
+	return impl.m.OfferTalkTransfer(ctx, transfer)
+}
+
+func (impl *modelExImpl) GetTalkTransfer(ctx context.Context, talkID string) (transfer *defs.TalkTransfer, err error) {
+	return impl.m.GetTalkTransfer(ctx, talkID)
+}
+
+func (impl *modelExImpl) RemoveTalkTransfer(ctx context.Context, talkID string, expireAt int64) (removed bool, err error) {
+	return impl.m.RemoveTalkTransfer(ctx, talkID, expireAt)
+}
+
+func (impl *modelExImpl) GetExpiredTalkTransfers(ctx context.Context, now int64, count int64) (
+	transfers []*defs.TalkTransfer, err error) {
+	return impl.m.GetExpiredTalkTransfers(ctx, now, count)
+}
+
+func (impl *modelExImpl) LeaseOutboxMessages(ctx context.Context, now, leaseUntil int64, count int64) (messages []*defs.OutboxMessage, err error) {
+	return impl.m.LeaseOutboxMessages(ctx, now, leaseUntil, count)
+}
+
+func (impl *modelExImpl) AckOutboxMessage(ctx context.Context, talkID string, seq uint64) (err error) {
+	return impl.m.AckOutboxMessage(ctx, talkID, seq)
+}
+
 func (impl *modelExImpl) GetServicerTalkInfos(ctx context.Context, servicerID uint64) ([]*defs.TalkInfoR, error) {
-	talkInfos, err := impl.m.QueryTalks(ctx, 0, servicerID, "", []defs.TalkStatus{defs.TalkStatusOpened})
+	talkInfos, err := impl.m.QueryTalks(ctx, 0, servicerID, "", defs.TalkStatusesOpened, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,109 @@
+package impls
+
+import (
+	"context"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/i/l"
+	"github.com/sgostarter/libeasygo/routineman"
+)
+
+const (
+	defOutboxRelayInterval = time.Second
+	defOutboxRelayLease    = 30 * time.Second
+	defOutboxRelayBatch    = 100
+)
+
+type OutboxRelay interface {
+	StopAndWait()
+}
+
+// NewOutboxRelay publishes the stored messages whose fan-out wasn't acked in time, retrying every lease,
+// so other instances receive every message at least once.
+func NewOutboxRelay(mdi defs.MDIBase, logger l.Wrapper) OutboxRelay {
+	return NewOutboxRelayEx(defOutboxRelayInterval, defOutboxRelayLease, mdi, logger)
+}
+
+func NewOutboxRelayEx(interval, lease time.Duration, mdi defs.MDIBase, logger l.Wrapper) OutboxRelay {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	if interval <= 0 {
+		interval = defOutboxRelayInterval
+	}
+
+	if lease <= 0 {
+		lease = defOutboxRelayLease
+	}
+
+	impl := &outboxRelayImpl{
+		mdi:        mdi,
+		interval:   interval,
+		lease:      lease,
+		logger:     logger.WithFields(l.StringField(l.ClsKey, "outboxRelayImpl")),
+		routineMan: routineman.NewRoutineMan(context.Background(), logger),
+	}
+
+	impl.routineMan.StartRoutine(impl.relayRoutine, "relayRoutine")
+
+	return impl
+}
+
+type outboxRelayImpl struct {
+	mdi      defs.MDIBase
+	interval time.Duration
+	lease    time.Duration
+	logger   l.Wrapper
+
+	routineMan routineman.RoutineMan
+}
+
+func (impl *outboxRelayImpl) StopAndWait() {
+	impl.routineMan.StopAndWait()
+}
+
+func (impl *outboxRelayImpl) relayRoutine(ctx context.Context, exiting func() bool) {
+	logger := impl.logger.WithFields(l.StringField(l.RoutineKey, "relayRoutine"))
+
+	logger.Debug("enter")
+	defer logger.Debug("leave")
+
+	ticker := time.NewTicker(impl.interval)
+	defer ticker.Stop()
+
+	for !exiting() {
+		select {
+		case <-ctx.Done():
+			continue
+		case <-ticker.C:
+			impl.relay(ctx, logger)
+		}
+	}
+}
+
+func (impl *outboxRelayImpl) relay(ctx context.Context, logger l.Wrapper) {
+	for {
+		now := time.Now()
+
+		messages, err := impl.mdi.GetM().LeaseOutboxMessages(ctx, now.UnixMilli(), now.Add(impl.lease).UnixMilli(),
+			defOutboxRelayBatch)
+		if err != nil {
+			logger.WithFields(l.ErrorField(err)).Error("LeaseOutboxMessagesFailed")
+
+			return
+		}
+
+		for _, message := range messages {
+			messageW := message.TalkMessageW
+
+			// the sender connection is unknown here, so it may receive its own message again
+			impl.mdi.SendMessage(0, message.TalkID, &messageW)
+		}
+
+		if len(messages) < defOutboxRelayBatch {
+			return
+		}
+	}
+}
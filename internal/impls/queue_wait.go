@@ -0,0 +1,43 @@
+package impls
+
+import "time"
+
+const (
+	defAssignmentRateWindow     = 30 * time.Minute
+	defAssignmentRateMaxSamples = 100
+	defAssignmentRateMinSamples = 3
+)
+
+// assignmentRate estimates the wait of the queued talks from the rate the talks got assigned recently.
+type assignmentRate struct {
+	assignedAt []int64 // unix milliseconds, ascending
+}
+
+func (r *assignmentRate) add(at int64) {
+	r.assignedAt = append(r.assignedAt, at)
+
+	if len(r.assignedAt) > defAssignmentRateMaxSamples {
+		r.assignedAt = r.assignedAt[len(r.assignedAt)-defAssignmentRateMaxSamples:]
+	}
+}
+
+// estimateWait ok is false if there were too few assignments in the window.
+func (r *assignmentRate) estimateWait(position int, now int64) (wait time.Duration, ok bool) {
+	since := now - defAssignmentRateWindow.Milliseconds()
+
+	for len(r.assignedAt) > 0 && r.assignedAt[0] < since {
+		r.assignedAt = r.assignedAt[1:]
+	}
+
+	if len(r.assignedAt) < defAssignmentRateMinSamples {
+		return
+	}
+
+	// measured till now, so a queue nobody has served for a while gets a longer estimate
+	interval := time.Duration(now-r.assignedAt[0]) * time.Millisecond / time.Duration(len(r.assignedAt))
+
+	wait = interval * time.Duration(position)
+	ok = true
+
+	return
+}
@@ -0,0 +1,159 @@
+package impls
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/controller"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestAssignmentRate(t *testing.T) {
+	var rate assignmentRate
+
+	now := time.Now().UnixMilli()
+
+	rate.add(now - defAssignmentRateWindow.Milliseconds() - 1)
+	rate.add(now - 60000)
+	rate.add(now - 30000)
+
+	_, ok := rate.estimateWait(1, now)
+	assert.False(t, ok)
+
+	rate.add(now - 20000)
+
+	wait, ok := rate.estimateWait(2, now)
+	assert.True(t, ok)
+	assert.EqualValues(t, 40*time.Second, wait)
+}
+
+func TestCustomerMDQueueEvents(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	mdi := NewAllInOneMDI(m, nil)
+
+	customerMD := NewCustomerMD(mdi, nil)
+	customerMD.Setup(utMainRoutineRunner{})
+
+	servicerMD := NewServicerMD(mdi, nil)
+	servicerMD.Setup(utMainRoutineRunner{})
+
+	talkID1, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued, QueuedAt: 1})
+	assert.Nil(t, err)
+
+	talkID2, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued, QueuedAt: 2})
+	assert.Nil(t, err)
+
+	chCustomer := make(chan *customertalkpb.TalkResponse, 100)
+	customerMD.InstallCustomer(ctx, controller.NewCustomer(1, talkID2, false, 1, nil, chCustomer))
+
+	// skips the history sent by another routine
+	nextEvent := func() (num protowire.Number, value uint64) {
+		for {
+			select {
+			case resp := <-chCustomer:
+				if resp.GetTalk() != nil {
+					continue
+				}
+
+				d := resp.ProtoReflect().GetUnknown()
+
+				num, _, n := protowire.ConsumeTag(d)
+				event, _ := protowire.ConsumeBytes(d[n:])
+
+				_, _, n = protowire.ConsumeTag(event)
+				value, _ = protowire.ConsumeVarint(event[n:])
+
+				return num, value
+			case <-time.After(time.Second):
+				return
+			}
+		}
+	}
+
+	num, position := nextEvent()
+	assert.EqualValues(t, 7, num)
+	assert.EqualValues(t, 2, position)
+
+	servicer := controller.NewServicer(1, 1, false, make(chan *customertalkpb.ServiceResponse, 100))
+	servicerMD.InstallServicer(ctx, servicer)
+
+	servicerMD.ServicerAttachTalk(ctx, talkID1, servicer, false)
+
+	num, position = nextEvent()
+	assert.EqualValues(t, 7, num)
+	assert.EqualValues(t, 1, position)
+
+	servicerMD.ServicerAttachTalk(ctx, talkID2, servicer, false)
+
+	num, servicerID := nextEvent()
+	assert.EqualValues(t, 8, num)
+	assert.EqualValues(t, 1, servicerID)
+
+	mdi.SendServicerAttachMessage(talkID2, 2, 1)
+
+	num, fromServicerID := nextEvent()
+	assert.EqualValues(t, 12, num)
+	assert.EqualValues(t, 1, fromServicerID)
+
+	servicerMD.ServicerDetachTalk(ctx, talkID2, servicer)
+
+	num, servicerID = nextEvent()
+	assert.EqualValues(t, 11, num)
+	assert.EqualValues(t, 1, servicerID)
+
+	num, position = nextEvent()
+	assert.EqualValues(t, 7, num)
+	assert.EqualValues(t, 1, position)
+}
+
+func TestCustomerMDSkipsSystemMessages(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	mdi := NewAllInOneMDI(m, nil)
+
+	customerMD := NewCustomerMD(mdi, nil)
+	customerMD.Setup(utMainRoutineRunner{})
+
+	servicerMD := NewServicerMD(mdi, nil)
+	servicerMD.Setup(utMainRoutineRunner{})
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusAssigned})
+	assert.Nil(t, err)
+
+	chCustomer := make(chan *customertalkpb.TalkResponse, 100)
+	customerMD.InstallCustomer(ctx, controller.NewCustomer(1, talkID, false, 1, nil, chCustomer))
+
+	time.Sleep(100 * time.Millisecond)
+
+	for len(chCustomer) > 0 {
+		<-chCustomer
+	}
+
+	mdi.SendMessage(0, talkID, &defs.TalkMessageW{
+		Type: defs.TalkMessageTypeSystem,
+		Text: string(defs.TalkSystemEventServicerJoined),
+	})
+	mdi.SendMessage(0, talkID, &defs.TalkMessageW{
+		Type: defs.TalkMessageTypeText,
+		Text: "hello",
+	})
+
+	select {
+	case resp := <-chCustomer:
+		assert.EqualValues(t, "hello", resp.GetMessage().GetText())
+	case <-time.After(time.Second):
+		assert.Fail(t, "noMessage")
+	}
+
+	assert.Len(t, chCustomer, 0)
+}
@@ -78,21 +78,34 @@ type mqDataTalkClose struct {
 }
 
 type mqDataServicerAttach struct {
-	ServicerID uint64
+	ServicerID     uint64
+	FromServicerID uint64 `json:"FromServicerID,omitempty"`
 }
 
 type mqDataServicerDetach struct {
 	ServicerID uint64
 }
 
+type mqDataServicerPresence struct {
+	ServicerID   uint64
+	Presence     defs.ServicerPresence
+	Disconnected bool
+}
+
 type mqData struct {
-	TalkID         string                `json:"TalkID,omitempty"`
-	ChannelID      string                `json:"ChannelID"` // empty channel id equal talk id
-	Message        *mqDataMessage        `json:"Message,omitempty"`
-	TalkCreate     *mqDataTalkCreate     `json:"TalkCreate,omitempty"`
-	TalkClose      *mqDataTalkClose      `json:"TalkClose,omitempty"`
-	ServicerAttach *mqDataServicerAttach `json:"ServicerAttach,omitempty"`
-	ServicerDetach *mqDataServicerDetach `json:"ServicerDetach,omitempty"`
+	TalkID           string                  `json:"TalkID,omitempty"`
+	ChannelID        string                  `json:"ChannelID"` // empty channel id equal talk id
+	Message          *mqDataMessage          `json:"Message,omitempty"`
+	TalkCreate       *mqDataTalkCreate       `json:"TalkCreate,omitempty"`
+	TalkClose        *mqDataTalkClose        `json:"TalkClose,omitempty"`
+	ServicerAttach   *mqDataServicerAttach   `json:"ServicerAttach,omitempty"`
+	ServicerDetach   *mqDataServicerDetach   `json:"ServicerDetach,omitempty"`
+	ServicerPresence *mqDataServicerPresence `json:"ServicerPresence,omitempty"`
+	TalkTransfer     *defs.TalkTransfer      `json:"TalkTransfer,omitempty"`
+	Whisper          *mqDataMessage          `json:"Whisper,omitempty"`
+	TalkClassified   *defs.TalkClassified    `json:"TalkClassified,omitempty"`
+
+	onPublished func(err error) // called on the mq routine, must not block
 }
 
 type talkTrackStartedEventData struct {
@@ -339,15 +352,10 @@ func (impl *rabbitMQImpl) mainRoutine(ctx context.Context, exiting func() bool)
 			channelSend.NotifyClose(sendChannelBrokenNotifier)
 
 			for _, data := range cachedSendData {
-				d, _ := json.Marshal(data)
-
-				if err = channelSend.Publish(impl.exchangeName(data.TalkID), "", false, false,
-					amqp.Publishing{
-						Body: d,
-					}); err != nil {
-					logger.WithFields(l.ErrorField(err), l.StringField("talkID", data.TalkID)).Error("PublishFailed")
-				}
+				impl.publish(channelSend, data, logger)
 			}
+
+			cachedSendData = cachedSendData[:0]
 		case mqErr, ok := <-sendChannelBrokenNotifier:
 			logger.WithFields().WithFields(l.BoolField("ok", ok), l.StringField("desc", impl.mqErrorDesc(mqErr))).Error("SendChannelClosed")
 
@@ -403,20 +411,29 @@ func (impl *rabbitMQImpl) mainRoutine(ctx context.Context, exiting func() bool)
 				break
 			}
 
-			d, _ := json.Marshal(sendD)
+			impl.publish(channelSend, sendD, logger)
+		}
+	}
+}
 
-			channelID := sendD.ChannelID
-			if channelID == "" {
-				channelID = sendD.TalkID
-			}
+func (impl *rabbitMQImpl) publish(channelSend *amqp.Channel, data *mqData, logger l.Wrapper) {
+	d, _ := json.Marshal(data)
 
-			if err = channelSend.Publish(impl.exchangeName(channelID), "", false, false,
-				amqp.Publishing{
-					Body: d,
-				}); err != nil {
-				logger.WithFields(l.ErrorField(err), l.StringField("talkID", sendD.TalkID)).Error("PublishFailed")
-			}
-		}
+	channelID := data.ChannelID
+	if channelID == "" {
+		channelID = data.TalkID
+	}
+
+	err := channelSend.Publish(impl.exchangeName(channelID), "", false, false,
+		amqp.Publishing{
+			Body: d,
+		})
+	if err != nil {
+		logger.WithFields(l.ErrorField(err), l.StringField("talkID", data.TalkID)).Error("PublishFailed")
+	}
+
+	if data.onPublished != nil {
+		data.onPublished(err)
 	}
 }
 
@@ -563,7 +580,7 @@ func (impl *rabbitMQImpl) trackTalkRoutine(ctx context.Context, talkID string, s
 			}
 
 			if obj.Message != nil {
-				if impl.customerOb != nil {
+				if impl.customerOb != nil && obj.Message.Message.VisibleToCustomer() {
 					impl.customerOb.OnMessageIncoming(obj.Message.SenderUniqueID, obj.TalkID, obj.Message.Message)
 				}
 
@@ -583,13 +600,40 @@ func (impl *rabbitMQImpl) trackTalkRoutine(ctx context.Context, talkID string, s
 					impl.servicerOb.OnTalkCreate(obj.TalkID)
 				}
 			} else if obj.ServicerAttach != nil {
+				if impl.customerOb != nil {
+					impl.customerOb.OnServicerAttachMessage(obj.TalkID, obj.ServicerAttach.ServicerID,
+						obj.ServicerAttach.FromServicerID)
+				}
+
 				if impl.servicerOb != nil {
-					impl.servicerOb.OnServicerAttachMessage(obj.TalkID, obj.ServicerAttach.ServicerID)
+					impl.servicerOb.OnServicerAttachMessage(obj.TalkID, obj.ServicerAttach.ServicerID,
+						obj.ServicerAttach.FromServicerID)
 				}
 			} else if obj.ServicerDetach != nil {
+				if impl.customerOb != nil {
+					impl.customerOb.OnServicerDetachMessage(obj.TalkID, obj.ServicerDetach.ServicerID)
+				}
+
 				if impl.servicerOb != nil {
 					impl.servicerOb.OnServicerDetachMessage(obj.TalkID, obj.ServicerDetach.ServicerID)
 				}
+			} else if obj.ServicerPresence != nil {
+				if impl.servicerOb != nil {
+					impl.servicerOb.OnServicerPresenceMessage(obj.ServicerPresence.ServicerID,
+						obj.ServicerPresence.Presence, obj.ServicerPresence.Disconnected)
+				}
+			} else if obj.TalkTransfer != nil {
+				if impl.servicerOb != nil {
+					impl.servicerOb.OnTalkTransferMessage(obj.TalkTransfer)
+				}
+			} else if obj.Whisper != nil {
+				if impl.servicerOb != nil {
+					impl.servicerOb.OnWhisperMessage(obj.TalkID, obj.Whisper.Message)
+				}
+			} else if obj.TalkClassified != nil {
+				if impl.servicerOb != nil {
+					impl.servicerOb.OnTalkClassifiedMessage(obj.TalkClassified)
+				}
 			} else {
 				logger.Error("UnknownMqData")
 			}
@@ -28,14 +28,30 @@ func (impl *obImpl) OnTalkClose(talkID string) {
 	impl.t.Log(impl.id+" => OnTalkClose:", talkID)
 }
 
-func (impl *obImpl) OnServicerAttachMessage(talkID string, servicerID uint64) {
-	impl.t.Log(impl.id+" => OnServicerAttachMessage:", talkID, servicerID)
+func (impl *obImpl) OnServicerAttachMessage(talkID string, servicerID, fromServicerID uint64) {
+	impl.t.Log(impl.id+" => OnServicerAttachMessage:", talkID, servicerID, fromServicerID)
 }
 
 func (impl *obImpl) OnServicerDetachMessage(talkID string, servicerID uint64) {
 	impl.t.Log(impl.id+" => OnServicerDetachMessage:", talkID, servicerID)
 }
 
+func (impl *obImpl) OnServicerPresenceMessage(servicerID uint64, presence defs.ServicerPresence, disconnected bool) {
+	impl.t.Log(impl.id+" => OnServicerPresenceMessage:", servicerID, presence, disconnected)
+}
+
+func (impl *obImpl) OnTalkTransferMessage(transfer *defs.TalkTransfer) {
+	impl.t.Log(impl.id+" => OnTalkTransferMessage:", transfer.TalkID, transfer.State)
+}
+
+func (impl *obImpl) OnWhisperMessage(talkID string, message *defs.TalkMessageW) {
+	impl.t.Log(impl.id+" => OnWhisperMessage:", talkID, message.Text)
+}
+
+func (impl *obImpl) OnTalkClassifiedMessage(classified *defs.TalkClassified) {
+	impl.t.Log(impl.id+" => OnTalkClassifiedMessage:", classified.TalkID, classified.Tags)
+}
+
 func TestRabbitMQImpl(t *testing.T) {
 	mq1, err := NewRabbitMQ(UtMqURL, UserModeServicer, l.NewConsoleLoggerWrapper())
 	assert.Nil(t, err)
@@ -0,0 +1,118 @@
+package impls
+
+import (
+	"fmt"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/libeasygo/commerr"
+)
+
+const (
+	RoutingStrategyRoundRobin       = "roundRobin"
+	RoutingStrategyLeastActiveTalks = "leastActiveTalks"
+	RoutingStrategyLongestIdle      = "longestIdle"
+	RoutingStrategySkillsMatch      = "skillsMatch"
+)
+
+// NewRoutingStrategy returns nil for an empty name, which keeps every new talk pending.
+func NewRoutingStrategy(name string) (defs.RoutingStrategy, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case RoutingStrategyRoundRobin:
+		return &roundRobinStrategy{}, nil
+	case RoutingStrategyLeastActiveTalks:
+		return &leastActiveTalksStrategy{}, nil
+	case RoutingStrategyLongestIdle:
+		return &longestIdleStrategy{}, nil
+	case RoutingStrategySkillsMatch:
+		return &skillsMatchStrategy{}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown routing strategy %s", commerr.ErrInvalidArgument, name)
+	}
+}
+
+// roundRobinStrategy picks the candidates in servicer ID order.
+type roundRobinStrategy struct {
+	lastServicerID uint64
+}
+
+func (s *roundRobinStrategy) Pick(_ *defs.TalkInfoR, candidates []*defs.RoutingCandidate) *defs.RoutingCandidate {
+	var first, next *defs.RoutingCandidate
+
+	for _, candidate := range candidates {
+		if first == nil || candidate.ServicerID < first.ServicerID {
+			first = candidate
+		}
+
+		if candidate.ServicerID > s.lastServicerID && (next == nil || candidate.ServicerID < next.ServicerID) {
+			next = candidate
+		}
+	}
+
+	if next == nil {
+		next = first
+	}
+
+	if next != nil {
+		s.lastServicerID = next.ServicerID
+	}
+
+	return next
+}
+
+// leastActiveTalksStrategy picks the candidate with the fewest attached talks, the longest idle one on ties.
+type leastActiveTalksStrategy struct{}
+
+func (s *leastActiveTalksStrategy) Pick(_ *defs.TalkInfoR, candidates []*defs.RoutingCandidate) *defs.RoutingCandidate {
+	var picked *defs.RoutingCandidate
+
+	for _, candidate := range candidates {
+		if picked == nil || candidate.ActiveTalks < picked.ActiveTalks ||
+			candidate.ActiveTalks == picked.ActiveTalks && candidate.IdleSince < picked.IdleSince {
+			picked = candidate
+		}
+	}
+
+	return picked
+}
+
+type longestIdleStrategy struct{}
+
+func (s *longestIdleStrategy) Pick(_ *defs.TalkInfoR, candidates []*defs.RoutingCandidate) *defs.RoutingCandidate {
+	var picked *defs.RoutingCandidate
+
+	for _, candidate := range candidates {
+		if picked == nil || candidate.IdleSince < picked.IdleSince {
+			picked = candidate
+		}
+	}
+
+	return picked
+}
+
+// skillsMatchStrategy picks the least active candidate having the skill the talk asks for,
+// talks asking for no skill go to any candidate.
+type skillsMatchStrategy struct {
+	leastActiveTalksStrategy
+}
+
+func (s *skillsMatchStrategy) Pick(talkInfo *defs.TalkInfoR, candidates []*defs.RoutingCandidate) *defs.RoutingCandidate {
+	if talkInfo.Skill == "" {
+		return s.leastActiveTalksStrategy.Pick(talkInfo, candidates)
+	}
+
+	matched := make([]*defs.RoutingCandidate, 0, len(candidates))
+
+	for _, candidate := range candidates {
+		for _, skill := range candidate.Skills {
+			if skill == talkInfo.Skill {
+				matched = append(matched, candidate)
+
+				break
+			}
+		}
+	}
+
+	return s.leastActiveTalksStrategy.Pick(talkInfo, matched)
+}
@@ -0,0 +1,294 @@
+package impls
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/controller"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutingStrategies(t *testing.T) {
+	candidates := []*defs.RoutingCandidate{
+		{ServicerID: 3, ActiveTalks: 1, IdleSince: 100},
+		{ServicerID: 1, ActiveTalks: 2, IdleSince: 50, Skills: []string{"billing"}},
+		{ServicerID: 2, ActiveTalks: 1, IdleSince: 80},
+	}
+
+	talkInfo := &defs.TalkInfoR{}
+
+	strategy, err := NewRoutingStrategy(RoutingStrategyRoundRobin)
+	assert.Nil(t, err)
+
+	for _, servicerID := range []uint64{1, 2, 3, 1} {
+		assert.EqualValues(t, servicerID, strategy.Pick(talkInfo, candidates).ServicerID)
+	}
+
+	strategy, _ = NewRoutingStrategy(RoutingStrategyLeastActiveTalks)
+	assert.EqualValues(t, 2, strategy.Pick(talkInfo, candidates).ServicerID)
+
+	strategy, _ = NewRoutingStrategy(RoutingStrategyLongestIdle)
+	assert.EqualValues(t, 1, strategy.Pick(talkInfo, candidates).ServicerID)
+
+	strategy, _ = NewRoutingStrategy(RoutingStrategySkillsMatch)
+	assert.EqualValues(t, 2, strategy.Pick(talkInfo, candidates).ServicerID)
+	assert.EqualValues(t, 1, strategy.Pick(&defs.TalkInfoR{TalkInfoW: defs.TalkInfoW{Skill: "billing"}}, candidates).ServicerID)
+	assert.Nil(t, strategy.Pick(&defs.TalkInfoR{TalkInfoW: defs.TalkInfoW{Skill: "refund"}}, candidates))
+
+	assert.Nil(t, strategy.Pick(talkInfo, nil))
+
+	strategy, err = NewRoutingStrategy("")
+	assert.Nil(t, err)
+	assert.Nil(t, strategy)
+
+	_, err = NewRoutingStrategy("random")
+	assert.NotNil(t, err)
+}
+
+type utMainRoutineRunner struct{}
+
+func (utMainRoutineRunner) Post(f func()) {
+	f()
+}
+
+func TestServicerMDRouting(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetCustomerObserver(&utObserver{})
+
+	md := NewServicerMDEx(mdi, &ServicerRouting{
+		Strategy:            &leastActiveTalksStrategy{},
+		MaxTalksPerServicer: 1,
+	}, nil)
+	md.Setup(utMainRoutineRunner{})
+
+	for _, servicerID := range []uint64{1, 2} {
+		md.InstallServicer(ctx, controller.NewServicer(servicerID, servicerID, false,
+			make(chan *customertalkpb.ServiceResponse, 100)))
+	}
+
+	createTalk := func() string {
+		talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued})
+		assert.Nil(t, err)
+
+		mdi.SendTalkCreateMessage(talkID)
+
+		return talkID
+	}
+
+	talkID1 := createTalk()
+	talkID2 := createTalk()
+	talkID3 := createTalk()
+
+	talkInfo1, _ := m.GetTalkInfo(ctx, talkID1)
+	talkInfo2, _ := m.GetTalkInfo(ctx, talkID2)
+	assert.EqualValues(t, defs.TalkStatusAssigned, talkInfo1.Status)
+	assert.EqualValues(t, defs.TalkStatusAssigned, talkInfo2.Status)
+	assert.ElementsMatch(t, []uint64{1, 2}, []uint64{talkInfo1.ServiceID, talkInfo2.ServiceID})
+
+	// nobody has capacity left
+	pendingTalks, err := m.GetPendingTalkInfos(ctx)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(pendingTalks))
+	assert.EqualValues(t, talkID3, pendingTalks[0].TalkID)
+
+	assert.Nil(t, m.CloseTalk(ctx, talkID1))
+	mdi.SendTalkCloseMessage(talkID1)
+
+	talkInfo3, _ := m.GetTalkInfo(ctx, talkID3)
+	assert.EqualValues(t, talkInfo1.ServiceID, talkInfo3.ServiceID)
+	assert.EqualValues(t, defs.TalkStatusAssigned, talkInfo3.Status)
+}
+
+type utCASFailedModel struct {
+	defs.ModelEx
+}
+
+func (m *utCASFailedModel) CompareAndSetTalkServiceID(context.Context, string, uint64, uint64) (bool, uint64, error) {
+	return false, 0, errors.New("storageDown")
+}
+
+func TestServicerMDRoutingCASFailed(t *testing.T) {
+	ctx := context.Background()
+
+	m := &utCASFailedModel{ModelEx: NewModelEx(model.NewMemoryModel())}
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetCustomerObserver(&utObserver{})
+
+	md := NewServicerMDEx(mdi, &ServicerRouting{
+		Strategy: &leastActiveTalksStrategy{},
+	}, nil)
+	md.Setup(utMainRoutineRunner{})
+
+	chServicer := make(chan *customertalkpb.ServiceResponse, 100)
+	md.InstallServicer(ctx, controller.NewServicer(1, 1, false, chServicer))
+
+	for len(chServicer) > 0 {
+		<-chServicer
+	}
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued})
+	assert.Nil(t, err)
+
+	mdi.SendTalkCreateMessage(talkID)
+
+	// not routed, so it's offered to the servicers instead of being lost
+	if assert.EqualValues(t, 1, len(chServicer)) {
+		assert.EqualValues(t, talkID, (<-chServicer).GetDetach().GetTalk().GetTalkId())
+	}
+
+	talkInfo, _ := m.GetTalkInfo(ctx, talkID)
+	assert.EqualValues(t, defs.TalkStatusQueued, talkInfo.Status)
+	assert.EqualValues(t, 0, talkInfo.ServiceID)
+}
+
+func TestServicerMDPresence(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetCustomerObserver(&utObserver{})
+
+	md := NewServicerMDEx(mdi, &ServicerRouting{
+		Strategy: &leastActiveTalksStrategy{},
+	}, nil)
+	md.Setup(utMainRoutineRunner{})
+
+	supervisor := controller.NewServicer(1, 1, true, make(chan *customertalkpb.ServiceResponse, 100))
+	chServicer := make(chan *customertalkpb.ServiceResponse, 100)
+	servicer := controller.NewServicer(2, 2, false, chServicer)
+
+	md.InstallServicer(ctx, supervisor)
+	md.InstallServicer(ctx, servicer)
+
+	createTalk := func() *defs.TalkInfoR {
+		talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued})
+		assert.Nil(t, err)
+
+		mdi.SendTalkCreateMessage(talkID)
+
+		talkInfo, err := m.GetTalkInfo(ctx, talkID)
+		assert.Nil(t, err)
+
+		return talkInfo
+	}
+
+	md.ServicerSetPresence(ctx, servicer, defs.ServicerPresenceAway)
+	assert.EqualValues(t, 1, createTalk().ServiceID)
+
+	md.ServicerSetPresence(ctx, supervisor, defs.ServicerPresenceBusy)
+
+	for len(chServicer) > 0 {
+		<-chServicer
+	}
+
+	talkInfo := createTalk()
+	assert.EqualValues(t, 0, talkInfo.ServiceID)
+	assert.EqualValues(t, 0, len(chServicer), "no pending talk notification while away")
+
+	md.ServicerSetPresence(ctx, servicer, defs.ServicerPresenceOnline)
+
+	talkInfo, _ = m.GetTalkInfo(ctx, talkInfo.TalkID)
+	assert.EqualValues(t, 2, talkInfo.ServiceID)
+
+	md.ServicerQueryPresences(ctx, servicer)
+
+	for len(chServicer) > 1 {
+		<-chServicer
+	}
+
+	assert.EqualValues(t, notifyPermissionDenied, (<-chServicer).GetNotify().GetMsg())
+
+	md.UninstallServicer(ctx, servicer)
+
+	impl, _ := md.(*servicerMDImpl)
+	assert.EqualValues(t, map[uint64]defs.ServicerPresence{1: defs.ServicerPresenceBusy}, impl.presences)
+
+	// the servicer is connected to another instance still
+	impl.OnServicerPresenceMessage(1, defs.ServicerPresenceOffline, true)
+	assert.EqualValues(t, defs.ServicerPresenceBusy, impl.presences[1])
+}
+
+func TestServicerMDCapacity(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetCustomerObserver(&utObserver{})
+
+	capacities := model.NewMemoryServicerCapacityModel()
+	assert.Nil(t, capacities.SetServicerMaxTalks(ctx, 2, 2))
+
+	md := NewServicerMDEx(mdi, &ServicerRouting{
+		MaxTalksPerServicer: 1,
+		Capacities:          capacities,
+	}, nil)
+	md.Setup(utMainRoutineRunner{})
+
+	chServicer1 := make(chan *customertalkpb.ServiceResponse, 100)
+	servicer1 := controller.NewServicer(1, 1, false, chServicer1)
+	chServicer2 := make(chan *customertalkpb.ServiceResponse, 100)
+	servicer2 := controller.NewServicer(2, 2, false, chServicer2)
+
+	md.InstallServicer(ctx, servicer1)
+	md.InstallServicer(ctx, servicer2)
+
+	var talkIDs []string
+
+	for i := 0; i < 3; i++ {
+		talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued})
+		assert.Nil(t, err)
+
+		talkIDs = append(talkIDs, talkID)
+	}
+
+	lastNotify := func(ch chan *customertalkpb.ServiceResponse) (msg string) {
+		for len(ch) > 0 {
+			if notify := (<-ch).GetNotify(); notify != nil {
+				msg = notify.GetMsg()
+			}
+		}
+
+		return
+	}
+
+	md.ServicerAttachTalk(ctx, talkIDs[0], servicer1, false)
+	md.ServicerAttachTalk(ctx, talkIDs[1], servicer1, false)
+	assert.EqualValues(t, notifyCapacityReached+":"+talkIDs[1]+":1", lastNotify(chServicer1))
+
+	// attaching the same talk again is not rejected
+	md.ServicerAttachTalk(ctx, talkIDs[0], servicer1, false)
+	assert.EqualValues(t, "", lastNotify(chServicer1))
+
+	md.ServicerAttachTalk(ctx, talkIDs[1], servicer2, false)
+	md.ServicerAttachTalk(ctx, talkIDs[2], servicer2, false)
+	assert.EqualValues(t, "", lastNotify(chServicer2))
+
+	servicerID, _ := m.GetTalkServicerID(ctx, talkIDs[2])
+	assert.EqualValues(t, 2, servicerID)
+
+	md.ServicerQueryAttachedTalks(ctx, servicer2)
+
+	var attachedTalks *customertalkpb.ServiceAttachedTalksResponse
+
+	for len(chServicer2) > 0 {
+		if talks := (<-chServicer2).GetTalks(); talks != nil {
+			attachedTalks = talks
+		}
+	}
+
+	assert.NotNil(t, attachedTalks)
+	assert.EqualValues(t, 2, len(attachedTalks.GetTalks()))
+	// active_talks = 2, max_talks = 2
+	assert.EqualValues(t, []byte{3 << 3, 2, 4 << 3, 2}, attachedTalks.ProtoReflect().GetUnknown())
+}
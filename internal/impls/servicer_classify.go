@@ -0,0 +1,75 @@
+package impls
+
+import (
+	"context"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/vo"
+	"github.com/sgostarter/i/l"
+)
+
+// OnTalkClassifiedMessage shows the classification to the servicers and the watchers of the talk on every instance.
+func (impl *servicerMDImpl) OnTalkClassifiedMessage(classified *defs.TalkClassified) {
+	impl.mrRunner.Post(func() {
+		impl.sendResponseToServicersForTalk(0, classified.TalkID, vo.ServiceTalkClassifiedResponse(classified))
+	})
+}
+
+func (impl *servicerMDImpl) ServicerClassifyTalk(ctx context.Context, servicer defs.Servicer,
+	classification *defs.TalkClassification) {
+	if servicer == nil || classification == nil || classification.TalkID == "" {
+		impl.logger.Error("noServicerOrTalkID")
+
+		return
+	}
+
+	talkInfo, err := impl.mdi.GetM().GetTalkInfo(ctx, classification.TalkID)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", classification.TalkID)).
+			Error("GetTalkInfoFailed")
+
+		return
+	}
+
+	attached := talkInfo.ServiceID == servicer.GetUserID()
+
+	if !attached && !servicer.IsSupervisor() {
+		impl.sendNotify(servicer, notifyTalkNotAttached)
+
+		return
+	}
+
+	classifiedInfo := talkInfo.TalkInfoW
+	classifiedInfo.Classify(classification)
+
+	if len(classifiedInfo.Tags) > defs.TalkMaxTags {
+		impl.sendNotify(servicer, notifyTooManyTags)
+
+		return
+	}
+
+	talkInfo, err = impl.mdi.GetM().ClassifyTalk(ctx, classification)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", classification.TalkID)).
+			Error("ClassifyTalkFailed")
+
+		return
+	}
+
+	classified := &defs.TalkClassified{
+		TalkID:       talkInfo.TalkID,
+		ServicerID:   servicer.GetUserID(),
+		Tags:         talkInfo.Tags,
+		Category:     talkInfo.Category,
+		CustomFields: talkInfo.CustomFields,
+	}
+
+	impl.mdi.SendTalkClassifiedMessage(classified)
+
+	// the supervisors may classify the talks they neither have nor watch
+	if !attached && servicer.GetTalkWatchMode(classification.TalkID) == defs.TalkWatchModeNone {
+		if err = servicer.SendMessage(vo.ServiceTalkClassifiedResponse(classified)); err != nil {
+			impl.logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+		}
+	}
+}
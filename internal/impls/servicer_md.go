@@ -2,7 +2,12 @@ package impls
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
 
+	"github.com/sbasestarter/customer-service-be/config"
 	"github.com/sbasestarter/customer-service-be/internal/defs"
 	"github.com/sbasestarter/customer-service-be/internal/vo"
 	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
@@ -10,14 +15,28 @@ import (
 )
 
 func NewServicerMD(mdi defs.ServicerMDI, logger l.Wrapper) defs.ServicerMD {
+	return NewServicerMDEx(mdi, nil, logger)
+}
+
+// NewServicerMDEx assigns new talks to the online servicers by routing, they stay pending if routing is nil.
+func NewServicerMDEx(mdi defs.ServicerMDI, routing *ServicerRouting, logger l.Wrapper) defs.ServicerMD {
 	if logger == nil {
 		logger = l.NewNopLoggerWrapper()
 	}
 
+	if routing == nil {
+		routing = &ServicerRouting{}
+	}
+
 	impl := &servicerMDImpl{
 		mdi:       mdi,
 		logger:    logger,
+		routing:   routing,
 		servicers: make(map[uint64]map[uint64]defs.Servicer),
+		idleSince: make(map[uint64]int64),
+		presences: make(map[uint64]defs.ServicerPresence),
+		maxTalks:  make(map[uint64]int),
+		watchers:  make(map[string]map[uint64]defs.Servicer),
 	}
 
 	mdi.SetServicerObserver(impl)
@@ -25,12 +44,40 @@ func NewServicerMD(mdi defs.ServicerMDI, logger l.Wrapper) defs.ServicerMD {
 	return impl
 }
 
+type ServicerRouting struct {
+	Strategy            defs.RoutingStrategy
+	MaxTalksPerServicer int                        // 0 for no limit, attaching is limited too
+	Capacities          defs.ServicerCapacityModel // per servicer overrides of MaxTalksPerServicer, may be nil
+	ServicerSkills      map[uint64][]string        // the teams talks are transferred to too
+	TransferTimeout     time.Duration              // defTransferTimeout if not set
+}
+
+func NewServicerRouting(cfg *config.RoutingConfig, capacities defs.ServicerCapacityModel) (*ServicerRouting, error) {
+	strategy, err := NewRoutingStrategy(cfg.Strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServicerRouting{
+		Strategy:            strategy,
+		MaxTalksPerServicer: cfg.MaxTalksPerServicer,
+		Capacities:          capacities,
+		ServicerSkills:      cfg.ServicerSkills,
+		TransferTimeout:     time.Duration(cfg.TransferTimeoutSeconds) * time.Second,
+	}, nil
+}
+
 type servicerMDImpl struct {
 	mrRunner defs.MainRoutineRunner
 	mdi      defs.ServicerMDI
 	logger   l.Wrapper
+	routing  *ServicerRouting
 
 	servicers map[uint64]map[uint64]defs.Servicer // servicerID - servicerN - servicer
+	idleSince map[uint64]int64                    // servicerID - unix milliseconds
+	presences map[uint64]defs.ServicerPresence    // servicerID - presence, of the servicers on all instances
+	maxTalks  map[uint64]int                      // servicerID - max concurrent talks, of the local servicers
+	watchers  map[string]map[uint64]defs.Servicer // talkID - servicerN - local supervisor watching the talk
 }
 
 //
@@ -59,14 +106,22 @@ func (impl *servicerMDImpl) OnTalkCreate(talkID string) {
 			return
 		}
 
+		if impl.routeTalk(context.TODO(), talkInfo, 0) {
+			return
+		}
+
 		resp := &customertalkpb.ServiceResponse{
 			Response: &customertalkpb.ServiceResponse_Detach{ // FIXME use create message?
 				Detach: &customertalkpb.ServiceDetachTalkResponse{
-					Talk: vo.TalkInfoRDb2Pb(talkInfo),
+					Talk: vo.TalkInfoRDb2Pb4Servicer(talkInfo),
 				},
 			},
 		}
 		impl.send4AllServicers(func(servicer defs.Servicer) error {
+			if impl.presences[servicer.GetUserID()] != defs.ServicerPresenceOnline {
+				return nil
+			}
+
 			return servicer.SendMessage(resp)
 		})
 	})
@@ -85,10 +140,18 @@ func (impl *servicerMDImpl) OnTalkClose(talkID string) {
 		impl.send4AllServicers(func(servicer defs.Servicer) error {
 			return servicer.SendMessage(resp)
 		})
+
+		for _, watcher := range impl.watchers[talkID] {
+			watcher.SetTalkWatchMode(talkID, defs.TalkWatchModeNone)
+		}
+
+		delete(impl.watchers, talkID)
+
+		impl.routePendingTalks(context.TODO())
 	})
 }
 
-func (impl *servicerMDImpl) OnServicerAttachMessage(talkID string, servicerID uint64) {
+func (impl *servicerMDImpl) OnServicerAttachMessage(talkID string, servicerID, fromServicerID uint64) {
 	impl.mrRunner.Post(func() {
 		talkInfo, err := impl.mdi.GetM().GetTalkInfo(context.TODO(), talkID)
 		if err != nil {
@@ -97,10 +160,26 @@ func (impl *servicerMDImpl) OnServicerAttachMessage(talkID string, servicerID ui
 			return
 		}
 
+		// the talk is transferred away from the old servicer
+		if fromServicerID != 0 {
+			resp := &customertalkpb.ServiceResponse{
+				Response: &customertalkpb.ServiceResponse_Detach{
+					Detach: &customertalkpb.ServiceDetachTalkResponse{
+						Talk:              vo.TalkInfoRDb2Pb4Servicer(talkInfo),
+						DetachedServiceId: fromServicerID,
+					},
+				},
+			}
+
+			impl.send4AllServicers(func(servicer defs.Servicer) error {
+				return servicer.SendMessage(resp)
+			})
+		}
+
 		resp := &customertalkpb.ServiceResponse{
 			Response: &customertalkpb.ServiceResponse_Attach{
 				Attach: &customertalkpb.ServiceAttachTalkResponse{
-					Talk:              vo.TalkInfoRDb2Pb(talkInfo),
+					Talk:              vo.TalkInfoRDb2Pb4Servicer(talkInfo),
 					AttachedServiceId: servicerID,
 				},
 			},
@@ -143,7 +222,7 @@ func (impl *servicerMDImpl) OnServicerDetachMessage(talkID string, servicerID ui
 		resp := &customertalkpb.ServiceResponse{
 			Response: &customertalkpb.ServiceResponse_Detach{
 				Detach: &customertalkpb.ServiceDetachTalkResponse{
-					Talk:              vo.TalkInfoRDb2Pb(talkInfo),
+					Talk:              vo.TalkInfoRDb2Pb4Servicer(talkInfo),
 					DetachedServiceId: servicerID,
 				},
 			},
@@ -154,12 +233,33 @@ func (impl *servicerMDImpl) OnServicerDetachMessage(talkID string, servicerID ui
 	})
 }
 
+func (impl *servicerMDImpl) OnServicerPresenceMessage(servicerID uint64, presence defs.ServicerPresence, disconnected bool) {
+	impl.mrRunner.Post(func() {
+		_, local := impl.servicers[servicerID]
+
+		if disconnected && local {
+			// another instance lost its sessions, the ones here keep the servicer present
+			if impl.presences[servicerID] != defs.ServicerPresenceOffline {
+				impl.mdi.SendServicerPresenceMessage(servicerID, impl.presences[servicerID], false)
+			}
+
+			return
+		}
+
+		if impl.updatePresence(servicerID, presence) && local {
+			impl.routePendingTalks(context.TODO())
+		}
+	})
+}
+
 //
 // defs.ServicerMD
 //
 
 func (impl *servicerMDImpl) Setup(mr defs.MainRoutineRunner) {
 	impl.mrRunner = mr
+
+	impl.sweepTransfers()
 }
 
 func (impl *servicerMDImpl) InstallServicer(ctx context.Context, servicer defs.Servicer) {
@@ -169,6 +269,10 @@ func (impl *servicerMDImpl) InstallServicer(ctx context.Context, servicer defs.S
 		return
 	}
 
+	if _, ok := impl.servicers[servicer.GetUserID()]; !ok {
+		impl.maxTalks[servicer.GetUserID()] = impl.loadMaxTalks(ctx, servicer.GetUserID())
+	}
+
 	talkIDs, _ := impl.sendAttachedTalks(ctx, servicer)
 	for _, talkID := range talkIDs {
 		_ = impl.mdi.AddTrackTalk(ctx, talkID)
@@ -178,9 +282,17 @@ func (impl *servicerMDImpl) InstallServicer(ctx context.Context, servicer defs.S
 
 	if _, ok := impl.servicers[servicer.GetUserID()]; !ok {
 		impl.servicers[servicer.GetUserID()] = make(map[uint64]defs.Servicer)
+		impl.idleSince[servicer.GetUserID()] = time.Now().UnixMilli()
 	}
 
 	impl.servicers[servicer.GetUserID()][servicer.GetUniqueID()] = servicer
+
+	if impl.presences[servicer.GetUserID()] == defs.ServicerPresenceOffline {
+		impl.updatePresence(servicer.GetUserID(), defs.ServicerPresenceOnline)
+		impl.mdi.SendServicerPresenceMessage(servicer.GetUserID(), defs.ServicerPresenceOnline, false)
+	}
+
+	impl.routePendingTalks(ctx)
 }
 
 func (impl *servicerMDImpl) UninstallServicer(ctx context.Context, servicer defs.Servicer) {
@@ -188,50 +300,121 @@ func (impl *servicerMDImpl) UninstallServicer(ctx context.Context, servicer defs
 	if !ok {
 		impl.logger.Warn("noUserService")
 
+		// the sessions were dropped on sending failures
+		impl.servicerDisconnected(servicer.GetUserID())
+
 		return
 	}
 
 	delete(talkServicers, servicer.GetUniqueID())
 
+	for talkID, watchers := range impl.watchers {
+		if _, ok = watchers[servicer.GetUniqueID()]; ok {
+			impl.unwatchTalk(ctx, servicer, talkID)
+		}
+	}
+
 	if len(talkServicers) == 0 {
 		delete(impl.servicers, servicer.GetUserID())
+		delete(impl.idleSince, servicer.GetUserID())
+		delete(impl.maxTalks, servicer.GetUserID())
+
+		impl.servicerDisconnected(servicer.GetUserID())
 
 		talkInfos, _ := impl.mdi.GetM().GetServicerTalkInfos(ctx, servicer.GetUserID())
 		for _, info := range talkInfos {
+			// still tracked for the supervisors watching it
+			if len(impl.watchers[info.TalkID]) > 0 {
+				continue
+			}
+
 			impl.mdi.RemoveTrackTalk(context.TODO(), info.TalkID)
 		}
 	}
 }
 
-func (impl *servicerMDImpl) ServicerAttachTalk(ctx context.Context, talkID string, servicer defs.Servicer) {
+func (impl *servicerMDImpl) ServicerAttachTalk(ctx context.Context, talkID string, servicer defs.Servicer, force bool) {
 	if talkID == "" || servicer == nil {
 		impl.logger.WithFields(l.StringField("talkID", talkID)).Error("noServicerOrTalkID")
 
 		return
 	}
 
-	servicerID, err := impl.mdi.GetM().GetTalkServicerID(ctx, talkID)
+	talkInfo, err := impl.mdi.GetM().GetTalkInfo(ctx, talkID)
 	if err != nil {
-		impl.logger.WithFields(l.ErrorField(err)).Error("GetTalkServicerIDFailed")
+		impl.logger.WithFields(l.ErrorField(err)).Error("GetTalkInfoFailed")
+
+		return
+	}
+
+	// the customer reopens resolved talks, they're not taken back silently
+	if !talkInfo.Status.Opened() {
+		impl.sendNotify(servicer, notifyTalkClosed)
 
 		return
 	}
 
-	if servicerID > 0 {
+	if talkInfo.ServiceID != servicer.GetUserID() {
+		activeTalks, maxTalks, errCapacity := impl.servicerLoad(ctx, servicer.GetUserID())
+		if errCapacity != nil {
+			impl.logger.WithFields(l.ErrorField(errCapacity)).Error("GetServicerTalkInfosFailed")
+
+			return
+		}
+
+		if maxTalks > 0 && activeTalks >= maxTalks {
+			impl.sendNotify(servicer, fmt.Sprintf("%s:%s:%d", notifyCapacityReached, talkID, maxTalks))
+
+			return
+		}
+	}
+
+	var expectedServicerID uint64
+
+	if force {
+		expectedServicerID = talkInfo.ServiceID
+	}
+
+	swapped, servicerID, err := impl.mdi.GetM().CompareAndSetTalkServiceID(ctx, talkID, expectedServicerID,
+		servicer.GetUserID())
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("CompareAndSetTalkServiceIDFailed")
+
+		return
+	}
+
+	if !swapped {
 		if servicerID == servicer.GetUserID() {
 			impl.logger.WithFields(l.StringField("talkID", talkID),
 				l.UInt64Field("userID", servicer.GetUserID())).Warn("talkAlreadyAttached")
 
 			return
 		}
+
+		if err = servicer.SendMessage(vo.ServiceTalkAlreadyAttachedResponse(talkID, servicerID)); err != nil {
+			impl.logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+		}
+
+		return
 	}
 
-	err = impl.mdi.GetM().UpdateTalkServiceID(ctx, talkID, servicer.GetUserID())
-	if err != nil {
-		impl.logger.WithFields(l.ErrorField(err)).Error("UpdateTalkServiceID")
+	event := defs.TalkSystemEventServicerJoined
+
+	if expectedServicerID != 0 && expectedServicerID != servicer.GetUserID() {
+		impl.logger.WithFields(l.StringField("audit", "forceTakeOver"), l.StringField("talkID", talkID),
+			l.UInt64Field("fromServicerID", expectedServicerID), l.UInt64Field("toServicerID", servicer.GetUserID())).
+			Info("TalkTakenOver")
+
+		event = defs.TalkSystemEventTalkTransferred
+	}
+
+	if err = impl.mdi.GetM().UpdateTalkStatus(ctx, talkID, defs.TalkStatusAssigned); err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("UpdateTalkStatusFailed")
 	}
 
-	impl.mdi.SendServicerAttachMessage(talkID, servicer.GetUserID())
+	impl.mdi.SendServicerAttachMessage(talkID, servicer.GetUserID(), 0)
+
+	impl.sendSystemMessage(ctx, talkID, event, servicer.GetUserID())
 }
 
 func (impl *servicerMDImpl) ServicerDetachTalk(ctx context.Context, talkID string, servicer defs.Servicer) {
@@ -252,7 +435,7 @@ func (impl *servicerMDImpl) ServicerDetachTalk(ctx context.Context, talkID strin
 		if err = servicer.SendMessage(&customertalkpb.ServiceResponse{
 			Response: &customertalkpb.ServiceResponse_Notify{
 				Notify: &customertalkpb.ServiceTalkNotifyResponse{
-					Msg: "talkNotAttached",
+					Msg: notifyTalkNotAttached,
 				},
 			},
 		}); err != nil {
@@ -268,7 +451,94 @@ func (impl *servicerMDImpl) ServicerDetachTalk(ctx context.Context, talkID strin
 		impl.logger.WithFields(l.ErrorField(err)).Error("UpdateTalkServiceID")
 	}
 
+	// back to the queue unless the talk is finished already
+	if err = impl.mdi.GetM().UpdateTalkStatus(ctx, talkID, defs.TalkStatusQueued); err != nil &&
+		!errors.Is(err, defs.ErrInvalidTalkTransition) {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("UpdateTalkStatusFailed")
+	}
+
 	impl.mdi.SendServiceDetachMessage(talkID, servicer.GetUserID())
+
+	impl.sendSystemMessage(ctx, talkID, defs.TalkSystemEventServicerLeft, servicer.GetUserID())
+
+	talkInfo, err := impl.mdi.GetM().GetTalkInfo(ctx, talkID)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("GetTalkInfoFailed")
+
+		return
+	}
+
+	// hand it over to someone else
+	impl.routeTalk(ctx, talkInfo, servicer.GetUserID())
+}
+
+func (impl *servicerMDImpl) ServicerCloseTalk(ctx context.Context, servicer defs.Servicer, talkID, disposition, note string) {
+	if talkID == "" || servicer == nil {
+		impl.logger.WithFields(l.StringField("talkID", talkID)).Error("noServicerOrTalkID")
+
+		return
+	}
+
+	servicerID, err := impl.mdi.GetM().GetTalkServicerID(ctx, talkID)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("GetTalkServicerIDFailed")
+
+		return
+	}
+
+	if servicerID != servicer.GetUserID() {
+		impl.sendNotify(servicer, notifyTalkNotAttached)
+
+		return
+	}
+
+	if err = impl.mdi.GetM().ResolveTalk(ctx, talkID, disposition, note); err != nil {
+		if errors.Is(err, defs.ErrInvalidTalkTransition) {
+			impl.sendNotify(servicer, notifyTalkClosed)
+
+			return
+		}
+
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("ResolveTalkFailed")
+
+		return
+	}
+
+	impl.mdi.SendTalkCloseMessage(talkID)
+}
+
+func (impl *servicerMDImpl) ServicerSetPresence(ctx context.Context, servicer defs.Servicer, presence defs.ServicerPresence) {
+	if servicer == nil || !presence.Valid() {
+		impl.logger.WithFields(l.IntField("presence", int(presence))).Error("noServicerOrInvalidPresence")
+
+		return
+	}
+
+	changed := impl.updatePresence(servicer.GetUserID(), presence)
+
+	impl.mdi.SendServicerPresenceMessage(servicer.GetUserID(), presence, false)
+
+	if changed {
+		impl.routePendingTalks(ctx)
+	}
+}
+
+func (impl *servicerMDImpl) ServicerQueryPresences(_ context.Context, servicer defs.Servicer) {
+	if servicer == nil {
+		impl.logger.Error("noServicer")
+
+		return
+	}
+
+	if !servicer.IsSupervisor() {
+		impl.sendNotify(servicer, notifyPermissionDenied)
+
+		return
+	}
+
+	if err := servicer.SendMessage(vo.ServicePresencesResponse(impl.presences)); err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+	}
 }
 
 func (impl *servicerMDImpl) ServicerQueryAttachedTalks(ctx context.Context, servicer defs.Servicer) {
@@ -279,7 +549,48 @@ func (impl *servicerMDImpl) ServicerQueryPendingTalks(ctx context.Context, servi
 	_ = impl.sendPendingTalks(ctx, servicer)
 }
 
-func (impl *servicerMDImpl) ServicerReloadTalk(ctx context.Context, servicer defs.Servicer, talkID string) {
+func (impl *servicerMDImpl) ServicerReloadTalk(ctx context.Context, servicer defs.Servicer, talkID string,
+	resumePoint *defs.ResumePoint) {
+	if resumePoint != nil {
+		messages, ok, err := getResumeMessages(ctx, impl.mdi.GetM(), talkID, resumePoint, nil)
+		if err != nil {
+			impl.logger.WithFields(l.ErrorField(err)).Error("GetResumeMessagesFailed")
+
+			return
+		}
+
+		if ok {
+			for _, message := range messages {
+				if err = servicer.SendMessage(&customertalkpb.ServiceResponse{
+					Response: &customertalkpb.ServiceResponse_Message{
+						Message: &customertalkpb.ServiceTalkMessageResponse{
+							TalkId:  talkID,
+							Message: vo.TalkMessageDB2Pb4Servicer(&message.TalkMessageW),
+						},
+					},
+				}); err != nil {
+					impl.logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+					return
+				}
+			}
+
+			return
+		}
+
+		if err = servicer.SendMessage(&customertalkpb.ServiceResponse{
+			Response: &customertalkpb.ServiceResponse_Notify{
+				Notify: &customertalkpb.ServiceTalkNotifyResponse{
+					Msg: notifyHistoryGapTooLarge,
+				},
+			},
+		}); err != nil {
+			impl.logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+			return
+		}
+	}
+
 	talk, err := impl.getTalkInfoWithMessages(ctx, talkID)
 	if err != nil {
 		return
@@ -307,26 +618,23 @@ func (impl *servicerMDImpl) ServiceMessage(ctx context.Context, servicer defs.Se
 	if err != nil {
 		impl.logger.WithFields(l.ErrorField(err)).Error("GetTalkServicerIDFailed")
 
+		impl.sendMessageFailed(servicer, seqID, defs.MessageFailedReasonStorage)
+
 		return
 	}
 
-	if servicerID != servicer.GetUserID() {
+	if servicerID != servicer.GetUserID() && servicer.GetTalkWatchMode(talkID) != defs.TalkWatchModeBarge {
 		impl.logger.WithFields(l.StringField("talkID", talkID), l.UInt64Field("curServicerID", servicer.GetUserID()),
 			l.UInt64Field("talkServicerID", servicerID)).Error("invalidServicerID")
 
+		impl.sendMessageFailed(servicer, seqID, defs.MessageFailedReasonNotAttached)
+
 		return
 	}
 
 	servicersMap := impl.servicers[servicer.GetUserID()]
 
-	if err = servicer.SendMessage(&customertalkpb.ServiceResponse{
-		Response: &customertalkpb.ServiceResponse_MessageConfirmed{
-			MessageConfirmed: &customertalkpb.ServiceMessageConfirmed{
-				SeqId: seqID,
-				At:    uint64(message.At),
-			},
-		},
-	}); err != nil {
+	if err = servicer.SendMessage(vo.MessageConfirmed4Servicer(seqID, message)); err != nil {
 		impl.logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
 
 		servicer.Remove("SendMessageFailed")
@@ -337,10 +645,228 @@ func (impl *servicerMDImpl) ServiceMessage(ctx context.Context, servicer defs.Se
 	impl.mdi.SendMessage(servicer.GetUniqueID(), talkID, message)
 }
 
+func (impl *servicerMDImpl) ServicerQueryMessages(ctx context.Context, servicer defs.Servicer, talkID, messageID string,
+	before bool, count int64) {
+	if servicer == nil || talkID == "" {
+		impl.logger.Error("nilParameters")
+
+		return
+	}
+
+	talk, err := impl.getTalkInfoWithMessagesByCursor(ctx, talkID, messageID, before, fixQueryMessageCount(count))
+	if err != nil {
+		return
+	}
+
+	if err = servicer.SendMessage(&customertalkpb.ServiceResponse{
+		Response: &customertalkpb.ServiceResponse_Reload{
+			Reload: &customertalkpb.ServiceTalkReloadResponse{
+				Talk: talk,
+			},
+		},
+	}); err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+	}
+}
+
 //
 //
 //
 
+// routeTalk tries to assign the pending talk to a local servicer other than excludedServicerID,
+// handled is false if it stays pending.
+func (impl *servicerMDImpl) routeTalk(ctx context.Context, talkInfo *defs.TalkInfoR, excludedServicerID uint64) (handled bool) {
+	if impl.routing.Strategy == nil || talkInfo.ServiceID != 0 || talkInfo.Status != defs.TalkStatusQueued {
+		return
+	}
+
+	candidate := impl.routing.Strategy.Pick(talkInfo, impl.routingCandidates(ctx, excludedServicerID))
+	if candidate == nil {
+		return
+	}
+
+	// servicers on other instances race for it too
+	swapped, _, err := impl.mdi.GetM().CompareAndSetTalkServiceID(ctx, talkInfo.TalkID, 0, candidate.ServicerID)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkInfo.TalkID)).
+			Error("CompareAndSetTalkServiceIDFailed")
+
+		return
+	}
+
+	// taken by another servicer otherwise, it's not pending anyway
+	handled = true
+
+	if !swapped {
+		return
+	}
+
+	if err = impl.mdi.GetM().UpdateTalkStatus(ctx, talkInfo.TalkID, defs.TalkStatusAssigned); err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkInfo.TalkID)).Error("UpdateTalkStatusFailed")
+	}
+
+	impl.idleSince[candidate.ServicerID] = time.Now().UnixMilli()
+
+	_ = impl.mdi.AddTrackTalk(ctx, talkInfo.TalkID)
+
+	impl.mdi.SendServicerAttachMessage(talkInfo.TalkID, candidate.ServicerID, 0)
+
+	impl.sendSystemMessage(ctx, talkInfo.TalkID, defs.TalkSystemEventServicerJoined, candidate.ServicerID)
+
+	return
+}
+
+func (impl *servicerMDImpl) routePendingTalks(ctx context.Context) {
+	if impl.routing.Strategy == nil || len(impl.servicers) == 0 {
+		return
+	}
+
+	talkInfos, err := impl.mdi.GetM().GetPendingTalkInfos(ctx)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("GetPendingTalkInfosFailed")
+
+		return
+	}
+
+	sort.SliceStable(talkInfos, func(i, j int) bool {
+		return talkInfos[i].QueuedAt < talkInfos[j].QueuedAt
+	})
+
+	for _, talkInfo := range talkInfos {
+		impl.routeTalk(ctx, talkInfo, 0)
+	}
+}
+
+// updatePresence returns true if the servicer came online.
+func (impl *servicerMDImpl) updatePresence(servicerID uint64, presence defs.ServicerPresence) (online bool) {
+	online = presence == defs.ServicerPresenceOnline && impl.presences[servicerID] != defs.ServicerPresenceOnline
+
+	if presence == defs.ServicerPresenceOffline {
+		delete(impl.presences, servicerID)
+	} else {
+		impl.presences[servicerID] = presence
+	}
+
+	return
+}
+
+func (impl *servicerMDImpl) servicerDisconnected(servicerID uint64) {
+	if _, ok := impl.presences[servicerID]; !ok {
+		return
+	}
+
+	delete(impl.presences, servicerID)
+
+	impl.mdi.SendServicerPresenceMessage(servicerID, defs.ServicerPresenceOffline, true)
+}
+
+func (impl *servicerMDImpl) routingCandidates(ctx context.Context, excludedServicerID uint64) (candidates []*defs.RoutingCandidate) {
+	for servicerID := range impl.servicers {
+		if servicerID == excludedServicerID || impl.presences[servicerID] != defs.ServicerPresenceOnline {
+			continue
+		}
+
+		activeTalks, maxTalks, err := impl.servicerLoad(ctx, servicerID)
+		if err != nil {
+			impl.logger.WithFields(l.ErrorField(err)).Error("GetServicerTalkInfosFailed")
+
+			continue
+		}
+
+		if maxTalks > 0 && activeTalks >= maxTalks {
+			continue
+		}
+
+		candidates = append(candidates, &defs.RoutingCandidate{
+			ServicerID:  servicerID,
+			ActiveTalks: activeTalks,
+			IdleSince:   impl.idleSince[servicerID],
+			Skills:      impl.routing.ServicerSkills[servicerID],
+		})
+	}
+
+	return
+}
+
+// servicerLoad returns the talks attached to the local servicer and its max concurrent talks, 0 for no limit.
+func (impl *servicerMDImpl) servicerLoad(ctx context.Context, servicerID uint64) (activeTalks, maxTalks int, err error) {
+	maxTalks = impl.servicerMaxTalks(servicerID)
+
+	talkInfos, err := impl.mdi.GetM().GetServicerTalkInfos(ctx, servicerID)
+	if err != nil {
+		return
+	}
+
+	activeTalks = len(talkInfos)
+
+	return
+}
+
+func (impl *servicerMDImpl) servicerMaxTalks(servicerID uint64) int {
+	if maxTalks, ok := impl.maxTalks[servicerID]; ok {
+		return maxTalks
+	}
+
+	return impl.routing.MaxTalksPerServicer
+}
+
+func (impl *servicerMDImpl) loadMaxTalks(ctx context.Context, servicerID uint64) int {
+	if impl.routing.Capacities != nil {
+		maxTalks, ok, err := impl.routing.Capacities.GetServicerMaxTalks(ctx, servicerID)
+		if err != nil {
+			impl.logger.WithFields(l.ErrorField(err), l.UInt64Field("servicerID", servicerID)).
+				Error("GetServicerMaxTalksFailed")
+		} else if ok {
+			return maxTalks
+		}
+	}
+
+	return impl.routing.MaxTalksPerServicer
+}
+
+// sendSystemMessage keeps the event in the talk history and fans it out like a message.
+func (impl *servicerMDImpl) sendSystemMessage(ctx context.Context, talkID string, event defs.TalkSystemEvent,
+	servicerID uint64) {
+	impl.sendSystemMessageEx(ctx, talkID, event, servicerID, nil)
+}
+
+func (impl *servicerMDImpl) sendSystemMessageEx(ctx context.Context, talkID string, event defs.TalkSystemEvent,
+	servicerID uint64, data []byte) {
+	message := &defs.TalkMessageW{
+		At:       time.Now().UnixMilli(),
+		Type:     defs.TalkMessageTypeSystem,
+		SenderID: servicerID,
+		Text:     string(event),
+		Data:     data,
+	}
+
+	if err := impl.mdi.GetM().AddTalkMessage(ctx, talkID, message); err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("AddTalkMessageFailed")
+
+		return
+	}
+
+	impl.mdi.SendMessage(0, talkID, message)
+}
+
+func (impl *servicerMDImpl) sendNotify(servicer defs.Servicer, msg string) {
+	if err := servicer.SendMessage(&customertalkpb.ServiceResponse{
+		Response: &customertalkpb.ServiceResponse_Notify{
+			Notify: &customertalkpb.ServiceTalkNotifyResponse{
+				Msg: msg,
+			},
+		},
+	}); err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+	}
+}
+
+func (impl *servicerMDImpl) sendMessageFailed(servicer defs.Servicer, seqID uint64, reason defs.MessageFailedReason) {
+	if err := servicer.SendMessage(vo.MessageFailed4Servicer(seqID, reason)); err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+	}
+}
+
 func (impl *servicerMDImpl) sendAttachedTalks(ctx context.Context, servicer defs.Servicer) (talkIDs []string, err error) {
 	talkInfos, err := impl.mdi.GetM().GetServicerTalkInfos(ctx, servicer.GetUserID())
 	if err != nil {
@@ -352,20 +878,24 @@ func (impl *servicerMDImpl) sendAttachedTalks(ctx context.Context, servicer defs
 	talks := make([]*customertalkpb.ServiceTalkInfoAndMessages, 0, len(talkInfos))
 
 	for _, talkInfo := range talkInfos {
-		talkMessages, _ := impl.mdi.GetM().GetTalkMessages(ctx, talkInfo.TalkID, 0, 0)
+		talkMessages, _ := impl.mdi.GetM().GetTalkMessagesByCursor(ctx, talkInfo.TalkID, "", true, defInitialTalkMessageCount, nil)
 		talkIDs = append(talkIDs, talkInfo.TalkID)
 
 		talks = append(talks, &customertalkpb.ServiceTalkInfoAndMessages{
-			TalkInfo: vo.TalkInfoRDb2Pb(talkInfo),
+			TalkInfo: vo.TalkInfoRDb2Pb4Servicer(talkInfo),
 			Messages: vo.TalkMessagesRDb2Pb(talkMessages),
 		})
 	}
 
+	attachedTalks := &customertalkpb.ServiceAttachedTalksResponse{
+		Talks: talks,
+	}
+
+	vo.SetAttachedTalksLoad(attachedTalks, len(talkInfos), impl.servicerMaxTalks(servicer.GetUserID()))
+
 	err = servicer.SendMessage(&customertalkpb.ServiceResponse{
 		Response: &customertalkpb.ServiceResponse_Talks{
-			Talks: &customertalkpb.ServiceAttachedTalksResponse{
-				Talks: talks,
-			},
+			Talks: attachedTalks,
 		},
 	})
 	if err != nil {
@@ -386,7 +916,7 @@ func (impl *servicerMDImpl) sendPendingTalks(ctx context.Context, servicer defs.
 	err = servicer.SendMessage(&customertalkpb.ServiceResponse{
 		Response: &customertalkpb.ServiceResponse_PendingTalks{
 			PendingTalks: &customertalkpb.ServicePendingTalksResponse{
-				Talks: vo.TalkInfoRsDB2Pb(talkInfos),
+				Talks: vo.TalkInfoRsDB2Pb4Servicer(talkInfos),
 			},
 		},
 	})
@@ -398,6 +928,11 @@ func (impl *servicerMDImpl) sendPendingTalks(ctx context.Context, servicer defs.
 }
 
 func (impl *servicerMDImpl) getTalkInfoWithMessages(ctx context.Context, talkID string) (*customertalkpb.ServiceTalkInfoAndMessages, error) {
+	return impl.getTalkInfoWithMessagesByCursor(ctx, talkID, "", true, defInitialTalkMessageCount)
+}
+
+func (impl *servicerMDImpl) getTalkInfoWithMessagesByCursor(ctx context.Context, talkID, messageID string, before bool,
+	count int64) (*customertalkpb.ServiceTalkInfoAndMessages, error) {
 	talkInfo, err := impl.mdi.GetM().GetTalkInfo(ctx, talkID)
 	if err != nil {
 		impl.logger.WithFields(l.ErrorField(err)).Error("GetTalkInfoFailed")
@@ -405,7 +940,7 @@ func (impl *servicerMDImpl) getTalkInfoWithMessages(ctx context.Context, talkID
 		return nil, err
 	}
 
-	talkMessages, err := impl.mdi.GetM().GetTalkMessages(ctx, talkID, 0, 0)
+	talkMessages, err := impl.mdi.GetM().GetTalkMessagesByCursor(ctx, talkID, messageID, before, count, nil)
 	if err != nil {
 		impl.logger.Error("NoTalkIDMessage")
 
@@ -413,12 +948,39 @@ func (impl *servicerMDImpl) getTalkInfoWithMessages(ctx context.Context, talkID
 	}
 
 	return &customertalkpb.ServiceTalkInfoAndMessages{
-		TalkInfo: vo.TalkInfoRDb2Pb(talkInfo),
+		TalkInfo: vo.TalkInfoRDb2Pb4Servicer(talkInfo),
 		Messages: vo.TalkMessagesRDb2Pb(talkMessages),
 	}, nil
 }
 
+// sendResponseToServicersForTalk sends resp to the local sessions of the attached servicer and the supervisors watching the talk.
 func (impl *servicerMDImpl) sendResponseToServicersForTalk(excludedUniqueID uint64, talkID string, resp *customertalkpb.ServiceResponse) {
+	impl.sendResponseToServicersForTalkEx(excludedUniqueID, 0, talkID, resp)
+}
+
+// sendResponseToServicersForTalkEx sends resp to the supervisor watcherID only if it's not 0.
+func (impl *servicerMDImpl) sendResponseToServicersForTalkEx(excludedUniqueID, watcherID uint64, talkID string,
+	resp *customertalkpb.ServiceResponse) {
+	watchers := impl.watchers[talkID]
+
+	for uniqueID, watcher := range watchers {
+		if uniqueID == excludedUniqueID || (watcherID != 0 && watcher.GetUserID() != watcherID) {
+			continue
+		}
+
+		if err := watcher.SendMessage(resp); err != nil {
+			impl.logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+			watcher.Remove("SendMessageFailed")
+
+			delete(watchers, uniqueID)
+		}
+	}
+
+	if len(watchers) == 0 {
+		delete(impl.watchers, talkID)
+	}
+
 	servicerID, err := impl.mdi.GetM().GetTalkServicerID(context.TODO(), talkID)
 	if err != nil {
 		impl.logger.WithFields(l.ErrorField(err)).Error("GetTalkServicerIDFailed")
@@ -0,0 +1,515 @@
+package impls
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/controller"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/sbasestarter/customer-service-be/internal/vo"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/sgostarter/libeasygo/commerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServicerMDSystemMessages(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetCustomerObserver(&utObserver{})
+
+	md := NewServicerMD(mdi, nil)
+	md.Setup(utMainRoutineRunner{})
+
+	servicer1 := controller.NewServicer(1, 1, false, make(chan *customertalkpb.ServiceResponse, 100))
+	servicer2 := controller.NewServicer(2, 2, true, make(chan *customertalkpb.ServiceResponse, 100))
+
+	md.InstallServicer(ctx, servicer1)
+	md.InstallServicer(ctx, servicer2)
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued})
+	assert.Nil(t, err)
+
+	md.ServicerAttachTalk(ctx, talkID, servicer1, false)
+	md.ServicerAttachTalk(ctx, talkID, servicer2, true)
+	md.ServicerDetachTalk(ctx, talkID, servicer2)
+
+	messages, err := m.GetTalkMessages(ctx, talkID, 0, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(messages))
+
+	for idx, expected := range []struct {
+		event      defs.TalkSystemEvent
+		servicerID uint64
+	}{
+		{defs.TalkSystemEventServicerJoined, 1},
+		{defs.TalkSystemEventTalkTransferred, 2},
+		{defs.TalkSystemEventServicerLeft, 2},
+	} {
+		assert.EqualValues(t, defs.TalkMessageTypeSystem, messages[idx].Type)
+		assert.EqualValues(t, expected.event, messages[idx].Text)
+		assert.EqualValues(t, expected.servicerID, messages[idx].SenderID)
+	}
+}
+
+func TestServicerMDAlreadyAttached(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetCustomerObserver(&utObserver{})
+
+	md := NewServicerMD(mdi, nil)
+	md.Setup(utMainRoutineRunner{})
+
+	chServicer2 := make(chan *customertalkpb.ServiceResponse, 100)
+
+	servicer1 := controller.NewServicer(1, 1, false, make(chan *customertalkpb.ServiceResponse, 100))
+	servicer2 := controller.NewServicer(2, 2, false, chServicer2)
+
+	md.InstallServicer(ctx, servicer1)
+	md.InstallServicer(ctx, servicer2)
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued})
+	assert.Nil(t, err)
+
+	md.ServicerAttachTalk(ctx, talkID, servicer1, false)
+
+	for len(chServicer2) > 0 {
+		<-chServicer2
+	}
+
+	md.ServicerAttachTalk(ctx, talkID, servicer2, false)
+
+	assert.EqualValues(t, 1, len(chServicer2))
+	assert.EqualValues(t, vo.ServiceTalkAlreadyAttachedResponse(talkID, 1).ProtoReflect().GetUnknown(),
+		(<-chServicer2).ProtoReflect().GetUnknown())
+
+	servicerID, _ := m.GetTalkServicerID(ctx, talkID)
+	assert.EqualValues(t, 1, servicerID)
+
+	// a resolved talk is only reopened by the customer
+	md.ServicerCloseTalk(ctx, servicer1, talkID, "solved", "")
+
+	for len(chServicer2) > 0 {
+		<-chServicer2
+	}
+
+	md.ServicerAttachTalk(ctx, talkID, servicer2, false)
+	assert.EqualValues(t, notifyTalkClosed, (<-chServicer2).GetNotify().GetMsg())
+
+	talkInfo, _ := m.GetTalkInfo(ctx, talkID)
+	assert.EqualValues(t, defs.TalkStatusResolved, talkInfo.Status)
+	assert.EqualValues(t, 1, talkInfo.ServiceID)
+}
+
+func TestServicerMDTransfer(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetCustomerObserver(&utObserver{})
+
+	md := NewServicerMDEx(mdi, &ServicerRouting{
+		ServicerSkills:  map[uint64][]string{2: {"billing"}, 3: {"billing"}},
+		TransferTimeout: time.Hour,
+	}, nil)
+	md.Setup(utMainRoutineRunner{})
+
+	chServicers := make(map[uint64]chan *customertalkpb.ServiceResponse)
+	servicers := make(map[uint64]defs.Servicer)
+
+	for _, servicerID := range []uint64{1, 2, 3} {
+		chServicers[servicerID] = make(chan *customertalkpb.ServiceResponse, 100)
+		servicers[servicerID] = controller.NewServicer(servicerID, servicerID, false, chServicers[servicerID])
+
+		md.InstallServicer(ctx, servicers[servicerID])
+	}
+
+	var lastDetachedServicerID uint64
+
+	lastNotify := func(servicerID uint64) (msg string) {
+		for len(chServicers[servicerID]) > 0 {
+			resp := <-chServicers[servicerID]
+			if notify := resp.GetNotify(); notify != nil {
+				msg = notify.GetMsg()
+			}
+
+			if detach := resp.GetDetach(); detach != nil {
+				lastDetachedServicerID = detach.GetDetachedServiceId()
+			}
+		}
+
+		return
+	}
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued})
+	assert.Nil(t, err)
+
+	lastSystemMessage := func() *defs.TalkMessageR {
+		messages, err := m.GetTalkMessages(ctx, talkID, 0, 0)
+		assert.Nil(t, err)
+
+		return messages[len(messages)-1]
+	}
+
+	md.ServicerAttachTalk(ctx, talkID, servicers[1], false)
+
+	md.ServicerTransferTalk(ctx, servicers[2], talkID, 3, "", "")
+	assert.EqualValues(t, notifyTalkNotAttached, lastNotify(2))
+
+	md.ServicerTransferTalk(ctx, servicers[1], talkID, 2, "", "")
+	md.ServicerTransferTalk(ctx, servicers[1], talkID, 3, "", "")
+	assert.EqualValues(t, notifyTransferPending+":"+talkID, lastNotify(1))
+
+	md.ServicerReplyTalkTransfer(ctx, servicers[3], talkID, true)
+	assert.EqualValues(t, notifyTransferCanceled+":"+talkID, lastNotify(3), "not offered to servicer 3")
+
+	md.ServicerReplyTalkTransfer(ctx, servicers[2], talkID, false)
+	assert.EqualValues(t, notifyTransferDeclined+":"+talkID+":2", lastNotify(1))
+
+	message := lastSystemMessage()
+	assert.EqualValues(t, defs.TalkSystemEventTransferDeclined, message.Text)
+	assert.EqualValues(t, 2, message.SenderID)
+	assert.JSONEq(t, `{"FromServicerID":1,"ToServicerID":2}`, string(message.Data))
+
+	servicerID, _ := m.GetTalkServicerID(ctx, talkID)
+	assert.EqualValues(t, 1, servicerID)
+
+	impl, _ := md.(*servicerMDImpl)
+
+	md.ServicerTransferTalk(ctx, servicers[1], talkID, 0, "billing", "")
+
+	// as if it's expired
+	pending, err := m.GetTalkTransfer(ctx, talkID)
+	assert.Nil(t, err)
+	_, _ = m.RemoveTalkTransfer(ctx, talkID, pending.ExpireAt)
+	pending.ExpireAt = time.Now().UnixMilli()
+	assert.Nil(t, m.OfferTalkTransfer(ctx, pending))
+
+	impl.expireTransfers(ctx)
+	assert.EqualValues(t, notifyTransferExpired+":"+talkID, lastNotify(1))
+	assert.EqualValues(t, notifyTransferExpired+":"+talkID, lastNotify(3))
+
+	message = lastSystemMessage()
+	assert.EqualValues(t, defs.TalkSystemEventTransferExpired, message.Text)
+	assert.EqualValues(t, 1, message.SenderID)
+	assert.JSONEq(t, `{"FromServicerID":1,"ToTeam":"billing"}`, string(message.Data))
+
+	md.ServicerTransferTalk(ctx, servicers[1], talkID, 0, "billing", "refund asked")
+
+	md.ServicerReplyTalkTransfer(ctx, servicers[2], talkID, false)
+	assert.EqualValues(t, notifyTransferDeclined+":"+talkID+":2", lastNotify(1))
+	assert.EqualValues(t, "", lastNotify(3), "still offered to the team")
+
+	message = lastSystemMessage()
+	assert.EqualValues(t, defs.TalkSystemEventTransferDeclined, message.Text)
+	assert.EqualValues(t, 2, message.SenderID)
+	assert.JSONEq(t, `{"FromServicerID":1,"ToTeam":"billing","Reason":"refund asked"}`, string(message.Data))
+
+	md.ServicerReplyTalkTransfer(ctx, servicers[3], talkID, true)
+	assert.EqualValues(t, notifyTransferAccepted+":"+talkID+":3", lastNotify(1))
+	assert.EqualValues(t, 1, lastDetachedServicerID)
+
+	_, err = m.GetTalkTransfer(ctx, talkID)
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+
+	talkInfo, _ := m.GetTalkInfo(ctx, talkID)
+	assert.EqualValues(t, 3, talkInfo.ServiceID)
+	assert.EqualValues(t, defs.TalkStatusAssigned, talkInfo.Status)
+
+	message = lastSystemMessage()
+	assert.EqualValues(t, defs.TalkSystemEventTalkTransferred, message.Text)
+	assert.EqualValues(t, 3, message.SenderID)
+	assert.JSONEq(t, `{"FromServicerID":1,"ToTeam":"billing","Reason":"refund asked"}`, string(message.Data))
+
+	md.ServicerTransferTalk(ctx, servicers[3], talkID, 0, "sales", "")
+	assert.EqualValues(t, notifyTransferTargetUnavailable+":"+talkID, lastNotify(3))
+
+	// detached before the offer is accepted
+	md.ServicerTransferTalk(ctx, servicers[3], talkID, 2, "", "")
+	md.ServicerDetachTalk(ctx, talkID, servicers[3])
+	md.ServicerReplyTalkTransfer(ctx, servicers[2], talkID, true)
+	assert.EqualValues(t, notifyTransferCanceled+":"+talkID, lastNotify(2))
+
+	message = lastSystemMessage()
+	assert.EqualValues(t, defs.TalkSystemEventTransferCanceled, message.Text)
+	assert.EqualValues(t, 2, message.SenderID)
+	assert.JSONEq(t, `{"FromServicerID":3,"ToServicerID":2}`, string(message.Data))
+}
+
+func TestServicerMDTransferExpiredLocally(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetCustomerObserver(&utObserver{})
+
+	md := NewServicerMD(mdi, nil)
+	md.Setup(utMainRoutineRunner{})
+
+	chServicer := make(chan *customertalkpb.ServiceResponse, 100)
+	md.InstallServicer(ctx, controller.NewServicer(3, 3, false, chServicer))
+
+	for len(chServicer) > 0 {
+		<-chServicer
+	}
+
+	impl, _ := md.(*servicerMDImpl)
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusAssigned, ServiceID: 1})
+	assert.Nil(t, err)
+
+	// offered by a servicer on another instance, which may be gone before the offer expires
+	transfer := &defs.TalkTransfer{
+		TalkID:         talkID,
+		FromServicerID: 1,
+		ToServicerID:   3,
+		ExpireAt:       time.Now().Add(time.Millisecond * 50).UnixMilli(),
+		State:          defs.TalkTransferStateOffered,
+	}
+	assert.Nil(t, m.OfferTalkTransfer(ctx, transfer))
+	impl.OnTalkTransferMessage(transfer)
+
+	assert.EqualValues(t, vo.ServiceTransferOfferResponse(transfer).ProtoReflect().GetUnknown(),
+		(<-chServicer).ProtoReflect().GetUnknown())
+
+	select {
+	case resp := <-chServicer:
+		assert.EqualValues(t, notifyTransferExpired+":"+talkID, resp.GetNotify().GetMsg())
+	case <-time.After(time.Second):
+		assert.Fail(t, "offer not expired")
+	}
+
+	_, err = m.GetTalkTransfer(ctx, talkID)
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+}
+
+func TestServicerMDTransferSweptOnSetup(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusAssigned, ServiceID: 1})
+	assert.Nil(t, err)
+
+	// left by the instances gone
+	assert.Nil(t, m.OfferTalkTransfer(ctx, &defs.TalkTransfer{
+		TalkID:         talkID,
+		FromServicerID: 1,
+		ToServicerID:   3,
+		ExpireAt:       time.Now().UnixMilli(),
+		State:          defs.TalkTransferStateOffered,
+	}))
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetCustomerObserver(&utObserver{})
+
+	md := NewServicerMD(mdi, nil)
+	md.Setup(utMainRoutineRunner{})
+
+	_, err = m.GetTalkTransfer(ctx, talkID)
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+}
+
+type utTrackMDI struct {
+	defs.MDI
+
+	tracked map[string]bool
+}
+
+func (mdi *utTrackMDI) AddTrackTalk(_ context.Context, talkID string) error {
+	mdi.tracked[talkID] = true
+
+	return nil
+}
+
+func (mdi *utTrackMDI) RemoveTrackTalk(_ context.Context, talkID string) {
+	delete(mdi.tracked, talkID)
+}
+
+func TestServicerMDUninstallWatcher(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	mdi := &utTrackMDI{MDI: NewAllInOneMDI(m, nil), tracked: make(map[string]bool)}
+	mdi.SetCustomerObserver(&utObserver{})
+
+	md := NewServicerMD(mdi, nil)
+	md.Setup(utMainRoutineRunner{})
+
+	servicer := controller.NewServicer(1, 1, false, make(chan *customertalkpb.ServiceResponse, 100))
+	supervisor := controller.NewServicer(2, 2, true, make(chan *customertalkpb.ServiceResponse, 100))
+
+	md.InstallServicer(ctx, servicer)
+	md.InstallServicer(ctx, supervisor)
+
+	talkID1, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued})
+	assert.Nil(t, err)
+
+	talkID2, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued})
+	assert.Nil(t, err)
+
+	md.ServicerAttachTalk(ctx, talkID1, servicer, false)
+	md.ServicerWatchTalk(ctx, supervisor, talkID1, defs.TalkWatchModeMonitor)
+	md.ServicerWatchTalk(ctx, supervisor, talkID2, defs.TalkWatchModeMonitor)
+	assert.EqualValues(t, map[string]bool{talkID1: true, talkID2: true}, mdi.tracked)
+
+	// the talk of the servicer is still tracked, the one only watched isn't
+	md.UninstallServicer(ctx, supervisor)
+	assert.EqualValues(t, map[string]bool{talkID1: true}, mdi.tracked)
+
+	impl, _ := md.(*servicerMDImpl)
+	assert.EqualValues(t, 0, len(impl.watchers))
+
+	md.ServicerWatchTalk(ctx, controller.NewServicer(2, 3, true, make(chan *customertalkpb.ServiceResponse, 100)),
+		talkID1, defs.TalkWatchModeMonitor)
+
+	// the supervisor still watches the talk of the servicer gone
+	md.UninstallServicer(ctx, servicer)
+	assert.EqualValues(t, map[string]bool{talkID1: true}, mdi.tracked)
+}
+
+func TestServicerMDWatch(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	customerOb := &utObserver{messages: make(chan *defs.TalkMessageW, 100)}
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetCustomerObserver(customerOb)
+
+	md := NewServicerMD(mdi, nil)
+	md.Setup(utMainRoutineRunner{})
+
+	chServicer := make(chan *customertalkpb.ServiceResponse, 100)
+	servicer := controller.NewServicer(1, 1, false, chServicer)
+	chSupervisor := make(chan *customertalkpb.ServiceResponse, 100)
+	supervisor := controller.NewServicer(2, 2, true, chSupervisor)
+	chSupervisor3 := make(chan *customertalkpb.ServiceResponse, 100)
+	supervisor3 := controller.NewServicer(3, 3, true, chSupervisor3)
+
+	md.InstallServicer(ctx, servicer)
+	md.InstallServicer(ctx, supervisor)
+	md.InstallServicer(ctx, supervisor3)
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued})
+	assert.Nil(t, err)
+
+	md.ServicerAttachTalk(ctx, talkID, servicer, false)
+
+	drain := func(ch chan *customertalkpb.ServiceResponse) (resps []*customertalkpb.ServiceResponse) {
+		for len(ch) > 0 {
+			resps = append(resps, <-ch)
+		}
+
+		return
+	}
+
+	drain(chServicer)
+	drain(chSupervisor)
+
+	for len(customerOb.messages) > 0 {
+		<-customerOb.messages
+	}
+
+	md.ServicerWatchTalk(ctx, servicer, talkID, defs.TalkWatchModeMonitor)
+	assert.EqualValues(t, notifyPermissionDenied, drain(chServicer)[0].GetNotify().GetMsg())
+
+	md.ServicerWatchTalk(ctx, supervisor, talkID, defs.TalkWatchModeMonitor)
+	assert.NotNil(t, drain(chSupervisor)[0].GetReload())
+
+	md.ServicerWatchTalk(ctx, supervisor3, talkID, defs.TalkWatchModeMonitor)
+	drain(chSupervisor3)
+
+	mdi.SendMessage(0, talkID, &defs.TalkMessageW{Type: defs.TalkMessageTypeText, CustomerMessage: true, Text: "hi"})
+	assert.EqualValues(t, "hi", drain(chSupervisor)[0].GetMessage().GetMessage().GetText())
+	assert.EqualValues(t, "hi", drain(chServicer)[0].GetMessage().GetMessage().GetText())
+	assert.EqualValues(t, "hi", drain(chSupervisor3)[0].GetMessage().GetMessage().GetText())
+	<-customerOb.messages
+
+	md.ServiceMessage(ctx, supervisor, talkID, 1, &defs.TalkMessageW{Type: defs.TalkMessageTypeText, Text: "read only"})
+	assert.Nil(t, drain(chSupervisor)[0].GetMessageConfirmed())
+
+	md.ServicerWhisper(ctx, supervisor, talkID, &defs.TalkMessageW{Type: defs.TalkMessageTypeText, SenderID: 2,
+		Text: "offer the coupon"})
+
+	whisper := drain(chServicer)[0].GetMessage().GetMessage()
+	assert.EqualValues(t, "offer the coupon", whisper.GetText())
+	assert.NotNil(t, whisper.ProtoReflect().GetUnknown())
+	assert.EqualValues(t, 0, len(customerOb.messages), "whispers never reach the customer")
+	assert.EqualValues(t, "offer the coupon", drain(chSupervisor)[0].GetMessage().GetMessage().GetText())
+	assert.EqualValues(t, 0, len(drain(chSupervisor3)), "nor the other supervisors")
+
+	md.ServicerWatchTalk(ctx, supervisor, talkID, defs.TalkWatchModeBarge)
+	assert.EqualValues(t, defs.TalkSystemEventServicerJoined, (<-customerOb.messages).Text)
+
+	drain(chSupervisor)
+
+	md.ServiceMessage(ctx, supervisor, talkID, 2, &defs.TalkMessageW{Type: defs.TalkMessageTypeText, SenderID: 2,
+		Text: "let me help"})
+	assert.EqualValues(t, 2, drain(chSupervisor)[0].GetMessageConfirmed().GetSeqId())
+	assert.EqualValues(t, "let me help", (<-customerOb.messages).Text)
+
+	assert.Nil(t, m.CloseTalk(ctx, talkID))
+	mdi.SendTalkCloseMessage(talkID)
+	assert.EqualValues(t, defs.TalkWatchModeNone, supervisor.GetTalkWatchMode(talkID))
+
+	impl, _ := md.(*servicerMDImpl)
+	assert.EqualValues(t, 0, len(impl.watchers))
+}
+
+func TestServicerMDWatchInactiveTalk(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	md := NewServicerMD(NewAllInOneMDI(m, nil), nil)
+	md.Setup(utMainRoutineRunner{})
+
+	chSupervisor := make(chan *customertalkpb.ServiceResponse, 100)
+	supervisor := controller.NewServicer(2, 2, true, chSupervisor)
+
+	md.InstallServicer(ctx, supervisor)
+
+	drain := func() (resps []*customertalkpb.ServiceResponse) {
+		for len(chSupervisor) > 0 {
+			resps = append(resps, <-chSupervisor)
+		}
+
+		return
+	}
+
+	for _, status := range []defs.TalkStatus{defs.TalkStatusResolved, defs.TalkStatusAbandoned} {
+		talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: status})
+		assert.Nil(t, err)
+
+		drain()
+
+		md.ServicerWatchTalk(ctx, supervisor, talkID, defs.TalkWatchModeBarge)
+
+		resps := drain()
+		if assert.EqualValues(t, 1, len(resps)) {
+			assert.EqualValues(t, notifyTalkClosed, resps[0].GetNotify().GetMsg())
+		}
+
+		assert.EqualValues(t, defs.TalkWatchModeNone, supervisor.GetTalkWatchMode(talkID))
+
+		md.ServicerWatchTalk(ctx, supervisor, talkID, defs.TalkWatchModeMonitor)
+
+		if status == defs.TalkStatusResolved {
+			assert.EqualValues(t, defs.TalkWatchModeMonitor, supervisor.GetTalkWatchMode(talkID))
+		} else {
+			assert.EqualValues(t, defs.TalkWatchModeNone, supervisor.GetTalkWatchMode(talkID))
+		}
+	}
+}
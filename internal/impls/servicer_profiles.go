@@ -0,0 +1,54 @@
+package impls
+
+import (
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+)
+
+const defServicerDisplayName = "客服"
+
+// NewServicerProfiles shows every servicer by the default name if cfg is nil.
+func NewServicerProfiles(cfg *config.ServicerDisplayConfig) defs.ServicerProfiles {
+	impl := &servicerProfilesImpl{
+		defaultProfile: defs.ServicerProfile{
+			Name: defServicerDisplayName,
+		},
+	}
+
+	if cfg == nil {
+		return impl
+	}
+
+	if cfg.DefaultName != "" {
+		impl.defaultProfile.Name = cfg.DefaultName
+	}
+
+	impl.defaultProfile.Avatar = cfg.DefaultAvatar
+	impl.profiles = cfg.Profiles
+
+	return impl
+}
+
+type servicerProfilesImpl struct {
+	defaultProfile defs.ServicerProfile
+	profiles       map[uint64]config.ServicerProfileConfig
+}
+
+func (impl *servicerProfilesImpl) GetServicerProfile(servicerID uint64) (profile defs.ServicerProfile) {
+	profile = impl.defaultProfile
+
+	p, ok := impl.profiles[servicerID]
+	if !ok {
+		return
+	}
+
+	if p.Name != "" {
+		profile.Name = p.Name
+	}
+
+	if p.Avatar != "" {
+		profile.Avatar = p.Avatar
+	}
+
+	return
+}
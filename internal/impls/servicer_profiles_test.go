@@ -0,0 +1,23 @@
+package impls
+
+import (
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServicerProfiles(t *testing.T) {
+	profiles := NewServicerProfiles(&config.ServicerDisplayConfig{
+		DefaultAvatar: "default.png",
+		Profiles: map[uint64]config.ServicerProfileConfig{
+			1: {Name: "Alice"},
+		},
+	})
+
+	assert.EqualValues(t, defs.ServicerProfile{Name: "Alice", Avatar: "default.png"}, profiles.GetServicerProfile(1))
+	assert.EqualValues(t, defs.ServicerProfile{Name: defServicerDisplayName, Avatar: "default.png"},
+		profiles.GetServicerProfile(2))
+	assert.EqualValues(t, defServicerDisplayName, NewServicerProfiles(nil).GetServicerProfile(1).Name)
+}
@@ -63,6 +63,11 @@ func (impl *servicerRabbitMQImpl) SendMessage(senderUniqueID uint64, talkID stri
 			SenderUniqueID: senderUniqueID,
 			Message:        message,
 		},
+		onPublished: func(err error) {
+			if err == nil {
+				go ackOutboxMessage(impl.m, talkID, message, impl.logger)
+			}
+		},
 	}
 
 	if args.RabbitMQUseSharedChannel {
@@ -76,12 +81,13 @@ func (impl *servicerRabbitMQImpl) SetServicerObserver(ob defs.ServicerObserver)
 	impl.rabbitMQ.SetServicerObserver(ob)
 }
 
-func (impl *servicerRabbitMQImpl) SendServicerAttachMessage(talkID string, servicerID uint64) {
+func (impl *servicerRabbitMQImpl) SendServicerAttachMessage(talkID string, servicerID, fromServicerID uint64) {
 	_ = impl.rabbitMQ.SendData(&mqData{
 		TalkID:    talkID,
-		ChannelID: specialTalkServicer,
+		ChannelID: specialTalkAll, // the customers follow the attachment too
 		ServicerAttach: &mqDataServicerAttach{
-			ServicerID: servicerID,
+			ServicerID:     servicerID,
+			FromServicerID: fromServicerID,
 		},
 	})
 }
@@ -89,9 +95,55 @@ func (impl *servicerRabbitMQImpl) SendServicerAttachMessage(talkID string, servi
 func (impl *servicerRabbitMQImpl) SendServiceDetachMessage(talkID string, servicerID uint64) {
 	_ = impl.rabbitMQ.SendData(&mqData{
 		TalkID:    talkID,
-		ChannelID: specialTalkServicer,
+		ChannelID: specialTalkAll, // the customers follow the attachment too
 		ServicerDetach: &mqDataServicerDetach{
 			ServicerID: servicerID,
 		},
 	})
 }
+
+func (impl *servicerRabbitMQImpl) SendTalkCloseMessage(talkID string) {
+	_ = impl.rabbitMQ.SendData(&mqData{
+		TalkID:    talkID,
+		ChannelID: specialTalkAll,
+		TalkClose: &mqDataTalkClose{},
+	})
+}
+
+func (impl *servicerRabbitMQImpl) SendServicerPresenceMessage(servicerID uint64, presence defs.ServicerPresence,
+	disconnected bool) {
+	_ = impl.rabbitMQ.SendData(&mqData{
+		ChannelID: specialTalkServicer,
+		ServicerPresence: &mqDataServicerPresence{
+			ServicerID:   servicerID,
+			Presence:     presence,
+			Disconnected: disconnected,
+		},
+	})
+}
+
+func (impl *servicerRabbitMQImpl) SendTalkTransferMessage(transfer *defs.TalkTransfer) {
+	_ = impl.rabbitMQ.SendData(&mqData{
+		TalkID:       transfer.TalkID,
+		ChannelID:    specialTalkServicer,
+		TalkTransfer: transfer,
+	})
+}
+
+func (impl *servicerRabbitMQImpl) SendTalkClassifiedMessage(classified *defs.TalkClassified) {
+	_ = impl.rabbitMQ.SendData(&mqData{
+		TalkID:         classified.TalkID,
+		ChannelID:      specialTalkServicer,
+		TalkClassified: classified,
+	})
+}
+
+func (impl *servicerRabbitMQImpl) SendWhisperMessage(talkID string, message *defs.TalkMessageW) {
+	_ = impl.rabbitMQ.SendData(&mqData{
+		TalkID:    talkID,
+		ChannelID: specialTalkServicer, // never on the talk channel the customers follow
+		Whisper: &mqDataMessage{
+			Message: message,
+		},
+	})
+}
@@ -0,0 +1,352 @@
+package impls
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/vo"
+	"github.com/sgostarter/i/l"
+	"github.com/sgostarter/libeasygo/commerr"
+)
+
+// OnTalkTransferMessage tells the offers and their results to the local servicers. The pending offers are kept by
+// the model, so the targets may reply to any instance, and the first instance seeing an offer expired resolves it,
+// the talk stays with the offering servicer then.
+func (impl *servicerMDImpl) OnTalkTransferMessage(transfer *defs.TalkTransfer) {
+	impl.mrRunner.Post(func() {
+		if transfer.State == defs.TalkTransferStateOffered {
+			resp := vo.ServiceTransferOfferResponse(transfer)
+
+			impl.send4TransferTargets(transfer, func(servicer defs.Servicer) error {
+				return servicer.SendMessage(resp)
+			})
+
+			time.AfterFunc(time.Until(time.UnixMilli(transfer.ExpireAt)), func() {
+				impl.mrRunner.Post(func() {
+					impl.expireTransfers(context.TODO())
+				})
+			})
+
+			return
+		}
+
+		impl.finishTransfer(transfer)
+	})
+}
+
+func (impl *servicerMDImpl) ServicerTransferTalk(ctx context.Context, servicer defs.Servicer, talkID string,
+	toServicerID uint64, toTeam, reason string) {
+	if talkID == "" || servicer == nil || (toServicerID == 0) == (toTeam == "") {
+		impl.logger.WithFields(l.StringField("talkID", talkID), l.UInt64Field("toServicerID", toServicerID),
+			l.StringField("toTeam", toTeam)).Error("noServicerOrTalkIDOrTarget")
+
+		return
+	}
+
+	servicerID, err := impl.mdi.GetM().GetTalkServicerID(ctx, talkID)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("GetTalkServicerIDFailed")
+
+		return
+	}
+
+	if servicerID != servicer.GetUserID() {
+		impl.sendNotify(servicer, notifyTalkNotAttached)
+
+		return
+	}
+
+	now := time.Now()
+
+	pending, err := impl.mdi.GetM().GetTalkTransfer(ctx, talkID)
+	if err == nil {
+		if pending.ExpireAt > now.UnixMilli() {
+			impl.sendNotify(servicer, fmt.Sprintf("%s:%s", notifyTransferPending, talkID))
+
+			return
+		}
+
+		// expired, but no instance has swept it yet
+		impl.expireTransfer(ctx, pending)
+	} else if !errors.Is(err, commerr.ErrNotFound) {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("GetTalkTransferFailed")
+
+		return
+	}
+
+	timeout := impl.routing.TransferTimeout
+	if timeout <= 0 {
+		timeout = defTransferTimeout
+	}
+
+	transfer := &defs.TalkTransfer{
+		TalkID:         talkID,
+		FromServicerID: servicer.GetUserID(),
+		ToServicerID:   toServicerID,
+		ToTeam:         toTeam,
+		Reason:         reason,
+		ExpireAt:       now.Add(timeout).UnixMilli(),
+		State:          defs.TalkTransferStateOffered,
+	}
+
+	if !impl.hasOnlineTransferTarget(transfer) {
+		impl.sendNotify(servicer, fmt.Sprintf("%s:%s", notifyTransferTargetUnavailable, talkID))
+
+		return
+	}
+
+	if err = impl.mdi.GetM().OfferTalkTransfer(ctx, transfer); err != nil {
+		if errors.Is(err, commerr.ErrAlreadyExists) {
+			// offered on another instance meanwhile
+			impl.sendNotify(servicer, fmt.Sprintf("%s:%s", notifyTransferPending, talkID))
+		} else {
+			impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("OfferTalkTransferFailed")
+		}
+
+		return
+	}
+
+	impl.mdi.SendTalkTransferMessage(transfer)
+}
+
+func (impl *servicerMDImpl) ServicerReplyTalkTransfer(ctx context.Context, servicer defs.Servicer, talkID string,
+	accept bool) {
+	if talkID == "" || servicer == nil {
+		impl.logger.WithFields(l.StringField("talkID", talkID)).Error("noServicerOrTalkID")
+
+		return
+	}
+
+	transfer, err := impl.mdi.GetM().GetTalkTransfer(ctx, talkID)
+	if err != nil && !errors.Is(err, commerr.ErrNotFound) {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("GetTalkTransferFailed")
+
+		return
+	}
+
+	if err != nil || transfer.ExpireAt <= time.Now().UnixMilli() || !impl.isTransferTarget(transfer, servicer.GetUserID()) {
+		impl.sendNotify(servicer, fmt.Sprintf("%s:%s", notifyTransferCanceled, talkID))
+
+		return
+	}
+
+	result := *transfer
+	result.ByServicerID = servicer.GetUserID()
+
+	if !accept {
+		// a team offer stays open to the other members
+		if transfer.ToServicerID != 0 && !impl.claimTransfer(ctx, transfer) {
+			impl.sendNotify(servicer, fmt.Sprintf("%s:%s", notifyTransferCanceled, talkID))
+
+			return
+		}
+
+		result.State = defs.TalkTransferStateDeclined
+
+		impl.sendTransferSystemMessage(ctx, &result, defs.TalkSystemEventTransferDeclined, servicer.GetUserID())
+
+		impl.mdi.SendTalkTransferMessage(&result)
+
+		return
+	}
+
+	activeTalks, maxTalks, err := impl.servicerLoad(ctx, servicer.GetUserID())
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("GetServicerTalkInfosFailed")
+
+		return
+	}
+
+	if maxTalks > 0 && activeTalks >= maxTalks {
+		impl.sendNotify(servicer, fmt.Sprintf("%s:%s:%d", notifyCapacityReached, talkID, maxTalks))
+
+		return
+	}
+
+	// another member of the team may be accepting it on another instance
+	if !impl.claimTransfer(ctx, transfer) {
+		impl.sendNotify(servicer, fmt.Sprintf("%s:%s", notifyTransferCanceled, talkID))
+
+		return
+	}
+
+	swapped, _, err := impl.mdi.GetM().CompareAndSetTalkServiceID(ctx, talkID, transfer.FromServicerID,
+		servicer.GetUserID())
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).
+			Error("CompareAndSetTalkServiceIDFailed")
+
+		return
+	}
+
+	if !swapped {
+		// detached, closed or taken over meanwhile
+		result.State = defs.TalkTransferStateCanceled
+
+		impl.sendTransferSystemMessage(ctx, &result, defs.TalkSystemEventTransferCanceled, servicer.GetUserID())
+
+		impl.mdi.SendTalkTransferMessage(&result)
+
+		return
+	}
+
+	result.State = defs.TalkTransferStateAccepted
+
+	impl.logger.WithFields(l.StringField("audit", "transfer"), l.StringField("talkID", talkID),
+		l.UInt64Field("fromServicerID", transfer.FromServicerID), l.UInt64Field("toServicerID", servicer.GetUserID()),
+		l.StringField("reason", transfer.Reason)).Info("TalkTransferred")
+
+	if err = impl.mdi.GetM().UpdateTalkStatus(ctx, talkID, defs.TalkStatusAssigned); err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("UpdateTalkStatusFailed")
+	}
+
+	impl.idleSince[servicer.GetUserID()] = time.Now().UnixMilli()
+
+	_ = impl.mdi.AddTrackTalk(ctx, talkID)
+
+	impl.mdi.SendServicerAttachMessage(talkID, servicer.GetUserID(), transfer.FromServicerID)
+
+	impl.sendTransferSystemMessage(ctx, &result, defs.TalkSystemEventTalkTransferred, servicer.GetUserID())
+
+	// the offer is resolved once the talk is attached and the history has it
+	impl.mdi.SendTalkTransferMessage(&result)
+}
+
+//
+//
+//
+
+// sweepTransfers expires the pending offers now and every defTransferSweepInterval after,
+// for the offers outliving the instances they were told to.
+func (impl *servicerMDImpl) sweepTransfers() {
+	impl.mrRunner.Post(func() {
+		impl.expireTransfers(context.TODO())
+	})
+
+	time.AfterFunc(defTransferSweepInterval, impl.sweepTransfers)
+}
+
+func (impl *servicerMDImpl) expireTransfers(ctx context.Context) {
+	transfers, err := impl.mdi.GetM().GetExpiredTalkTransfers(ctx, time.Now().UnixMilli(), defTransferSweepBatch)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("GetExpiredTalkTransfersFailed")
+
+		return
+	}
+
+	for _, transfer := range transfers {
+		impl.expireTransfer(ctx, transfer)
+	}
+}
+
+func (impl *servicerMDImpl) expireTransfer(ctx context.Context, transfer *defs.TalkTransfer) {
+	if !impl.claimTransfer(ctx, transfer) {
+		return
+	}
+
+	expired := *transfer
+	expired.State = defs.TalkTransferStateExpired
+
+	impl.sendTransferSystemMessage(ctx, &expired, defs.TalkSystemEventTransferExpired, transfer.FromServicerID)
+
+	impl.mdi.SendTalkTransferMessage(&expired)
+}
+
+// claimTransfer removes the pending offer, ok is false if it's resolved already.
+func (impl *servicerMDImpl) claimTransfer(ctx context.Context, transfer *defs.TalkTransfer) (ok bool) {
+	ok, err := impl.mdi.GetM().RemoveTalkTransfer(ctx, transfer.TalkID, transfer.ExpireAt)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", transfer.TalkID)).
+			Error("RemoveTalkTransferFailed")
+	}
+
+	return
+}
+
+// sendTransferSystemMessage keeps the transfer result in the history of the talk, servicerID is the sender.
+func (impl *servicerMDImpl) sendTransferSystemMessage(ctx context.Context, result *defs.TalkTransfer,
+	event defs.TalkSystemEvent, servicerID uint64) {
+	detail, _ := json.Marshal(&defs.TalkTransferDetail{
+		FromServicerID: result.FromServicerID,
+		ToServicerID:   result.ToServicerID,
+		ToTeam:         result.ToTeam,
+		Reason:         result.Reason,
+	})
+
+	impl.sendSystemMessageEx(ctx, result.TalkID, event, servicerID, detail)
+}
+
+// finishTransfer tells the result to the servicers of this instance.
+func (impl *servicerMDImpl) finishTransfer(result *defs.TalkTransfer) {
+	msg := transferResultNotify(result)
+
+	impl.send4AllOneServicer(result.FromServicerID, func(servicer defs.Servicer) error {
+		impl.sendNotify(servicer, msg)
+
+		return nil
+	})
+
+	// the team offer stays open to the other members
+	if result.State == defs.TalkTransferStateDeclined && result.ToServicerID == 0 {
+		return
+	}
+
+	impl.send4TransferTargets(result, func(servicer defs.Servicer) error {
+		impl.sendNotify(servicer, msg)
+
+		return nil
+	})
+}
+
+func (impl *servicerMDImpl) isTransferTarget(transfer *defs.TalkTransfer, servicerID uint64) bool {
+	if servicerID == transfer.FromServicerID {
+		return false
+	}
+
+	if transfer.ToServicerID != 0 {
+		return servicerID == transfer.ToServicerID
+	}
+
+	for _, skill := range impl.routing.ServicerSkills[servicerID] {
+		if skill == transfer.ToTeam {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (impl *servicerMDImpl) hasOnlineTransferTarget(transfer *defs.TalkTransfer) bool {
+	for servicerID, presence := range impl.presences {
+		if presence == defs.ServicerPresenceOnline && impl.isTransferTarget(transfer, servicerID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (impl *servicerMDImpl) send4TransferTargets(transfer *defs.TalkTransfer, do func(defs.Servicer) error) {
+	for servicerID := range impl.servicers {
+		if impl.isTransferTarget(transfer, servicerID) {
+			impl.send4AllOneServicer(servicerID, do)
+		}
+	}
+}
+
+func transferResultNotify(transfer *defs.TalkTransfer) string {
+	switch transfer.State {
+	case defs.TalkTransferStateAccepted:
+		return fmt.Sprintf("%s:%s:%d", notifyTransferAccepted, transfer.TalkID, transfer.ByServicerID)
+	case defs.TalkTransferStateDeclined:
+		return fmt.Sprintf("%s:%s:%d", notifyTransferDeclined, transfer.TalkID, transfer.ByServicerID)
+	case defs.TalkTransferStateExpired:
+		return fmt.Sprintf("%s:%s", notifyTransferExpired, transfer.TalkID)
+	default:
+		return fmt.Sprintf("%s:%s", notifyTransferCanceled, transfer.TalkID)
+	}
+}
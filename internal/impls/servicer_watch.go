@@ -0,0 +1,139 @@
+package impls
+
+import (
+	"context"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/vo"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/sgostarter/i/l"
+)
+
+// OnWhisperMessage sends the whisper to the attached servicer and the supervisor whispering only.
+func (impl *servicerMDImpl) OnWhisperMessage(talkID string, message *defs.TalkMessageW) {
+	impl.mrRunner.Post(func() {
+		impl.sendResponseToServicersForTalkEx(0, message.SenderID, talkID, &customertalkpb.ServiceResponse{
+			Response: &customertalkpb.ServiceResponse_Message{
+				Message: &customertalkpb.ServiceTalkMessageResponse{
+					TalkId:  talkID,
+					Message: vo.WhisperMessageDB2Pb4Servicer(message),
+				},
+			},
+		})
+	})
+}
+
+func (impl *servicerMDImpl) ServicerWatchTalk(ctx context.Context, servicer defs.Servicer, talkID string,
+	mode defs.TalkWatchMode) {
+	if talkID == "" || servicer == nil || !mode.Valid() {
+		impl.logger.WithFields(l.StringField("talkID", talkID), l.IntField("mode", int(mode))).
+			Error("noServicerOrTalkIDOrInvalidMode")
+
+		return
+	}
+
+	if !servicer.IsSupervisor() {
+		impl.sendNotify(servicer, notifyPermissionDenied)
+
+		return
+	}
+
+	oldMode := servicer.GetTalkWatchMode(talkID)
+	if mode == oldMode {
+		return
+	}
+
+	if mode == defs.TalkWatchModeNone {
+		impl.unwatchTalk(ctx, servicer, talkID)
+	} else {
+		talkInfo, err := impl.mdi.GetM().GetTalkInfo(ctx, talkID)
+		if err != nil {
+			impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("GetTalkInfoFailed")
+
+			return
+		}
+
+		// only the messages of the inactive talks can be read, nothing is written into them
+		if talkInfo.Status.Finished() || (mode != defs.TalkWatchModeMonitor && !talkInfo.Status.Opened()) {
+			impl.sendNotify(servicer, notifyTalkClosed)
+
+			return
+		}
+
+		servicer.SetTalkWatchMode(talkID, mode)
+
+		if _, ok := impl.watchers[talkID]; !ok {
+			impl.watchers[talkID] = make(map[uint64]defs.Servicer)
+		}
+
+		impl.watchers[talkID][servicer.GetUniqueID()] = servicer
+
+		_ = impl.mdi.AddTrackTalk(ctx, talkID)
+	}
+
+	impl.logger.WithFields(l.StringField("audit", "watchTalk"), l.StringField("talkID", talkID),
+		l.UInt64Field("supervisorID", servicer.GetUserID()), l.IntField("mode", int(mode))).Info("TalkWatched")
+
+	if oldMode == defs.TalkWatchModeNone {
+		impl.ServicerReloadTalk(ctx, servicer, talkID, nil)
+	}
+
+	if mode == defs.TalkWatchModeBarge {
+		impl.sendSystemMessage(ctx, talkID, defs.TalkSystemEventServicerJoined, servicer.GetUserID())
+	} else if oldMode == defs.TalkWatchModeBarge {
+		impl.sendSystemMessage(ctx, talkID, defs.TalkSystemEventServicerLeft, servicer.GetUserID())
+	}
+}
+
+func (impl *servicerMDImpl) ServicerWhisper(_ context.Context, servicer defs.Servicer, talkID string,
+	message *defs.TalkMessageW) {
+	if talkID == "" || servicer == nil || message == nil {
+		impl.logger.Error("nilParameters")
+
+		return
+	}
+
+	if !servicer.IsSupervisor() {
+		impl.sendNotify(servicer, notifyPermissionDenied)
+
+		return
+	}
+
+	if servicer.GetTalkWatchMode(talkID) == defs.TalkWatchModeNone {
+		impl.sendNotify(servicer, notifyTalkNotWatched)
+
+		return
+	}
+
+	impl.mdi.SendWhisperMessage(talkID, message)
+}
+
+//
+//
+//
+
+func (impl *servicerMDImpl) unwatchTalk(ctx context.Context, servicer defs.Servicer, talkID string) {
+	servicer.SetTalkWatchMode(talkID, defs.TalkWatchModeNone)
+
+	watchers := impl.watchers[talkID]
+
+	delete(watchers, servicer.GetUniqueID())
+
+	if len(watchers) > 0 {
+		return
+	}
+
+	delete(impl.watchers, talkID)
+
+	servicerID, err := impl.mdi.GetM().GetTalkServicerID(ctx, talkID)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err), l.StringField("talkID", talkID)).Error("GetTalkServicerIDFailed")
+
+		return
+	}
+
+	// the talk is still tracked for the attached servicer
+	if _, ok := impl.servicers[servicerID]; !ok {
+		impl.mdi.RemoveTrackTalk(ctx, talkID)
+	}
+}
@@ -0,0 +1,47 @@
+package impls
+
+import (
+	"context"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/libeasygo/commerr"
+)
+
+// ExportTalk loads the talk with every message of it, the notes kept from the customers included.
+func ExportTalk(ctx context.Context, m defs.Model, talkID string) (export *defs.TalkExport, err error) {
+	talkInfos, err := m.QueryTalks(ctx, 0, 0, talkID, nil, nil)
+	if err != nil {
+		return
+	}
+
+	if len(talkInfos) == 0 {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	export = &defs.TalkExport{
+		TalkInfo: talkInfos[0],
+	}
+
+	var messageID string
+
+	for {
+		var messages []*defs.TalkMessageR
+
+		messages, err = m.GetTalkMessagesByCursor(ctx, talkID, messageID, false, defMaxQueryMessageCount, nil)
+		if err != nil {
+			return
+		}
+
+		export.Messages = append(export.Messages, messages...)
+
+		if len(messages) < defMaxQueryMessageCount {
+			break
+		}
+
+		messageID = messages[len(messages)-1].MessageID
+	}
+
+	return
+}
@@ -0,0 +1,47 @@
+package impls
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/sgostarter/libeasygo/commerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportTalk(t *testing.T) {
+	ctx := context.Background()
+
+	m := model.NewMemoryModel()
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status: defs.TalkStatusQueued,
+		Title:  "export",
+	})
+	assert.Nil(t, err)
+
+	// more than a page, every tenth one a note
+	for idx := 0; idx < defMaxQueryMessageCount*2+5; idx++ {
+		messageType := defs.TalkMessageTypeText
+		if idx%10 == 0 {
+			messageType = defs.TalkMessageTypeNote
+		}
+
+		err = m.AddTalkMessage(ctx, talkID, &defs.TalkMessageW{
+			At:   int64(idx),
+			Type: messageType,
+		})
+		assert.Nil(t, err)
+	}
+
+	export, err := ExportTalk(ctx, m, talkID)
+	assert.Nil(t, err)
+	assert.EqualValues(t, talkID, export.TalkInfo.TalkID)
+	assert.EqualValues(t, defMaxQueryMessageCount*2+5, len(export.Messages))
+	assert.EqualValues(t, defs.TalkMessageTypeNote, export.Messages[0].Type)
+	assert.EqualValues(t, defMaxQueryMessageCount*2+4, export.Messages[len(export.Messages)-1].At)
+
+	_, err = ExportTalk(ctx, m, "000000000000000000000000")
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+}
@@ -0,0 +1,89 @@
+package impls
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/libeasygo/commerr"
+)
+
+// AggregateTalkRatings groups the rated talks by the servicer and the period they're rated in, the periods start
+// in loc, stats are ordered by the period and then the servicer.
+func AggregateTalkRatings(talkInfos []*defs.TalkInfoR, period defs.TalkRatingPeriod,
+	loc *time.Location) (stats []*defs.TalkRatingStat, err error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	type statKey struct {
+		servicerID  uint64
+		periodStart int64
+	}
+
+	statMap := make(map[statKey]*defs.TalkRatingStat)
+
+	for _, talkInfo := range talkInfos {
+		if !talkInfo.Rated() || talkInfo.RatingScore < defs.TalkRatingScoreMin ||
+			talkInfo.RatingScore > defs.TalkRatingScoreMax {
+			continue
+		}
+
+		var periodStart int64
+
+		periodStart, err = talkRatingPeriodStart(talkInfo.RatedAt, period, loc)
+		if err != nil {
+			return
+		}
+
+		key := statKey{servicerID: talkInfo.ServiceID, periodStart: periodStart}
+
+		stat, ok := statMap[key]
+		if !ok {
+			stat = &defs.TalkRatingStat{
+				ServicerID:  talkInfo.ServiceID,
+				PeriodStart: periodStart,
+			}
+			statMap[key] = stat
+
+			stats = append(stats, stat)
+		}
+
+		stat.Count++
+		stat.ScoreSum += talkInfo.RatingScore
+		stat.ScoreCounts[talkInfo.RatingScore-1]++
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].PeriodStart != stats[j].PeriodStart {
+			return stats[i].PeriodStart < stats[j].PeriodStart
+		}
+
+		return stats[i].ServicerID < stats[j].ServicerID
+	})
+
+	return
+}
+
+//
+//
+//
+
+func talkRatingPeriodStart(at int64, period defs.TalkRatingPeriod, loc *time.Location) (periodStart int64, err error) {
+	t := time.Unix(at, 0).In(loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+
+	switch period {
+	case defs.TalkRatingPeriodDay:
+		periodStart = day.Unix()
+	case defs.TalkRatingPeriodWeek:
+		periodStart = day.AddDate(0, 0, -(int(day.Weekday())+6)%7).Unix()
+	case defs.TalkRatingPeriodMonth:
+		periodStart = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Unix()
+	default:
+		err = fmt.Errorf("%w: unknown rating period %s", commerr.ErrInvalidArgument, period)
+	}
+
+	return
+}
@@ -0,0 +1,121 @@
+package impls
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/controller"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestAggregateTalkRatings(t *testing.T) {
+	loc := time.FixedZone("ut", 8*3600)
+
+	// 2026-10-12 is a monday
+	at := func(day, hour int) int64 {
+		return time.Date(2026, 10, day, hour, 0, 0, 0, loc).Unix()
+	}
+
+	rated := func(servicerID uint64, score int, ratedAt int64) *defs.TalkInfoR {
+		return &defs.TalkInfoR{TalkInfoW: defs.TalkInfoW{ServiceID: servicerID, RatingScore: score, RatedAt: ratedAt}}
+	}
+
+	talkInfos := []*defs.TalkInfoR{
+		rated(2, 5, at(12, 9)),
+		rated(1, 4, at(12, 23)),
+		rated(1, 2, at(13, 1)),
+		rated(1, 5, at(18, 23)),
+		rated(1, 3, at(19, 0)),
+		{TalkInfoW: defs.TalkInfoW{ServiceID: 1}},
+	}
+
+	stats, err := AggregateTalkRatings(talkInfos, defs.TalkRatingPeriodDay, loc)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, len(stats))
+	assert.EqualValues(t, at(12, 0), stats[0].PeriodStart)
+	assert.EqualValues(t, 1, stats[0].ServicerID)
+	assert.EqualValues(t, 2, stats[1].ServicerID)
+
+	stats, err = AggregateTalkRatings(talkInfos, defs.TalkRatingPeriodWeek, loc)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(stats))
+	assert.EqualValues(t, at(12, 0), stats[0].PeriodStart)
+	assert.EqualValues(t, 1, stats[0].ServicerID)
+	assert.EqualValues(t, 3, stats[0].Count)
+	assert.InDelta(t, 3.67, stats[0].Average(), 0.01)
+	assert.EqualValues(t, [defs.TalkRatingScoreMax]int{0, 1, 0, 1, 1}, stats[0].ScoreCounts)
+	assert.EqualValues(t, at(19, 0), stats[2].PeriodStart)
+
+	stats, err = AggregateTalkRatings(talkInfos, defs.TalkRatingPeriodMonth, loc)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(stats))
+	assert.EqualValues(t, time.Date(2026, 10, 1, 0, 0, 0, 0, loc).Unix(), stats[0].PeriodStart)
+	assert.EqualValues(t, 4, stats[0].Count)
+
+	_, err = AggregateTalkRatings(talkInfos, "year", loc)
+	assert.NotNil(t, err)
+}
+
+func TestCustomerMDRatingPrompt(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewModelEx(model.NewMemoryModel())
+
+	mdi := NewAllInOneMDI(m, nil)
+	mdi.SetServicerObserver(&utObserver{})
+
+	customerMD := NewCustomerMD(mdi, nil)
+	customerMD.Setup(utMainRoutineRunner{})
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued, CreatorID: 1})
+	assert.Nil(t, err)
+
+	chCustomer := make(chan *customertalkpb.TalkResponse, 100)
+	customer := controller.NewCustomer(1, talkID, false, 1, nil, chCustomer)
+	customerMD.InstallCustomer(ctx, customer)
+
+	ratingPrompted := func() (prompted bool) {
+		for {
+			select {
+			case resp := <-chCustomer:
+				d := resp.ProtoReflect().GetUnknown()
+				if len(d) == 0 {
+					continue
+				}
+
+				if num, _, _ := protowire.ConsumeTag(d); num == 9 {
+					prompted = true
+				}
+			case <-time.After(100 * time.Millisecond):
+				return
+			}
+		}
+	}
+
+	// nobody served it
+	customerMD.CustomerClose(ctx, customer)
+	assert.False(t, ratingPrompted())
+
+	talkID, err = m.CreateTalk(ctx, &defs.TalkInfoW{Status: defs.TalkStatusQueued, CreatorID: 1})
+	assert.Nil(t, err)
+
+	_, _, err = m.CompareAndSetTalkServiceID(ctx, talkID, 0, 2)
+	assert.Nil(t, err)
+	assert.Nil(t, m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusAssigned))
+
+	customer = controller.NewCustomer(2, talkID, false, 1, nil, chCustomer)
+	customerMD.InstallCustomer(ctx, customer)
+
+	customerMD.CustomerClose(ctx, customer)
+	assert.True(t, ratingPrompted())
+
+	assert.Nil(t, m.RateTalk(ctx, talkID, 5, ""))
+
+	mdi.SendTalkCloseMessage(talkID)
+	assert.False(t, ratingPrompted())
+}
@@ -0,0 +1,332 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/i/l"
+	"github.com/sgostarter/libeasygo/commerr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const collectionCannedResponses = "canned_responses"
+
+// NewCannedResponseModel keeps the responses next to the talks, the sql model types have no store for them yet
+// and keep them in memory.
+func NewCannedResponseModel(cfg *config.Config, logger l.Wrapper) defs.CannedResponseModel {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	if cfg == nil {
+		logger.Fatal("NoCfgOnCreateCannedResponseModel")
+
+		return nil
+	}
+
+	switch cfg.ModelType {
+	case config.ModelTypeMemory:
+		return NewMemoryCannedResponseModel()
+	case config.ModelTypeSQLite, config.ModelTypePostgres:
+		logger.WithFields(l.StringField("modelType", cfg.ModelType)).Warn("CannedResponsesKeptInMemory")
+
+		return NewMemoryCannedResponseModel()
+	case "", config.ModelTypeMongo:
+		return NewMongoCannedResponseModel(&cfg.MongoConfig, logger)
+	default:
+		logger.WithFields(l.StringField("modelType", cfg.ModelType)).Fatal("UnknownModelType")
+
+		return nil
+	}
+}
+
+func validCannedResponse(response *defs.CannedResponse) bool {
+	return response != nil && response.Shortcut != "" && response.Text != "" &&
+		(response.OwnerID == 0) != (response.Team == "")
+}
+
+//
+// memory
+//
+
+func NewMemoryCannedResponseModel() defs.CannedResponseModel {
+	return &memoryCannedResponseModelImpl{
+		responses: make(map[string]*defs.CannedResponse),
+	}
+}
+
+type memoryCannedResponseModelImpl struct {
+	lock      sync.RWMutex
+	responses map[string]*defs.CannedResponse
+}
+
+func (m *memoryCannedResponseModelImpl) CreateCannedResponse(_ context.Context, response *defs.CannedResponse) (err error) {
+	if !validCannedResponse(response) {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.shortcutTakenUnderLock(response) {
+		err = commerr.ErrAlreadyExists
+
+		return
+	}
+
+	response.ID = primitive.NewObjectID().Hex()
+	response.UpdatedAt = time.Now().UnixMilli()
+
+	r := *response
+	m.responses[r.ID] = &r
+
+	return
+}
+
+func (m *memoryCannedResponseModelImpl) UpdateCannedResponse(_ context.Context, response *defs.CannedResponse) (err error) {
+	if response == nil || response.Shortcut == "" || response.Text == "" {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	r, ok := m.responses[response.ID]
+	if !ok {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	response.OwnerID = r.OwnerID
+	response.Team = r.Team
+
+	if m.shortcutTakenUnderLock(response) {
+		err = commerr.ErrAlreadyExists
+
+		return
+	}
+
+	response.UpdatedAt = time.Now().UnixMilli()
+
+	*r = *response
+
+	return
+}
+
+func (m *memoryCannedResponseModelImpl) DeleteCannedResponse(_ context.Context, id string) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.responses[id]; !ok {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	delete(m.responses, id)
+
+	return
+}
+
+func (m *memoryCannedResponseModelImpl) GetCannedResponse(_ context.Context, id string) (response *defs.CannedResponse, err error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	r, ok := m.responses[id]
+	if !ok {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	response = &defs.CannedResponse{}
+	*response = *r
+
+	return
+}
+
+func (m *memoryCannedResponseModelImpl) ListCannedResponses(_ context.Context, ownerID uint64, teams []string) (
+	responses []*defs.CannedResponse, err error) {
+	teamSet := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		teamSet[team] = true
+	}
+
+	m.lock.RLock()
+
+	for _, r := range m.responses {
+		if r.Shared() && teamSet[r.Team] || !r.Shared() && r.OwnerID == ownerID {
+			response := *r
+			responses = append(responses, &response)
+		}
+	}
+
+	m.lock.RUnlock()
+
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].Shortcut < responses[j].Shortcut
+	})
+
+	return
+}
+
+// shortcutTakenUnderLock checks another response of the same owner or team has the shortcut.
+func (m *memoryCannedResponseModelImpl) shortcutTakenUnderLock(response *defs.CannedResponse) bool {
+	for _, r := range m.responses {
+		if r.ID != response.ID && r.OwnerID == response.OwnerID && r.Team == response.Team &&
+			r.Shortcut == response.Shortcut {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// mongo
+//
+
+func NewMongoCannedResponseModel(cfg *config.MongoConfig, logger l.Wrapper) defs.CannedResponseModel {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	if cfg == nil {
+		logger.Fatal("NoCfgOnCreateCannedResponseModel")
+
+		return nil
+	}
+
+	m := &mongoCannedResponseModelImpl{
+		collection: newMongoClient(cfg, logger).Database(cfg.DB).Collection(collectionCannedResponses),
+	}
+
+	if _, err := m.collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "OwnerID", Value: 1},
+			{Key: "Team", Value: 1},
+			{Key: "Shortcut", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		logger.WithFields(l.ErrorField(err)).Error("CreateCannedResponsesIndexFailed")
+	}
+
+	return m
+}
+
+type mongoCannedResponseModelImpl struct {
+	collection *mongo.Collection
+}
+
+func (m *mongoCannedResponseModelImpl) CreateCannedResponse(ctx context.Context, response *defs.CannedResponse) (err error) {
+	if !validCannedResponse(response) {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	response.ID = primitive.NewObjectID().Hex()
+	response.UpdatedAt = time.Now().UnixMilli()
+
+	_, err = m.collection.InsertOne(ctx, response)
+	if mongo.IsDuplicateKeyError(err) {
+		err = commerr.ErrAlreadyExists
+	}
+
+	return
+}
+
+func (m *mongoCannedResponseModelImpl) UpdateCannedResponse(ctx context.Context, response *defs.CannedResponse) (err error) {
+	if response == nil || response.Shortcut == "" || response.Text == "" {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	updatedAt := time.Now().UnixMilli()
+
+	err = m.collection.FindOneAndUpdate(ctx, bson.M{"_id": response.ID}, bson.M{
+		"$set": bson.M{
+			"Shortcut":  response.Shortcut,
+			"Title":     response.Title,
+			"Text":      response.Text,
+			"UpdatedAt": updatedAt,
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(response)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = commerr.ErrNotFound
+		} else if mongo.IsDuplicateKeyError(err) {
+			err = commerr.ErrAlreadyExists
+		}
+
+		return
+	}
+
+	return
+}
+
+func (m *mongoCannedResponseModelImpl) DeleteCannedResponse(ctx context.Context, id string) (err error) {
+	r, err := m.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return
+	}
+
+	if r.DeletedCount == 0 {
+		err = commerr.ErrNotFound
+	}
+
+	return
+}
+
+func (m *mongoCannedResponseModelImpl) GetCannedResponse(ctx context.Context, id string) (response *defs.CannedResponse, err error) {
+	response = &defs.CannedResponse{}
+
+	err = m.collection.FindOne(ctx, bson.M{"_id": id}).Decode(response)
+	if err != nil {
+		response = nil
+
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = commerr.ErrNotFound
+		}
+
+		return
+	}
+
+	return
+}
+
+func (m *mongoCannedResponseModelImpl) ListCannedResponses(ctx context.Context, ownerID uint64, teams []string) (
+	responses []*defs.CannedResponse, err error) {
+	filter := bson.M{"OwnerID": ownerID, "Team": ""}
+
+	if len(teams) > 0 {
+		filter = bson.M{"$or": bson.A{
+			filter,
+			bson.M{"OwnerID": 0, "Team": bson.M{"$in": teams}},
+		}}
+	}
+
+	cursor, err := m.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "Shortcut", Value: 1}}))
+	if err != nil {
+		return
+	}
+
+	err = cursor.All(ctx, &responses)
+
+	return
+}
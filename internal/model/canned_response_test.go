@@ -0,0 +1,60 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/libeasygo/commerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCannedResponseModel(t *testing.T) {
+	testCannedResponseModel(t, NewMemoryCannedResponseModel())
+}
+
+func testCannedResponseModel(t *testing.T, m defs.CannedResponseModel) {
+	ctx := context.Background()
+
+	personal := &defs.CannedResponse{OwnerID: 1, Shortcut: "/hi", Title: "hi", Text: "你好，{{customer_name}}"}
+	assert.Nil(t, m.CreateCannedResponse(ctx, personal))
+	assert.NotEmpty(t, personal.ID)
+
+	shared := &defs.CannedResponse{Team: "billing", Shortcut: "/hi", Text: "billing"}
+	assert.Nil(t, m.CreateCannedResponse(ctx, shared))
+
+	assert.ErrorIs(t, m.CreateCannedResponse(ctx, &defs.CannedResponse{OwnerID: 1, Shortcut: "/hi", Text: "again"}),
+		commerr.ErrAlreadyExists)
+	assert.ErrorIs(t, m.CreateCannedResponse(ctx, &defs.CannedResponse{OwnerID: 1, Team: "billing", Shortcut: "/x", Text: "x"}),
+		commerr.ErrInvalidArgument)
+	assert.Nil(t, m.CreateCannedResponse(ctx, &defs.CannedResponse{OwnerID: 2, Shortcut: "/hi", Text: "other"}))
+
+	bye := &defs.CannedResponse{OwnerID: 1, Shortcut: "/bye", Text: "再见"}
+	assert.Nil(t, m.CreateCannedResponse(ctx, bye))
+
+	responses, err := m.ListCannedResponses(ctx, 1, []string{"billing"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(responses))
+	assert.EqualValues(t, bye.ID, responses[0].ID)
+
+	responses, err = m.ListCannedResponses(ctx, 1, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(responses))
+
+	assert.ErrorIs(t, m.UpdateCannedResponse(ctx, &defs.CannedResponse{ID: bye.ID, Shortcut: "/hi", Text: "dup"}),
+		commerr.ErrAlreadyExists)
+
+	update := &defs.CannedResponse{ID: bye.ID, Shortcut: "/bye", Title: "bye", Text: "再见，{{customer_name}}"}
+	assert.Nil(t, m.UpdateCannedResponse(ctx, update))
+	assert.EqualValues(t, 1, update.OwnerID)
+
+	response, err := m.GetCannedResponse(ctx, bye.ID)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "bye", response.Title)
+
+	assert.Nil(t, m.DeleteCannedResponse(ctx, bye.ID))
+	assert.ErrorIs(t, m.DeleteCannedResponse(ctx, bye.ID), commerr.ErrNotFound)
+
+	_, err = m.GetCannedResponse(ctx, bye.ID)
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+}
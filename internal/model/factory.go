@@ -0,0 +1,61 @@
+package model
+
+import (
+	"github.com/sbasestarter/bizinters/userinters/userpass"
+	"github.com/sbasestarter/bizmongolib/mongolib"
+	userpassauthenticator "github.com/sbasestarter/bizmongolib/user/authenticator/userpass"
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/i/l"
+)
+
+func NewModel(cfg *config.Config, logger l.Wrapper) defs.Model {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	if cfg == nil {
+		logger.Fatal("NoCfgOnCreateModel")
+
+		return nil
+	}
+
+	switch cfg.ModelType {
+	case config.ModelTypeMemory:
+		return NewMemoryModel()
+	case config.ModelTypeSQLite, config.ModelTypePostgres:
+		return NewSQLModel(cfg.ModelType, cfg.SQLDSN, logger)
+	case "", config.ModelTypeMongo:
+		return NewMongoModel(&cfg.MongoConfig, logger)
+	default:
+		logger.WithFields(l.StringField("modelType", cfg.ModelType)).Fatal("UnknownModelType")
+
+		return nil
+	}
+}
+
+// NewUserPasswordModel keeps servicer accounts next to the talks for sql model types, so allinone needs no mongo.
+func NewUserPasswordModel(cfg *config.Config, name string, logger l.Wrapper) userpass.UserPasswordModel {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	if cfg == nil {
+		logger.Fatal("NoCfgOnCreateUserPasswordModel")
+
+		return nil
+	}
+
+	if _, ok := sqlDialectByModelType(cfg.ModelType); ok {
+		return NewSQLUserPasswordModel(cfg.ModelType, cfg.SQLDSN, name, logger)
+	}
+
+	mongoCli, mongoOptions, err := mongolib.InitMongo(cfg.UserMongoDSN)
+	if err != nil {
+		logger.WithFields(l.ErrorField(err)).Fatal("InitMongoFailed")
+
+		return nil
+	}
+
+	return userpassauthenticator.NewMongoUserPasswordModel(mongoCli, mongoOptions.Auth.AuthSource, name, logger)
+}
@@ -0,0 +1,623 @@
+package model
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/libeasygo/commerr"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewMemoryModel() defs.Model {
+	return &memoryModelImpl{
+		talkInfos:    make(map[string]*defs.TalkInfoR),
+		talkMessages: make(map[string][]*defs.TalkMessageR),
+		outbox:       make(map[memoryOutboxKey]int64),
+		transfers:    make(map[string]*defs.TalkTransfer),
+	}
+}
+
+type memoryOutboxKey struct {
+	talkID string
+	seq    uint64
+}
+
+type memoryModelImpl struct {
+	lock sync.RWMutex
+
+	talkIDs      []string // keep the insert order like mongo's natural order
+	talkInfos    map[string]*defs.TalkInfoR
+	talkMessages map[string][]*defs.TalkMessageR
+	outbox       map[memoryOutboxKey]int64 // lease until in unix milliseconds
+	transfers    map[string]*defs.TalkTransfer
+}
+
+func (m *memoryModelImpl) CreateTalk(_ context.Context, talkInfo *defs.TalkInfoW) (talkID string, err error) {
+	if talkInfo == nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	talkID = primitive.NewObjectID().Hex()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.talkIDs = append(m.talkIDs, talkID)
+	m.talkInfos[talkID] = m.cloneTalkInfo(&defs.TalkInfoR{
+		TalkID:    talkID,
+		TalkInfoW: *talkInfo,
+	})
+
+	return
+}
+
+func (m *memoryModelImpl) OpenTalk(_ context.Context, talkID string) (err error) {
+	if err = m.checkTalkID(talkID); err != nil {
+		return
+	}
+
+	talkInfos, err := m.queryTalksEx(0, 0, talkID, nil, nil)
+	if err != nil {
+		return
+	}
+
+	status, reopen, err := reopenTalkStatus(talkInfos)
+	if err != nil || !reopen {
+		return
+	}
+
+	return m.transitTalkStatus(talkID, defs.TalkStatusesReopenable, status, nil)
+}
+
+func (m *memoryModelImpl) CloseTalk(ctx context.Context, talkID string) error {
+	return m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusClosed)
+}
+
+func (m *memoryModelImpl) UpdateTalkStatus(_ context.Context, talkID string, status defs.TalkStatus) (err error) {
+	return m.transitTalkStatus(talkID, defs.TalkStatusesTransitTo(status), status, nil)
+}
+
+func (m *memoryModelImpl) ResolveTalk(_ context.Context, talkID string, disposition, note string) (err error) {
+	return m.transitTalkStatus(talkID, defs.TalkStatusesTransitTo(defs.TalkStatusResolved), defs.TalkStatusResolved,
+		func(talkInfo *defs.TalkInfoR) {
+			talkInfo.Disposition = disposition
+			talkInfo.WrapUpNote = note
+		})
+}
+
+func (m *memoryModelImpl) RateTalk(_ context.Context, talkID string, score int, comment string) (err error) {
+	var rateErr error
+
+	err = m.updateTalkInfo(talkID, func(talkInfo *defs.TalkInfoR) {
+		if talkInfo.Rated() {
+			rateErr = commerr.ErrAlreadyExists
+
+			return
+		}
+
+		if !m.statusIn(talkInfo.Status, defs.TalkStatusesClosed) {
+			rateErr = defs.ErrInvalidTalkTransition
+
+			return
+		}
+
+		talkInfo.RatingScore = score
+		talkInfo.RatingComment = comment
+		talkInfo.RatedAt = time.Now().Unix()
+	})
+	if err == nil {
+		err = rateErr
+	}
+
+	return
+}
+
+func (m *memoryModelImpl) ClassifyTalk(_ context.Context, classification *defs.TalkClassification) (
+	talkInfo *defs.TalkInfoR, err error) {
+	if classification == nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	err = m.updateTalkInfo(classification.TalkID, func(ti *defs.TalkInfoR) {
+		ti.Classify(classification)
+
+		talkInfo = m.cloneTalkInfo(ti)
+	})
+
+	return
+}
+
+func (m *memoryModelImpl) AddTalkMessage(_ context.Context, talkID string, message *defs.TalkMessageW) (err error) {
+	if message == nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	if err = m.checkTalkID(talkID); err != nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.talkInfos[talkID]; !ok {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	if stored := m.findClientMessage(talkID, message); stored != nil {
+		*message = stored.TalkMessageW
+		err = commerr.ErrAlreadyExists
+
+		return
+	}
+
+	message.Seq = uint64(len(m.talkMessages[talkID]) + 1)
+
+	m.talkMessages[talkID] = append(m.talkMessages[talkID], &defs.TalkMessageR{
+		MessageID:    primitive.NewObjectID().Hex(),
+		TalkMessageW: *message,
+	})
+
+	m.outbox[memoryOutboxKey{talkID: talkID, seq: message.Seq}] = outboxFirstLeaseUntil()
+
+	return
+}
+
+func (m *memoryModelImpl) GetTalkMessages(_ context.Context, talkID string, offset, count int64) (messages []*defs.TalkMessageR, err error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	talkMessages := m.talkMessages[talkID]
+
+	if count > 0 {
+		if offset < 0 {
+			offset = 0
+		}
+
+		if offset >= int64(len(talkMessages)) {
+			return
+		}
+
+		end := offset + count
+		if end > int64(len(talkMessages)) {
+			end = int64(len(talkMessages))
+		}
+
+		talkMessages = talkMessages[offset:end]
+	}
+
+	messages = make([]*defs.TalkMessageR, 0, len(talkMessages))
+
+	for _, message := range talkMessages {
+		messageCopy := *message
+		messages = append(messages, &messageCopy)
+	}
+
+	return
+}
+
+func (m *memoryModelImpl) GetTalkMessagesByCursor(_ context.Context, talkID, messageID string, before bool,
+	count int64, filter *defs.TalkMessageFilter) (messages []*defs.TalkMessageR, err error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	talkMessages := m.talkMessages[talkID]
+
+	start, end := 0, len(talkMessages)
+
+	if messageID != "" {
+		if _, err = primitive.ObjectIDFromHex(messageID); err != nil {
+			err = commerr.ErrInvalidArgument
+
+			return
+		}
+
+		idx := -1
+
+		for i, message := range talkMessages {
+			if message.MessageID == messageID {
+				idx = i
+
+				break
+			}
+		}
+
+		if idx < 0 {
+			err = commerr.ErrNotFound
+
+			return
+		}
+
+		if before {
+			end = idx
+		} else {
+			start = idx + 1
+		}
+	}
+
+	matched := make([]*defs.TalkMessageR, 0, end-start)
+
+	for _, message := range talkMessages[start:end] {
+		if filter.Match(&message.TalkMessageW) {
+			matched = append(matched, message)
+		}
+	}
+
+	if count > 0 && int64(len(matched)) > count {
+		if before {
+			matched = matched[len(matched)-int(count):]
+		} else {
+			matched = matched[:count]
+		}
+	}
+
+	messages = make([]*defs.TalkMessageR, 0, len(matched))
+
+	for _, message := range matched {
+		messageCopy := *message
+		messages = append(messages, &messageCopy)
+	}
+
+	return
+}
+
+func (m *memoryModelImpl) GetTalkMessagesSince(_ context.Context, talkID string, at int64, count int64,
+	filter *defs.TalkMessageFilter) (messages []*defs.TalkMessageR, err error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	for _, message := range m.talkMessages[talkID] {
+		if message.At < at || !filter.Match(&message.TalkMessageW) {
+			continue
+		}
+
+		if count > 0 && int64(len(messages)) >= count {
+			break
+		}
+
+		messageCopy := *message
+		messages = append(messages, &messageCopy)
+	}
+
+	return
+}
+
+func (m *memoryModelImpl) QueryTalks(_ context.Context, creatorID, serviceID uint64, talkID string,
+	statuses []defs.TalkStatus, filter *defs.TalkFilter) (talks []*defs.TalkInfoR, err error) {
+	return m.queryTalksEx(creatorID, serviceID, talkID, statuses, func(talkInfo *defs.TalkInfoR) bool {
+		return filter.Match(talkInfo)
+	})
+}
+
+func (m *memoryModelImpl) SearchTalks(_ context.Context, search *defs.TalkSearch) (talks []*defs.TalkInfoR, err error) {
+	if search.After != nil {
+		if err = m.checkTalkID(search.After.TalkID); err != nil {
+			return
+		}
+	}
+
+	talks, err = m.queryTalksEx(search.CreatorID, search.ServiceID, "", search.Statuses, func(talkInfo *defs.TalkInfoR) bool {
+		return search.Filter.Match(talkInfo) && search.IsAfter(talkInfo)
+	})
+	if err != nil {
+		return
+	}
+
+	sort.Slice(talks, func(i, j int) bool {
+		return search.Before(talks[i], talks[j])
+	})
+
+	if search.Limit > 0 && len(talks) > search.Limit {
+		talks = talks[:search.Limit]
+	}
+
+	return
+}
+
+func (m *memoryModelImpl) GetPendingTalkInfos(_ context.Context) ([]*defs.TalkInfoR, error) {
+	return m.queryTalksEx(0, 0, "", []defs.TalkStatus{defs.TalkStatusQueued}, func(talkInfo *defs.TalkInfoR) bool {
+		return talkInfo.ServiceID == 0
+	})
+}
+
+func (m *memoryModelImpl) GetRatedTalkInfos(_ context.Context, from, to int64) ([]*defs.TalkInfoR, error) {
+	return m.queryTalksEx(0, 0, "", nil, func(talkInfo *defs.TalkInfoR) bool {
+		return talkInfo.Rated() && talkInfo.RatedAt >= from && talkInfo.RatedAt < to
+	})
+}
+
+func (m *memoryModelImpl) UpdateTalkServiceID(_ context.Context, talkID string, serviceID uint64) (err error) {
+	return m.updateTalkInfo(talkID, func(talkInfo *defs.TalkInfoR) {
+		talkInfo.ServiceID = serviceID
+	})
+}
+
+func (m *memoryModelImpl) CompareAndSetTalkServiceID(_ context.Context, talkID string, expectedServiceID, serviceID uint64) (swapped bool,
+	curServiceID uint64, err error) {
+	err = m.updateTalkInfo(talkID, func(talkInfo *defs.TalkInfoR) {
+		if talkInfo.ServiceID == expectedServiceID {
+			talkInfo.ServiceID = serviceID
+			swapped = true
+		}
+
+		curServiceID = talkInfo.ServiceID
+	})
+
+	return
+}
+
+func (m *memoryModelImpl) OfferTalkTransfer(_ context.Context, transfer *defs.TalkTransfer) (err error) {
+	if transfer == nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	if _, err = primitive.ObjectIDFromHex(transfer.TalkID); err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.transfers[transfer.TalkID]; ok {
+		err = commerr.ErrAlreadyExists
+
+		return
+	}
+
+	transferCopy := *transfer
+	m.transfers[transfer.TalkID] = &transferCopy
+
+	return
+}
+
+func (m *memoryModelImpl) GetTalkTransfer(_ context.Context, talkID string) (transfer *defs.TalkTransfer, err error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	stored, ok := m.transfers[talkID]
+	if !ok {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	transferCopy := *stored
+	transfer = &transferCopy
+
+	return
+}
+
+func (m *memoryModelImpl) RemoveTalkTransfer(_ context.Context, talkID string, expireAt int64) (removed bool, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if stored, ok := m.transfers[talkID]; ok && stored.ExpireAt == expireAt {
+		delete(m.transfers, talkID)
+
+		removed = true
+	}
+
+	return
+}
+
+func (m *memoryModelImpl) GetExpiredTalkTransfers(_ context.Context, now int64, count int64) (
+	transfers []*defs.TalkTransfer, err error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	for _, stored := range m.transfers {
+		if stored.ExpireAt > now {
+			continue
+		}
+
+		transferCopy := *stored
+		transfers = append(transfers, &transferCopy)
+	}
+
+	sort.SliceStable(transfers, func(i, j int) bool {
+		return transfers[i].ExpireAt < transfers[j].ExpireAt
+	})
+
+	if count > 0 && int64(len(transfers)) > count {
+		transfers = transfers[:count]
+	}
+
+	return
+}
+
+func (m *memoryModelImpl) LeaseOutboxMessages(_ context.Context, now, leaseUntil int64, count int64) (messages []*defs.OutboxMessage, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for key, lease := range m.outbox {
+		if count > 0 && int64(len(messages)) >= count {
+			break
+		}
+
+		if lease > now {
+			continue
+		}
+
+		m.outbox[key] = leaseUntil
+
+		messages = append(messages, &defs.OutboxMessage{
+			TalkID:       key.talkID,
+			TalkMessageR: *m.talkMessages[key.talkID][key.seq-1],
+		})
+	}
+
+	return
+}
+
+func (m *memoryModelImpl) AckOutboxMessage(_ context.Context, talkID string, seq uint64) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.outbox, memoryOutboxKey{talkID: talkID, seq: seq})
+
+	return
+}
+
+//
+//
+//
+
+// findClientMessage returns the message the sender of message stored with the same ClientSeqID.
+func (m *memoryModelImpl) findClientMessage(talkID string, message *defs.TalkMessageW) *defs.TalkMessageR {
+	if message.ClientSeqID == 0 {
+		return nil
+	}
+
+	for _, stored := range m.talkMessages[talkID] {
+		if stored.ClientSeqID == message.ClientSeqID && stored.SenderID == message.SenderID &&
+			stored.CustomerMessage == message.CustomerMessage {
+			return stored
+		}
+	}
+
+	return nil
+}
+
+func (m *memoryModelImpl) checkTalkID(talkID string) error {
+	if _, err := primitive.ObjectIDFromHex(talkID); err != nil {
+		return commerr.ErrInvalidArgument
+	}
+
+	return nil
+}
+
+func (m *memoryModelImpl) queryTalksEx(creatorID, serviceID uint64, talkID string, statuses []defs.TalkStatus,
+	filter func(talkInfo *defs.TalkInfoR) bool) (talks []*defs.TalkInfoR, err error) {
+	if talkID != "" {
+		if err = m.checkTalkID(talkID); err != nil {
+			return
+		}
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	for _, id := range m.talkIDs {
+		talkInfo := m.talkInfos[id]
+
+		if talkID != "" && talkInfo.TalkID != talkID {
+			continue
+		}
+
+		if creatorID > 0 && talkInfo.CreatorID != creatorID {
+			continue
+		}
+
+		if serviceID > 0 && talkInfo.ServiceID != serviceID {
+			continue
+		}
+
+		if len(statuses) > 0 && !m.statusIn(talkInfo.Status, statuses) {
+			continue
+		}
+
+		if filter != nil && !filter(talkInfo) {
+			continue
+		}
+
+		talks = append(talks, m.cloneTalkInfo(talkInfo))
+	}
+
+	return
+}
+
+// cloneTalkInfo copies the slices and the maps too, the stored talks are never shared with the callers.
+func (m *memoryModelImpl) cloneTalkInfo(talkInfo *defs.TalkInfoR) *defs.TalkInfoR {
+	talkInfoCopy := *talkInfo
+
+	talkInfoCopy.Tags = cloneStrings(talkInfo.Tags)
+	talkInfoCopy.FormFields = cloneStringMap(talkInfo.FormFields)
+	talkInfoCopy.CustomFields = cloneStringMap(talkInfo.CustomFields)
+
+	return &talkInfoCopy
+}
+
+func (m *memoryModelImpl) statusIn(status defs.TalkStatus, statuses []defs.TalkStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *memoryModelImpl) transitTalkStatus(talkID string, from []defs.TalkStatus, to defs.TalkStatus,
+	update func(talkInfo *defs.TalkInfoR)) (err error) {
+	var transitErr error
+
+	err = m.updateTalkInfo(talkID, func(talkInfo *defs.TalkInfoR) {
+		if !m.statusIn(talkInfo.Status, from) {
+			transitErr = defs.ErrInvalidTalkTransition
+
+			return
+		}
+
+		talkInfo.Status = to
+
+		now := time.Now().Unix()
+
+		switch to {
+		case defs.TalkStatusQueued:
+			talkInfo.QueuedAt = now
+		case defs.TalkStatusAssigned:
+			if talkInfo.FirstAssignedAt == 0 {
+				talkInfo.FirstAssignedAt = now
+			}
+		case defs.TalkStatusWaitingOnCustomer:
+			if talkInfo.FirstResponseAt == 0 {
+				talkInfo.FirstResponseAt = now
+			}
+		case defs.TalkStatusResolved, defs.TalkStatusClosed, defs.TalkStatusAbandoned:
+			talkInfo.FinishedAt = now
+		}
+
+		if update != nil {
+			update(talkInfo)
+		}
+	})
+	if err == nil {
+		err = transitErr
+	}
+
+	return
+}
+
+func (m *memoryModelImpl) updateTalkInfo(talkID string, update func(talkInfo *defs.TalkInfoR)) (err error) {
+	if err = m.checkTalkID(talkID); err != nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	talkInfo, ok := m.talkInfos[talkID]
+	if !ok {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	update(talkInfo)
+
+	return
+}
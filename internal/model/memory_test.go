@@ -0,0 +1,55 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryModel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testModel(ctx, t, NewMemoryModel())
+}
+
+func TestMemoryModelTalkInfoCopies(t *testing.T) {
+	ctx := context.Background()
+
+	m := NewMemoryModel()
+
+	formFields := map[string]string{"email": "a@b.c"}
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status:     defs.TalkStatusQueued,
+		FormFields: formFields,
+	})
+	assert.Nil(t, err)
+
+	formFields["email"] = "changed"
+
+	talkInfo, err := m.ClassifyTalk(ctx, &defs.TalkClassification{
+		TalkID:       talkID,
+		AddTags:      []string{"vip"},
+		CustomFields: map[string]string{"orderNumber": "A-1024"},
+	})
+	assert.Nil(t, err)
+
+	talkInfo.Tags[0] = "changed"
+	talkInfo.CustomFields["orderNumber"] = "changed"
+
+	talks, err := m.QueryTalks(ctx, 0, 0, talkID, nil, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+
+	talks[0].FormFields["email"] = "changed"
+
+	talks, err = m.QueryTalks(ctx, 0, 0, talkID, nil, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+	assert.EqualValues(t, map[string]string{"email": "a@b.c"}, talks[0].FormFields)
+	assert.EqualValues(t, []string{"vip"}, talks[0].Tags)
+	assert.EqualValues(t, map[string]string{"orderNumber": "A-1024"}, talks[0].CustomFields)
+}
@@ -2,8 +2,11 @@ package model
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/sbasestarter/customer-service-be/config"
 	"github.com/sbasestarter/customer-service-be/internal/defs"
@@ -16,8 +19,10 @@ import (
 )
 
 const (
-	collectionTalkInfo     = "talk_info"
-	collectionTalkTemplate = "talk:%s"
+	collectionTalkInfo      = "talk_info"
+	collectionTalkMessages  = "talk_messages"
+	collectionTalkSeqs      = "talk_seqs"
+	collectionTalkTransfers = "talk_transfers"
 )
 
 func NewMongoModel(cfg *config.MongoConfig, logger l.Wrapper) defs.Model {
@@ -31,6 +36,18 @@ func NewMongoModel(cfg *config.MongoConfig, logger l.Wrapper) defs.Model {
 		return nil
 	}
 
+	impl := &mongoModelImpl{
+		cfg:      cfg,
+		mongoCli: newMongoClient(cfg, logger),
+		logger:   logger.WithFields(l.StringField(l.ClsKey, "mongoModelImpl")),
+	}
+
+	impl.init()
+
+	return impl
+}
+
+func newMongoClient(cfg *config.MongoConfig, logger l.Wrapper) *mongo.Client {
 	mongoServer := cfg.Server
 	if !strings.HasPrefix(mongoServer, "mongodb://") {
 		mongoServer = "mongodb://" + mongoServer
@@ -52,15 +69,119 @@ func NewMongoModel(cfg *config.MongoConfig, logger l.Wrapper) defs.Model {
 		logger.WithFields(l.ErrorField(err)).Fatal("MongoPing")
 	}
 
-	return &mongoModelImpl{
-		cfg:      cfg,
-		mongoCli: client,
-	}
+	return client
 }
 
 type mongoModelImpl struct {
 	cfg      *config.MongoConfig
 	mongoCli *mongo.Client
+	logger   l.Wrapper
+}
+
+// mongoTalkMessage is the stored form of a message in collectionTalkMessages.
+type mongoTalkMessage struct {
+	TalkID            string `bson:"TalkID"`
+	OutboxLeaseUntil  int64  `bson:"OutboxLeaseUntil,omitempty"` // set until the message is fanned out
+	defs.TalkMessageW `bson:"inline"`
+}
+
+func (m *mongoModelImpl) init() {
+	if _, err := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkMessages).Indexes().CreateMany(context.TODO(),
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "TalkID", Value: 1},
+					{Key: "Seq", Value: 1},
+					{Key: "_id", Value: 1},
+				},
+			},
+			{
+				Keys: bson.D{
+					{Key: "TalkID", Value: 1},
+					{Key: "At", Value: 1},
+				},
+			},
+			{
+				Keys:    bson.D{{Key: "OutboxLeaseUntil", Value: 1}},
+				Options: options.Index().SetSparse(true),
+			},
+			{
+				Keys: bson.D{
+					{Key: "TalkID", Value: 1},
+					{Key: "CustomerMessage", Value: 1},
+					{Key: "SenderID", Value: 1},
+					{Key: "ClientSeqID", Value: 1},
+				},
+				// a message retried by the client is stored once
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+					"ClientSeqID": bson.M{"$gt": 0},
+				}),
+			},
+		}); err != nil {
+		m.logger.WithFields(l.ErrorField(err)).Error("CreateTalkMessagesIndexFailed")
+	}
+
+	// for finding the talks by classification
+	if _, err := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkInfo).Indexes().CreateMany(context.TODO(),
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "Tags", Value: 1},
+					{Key: "StartAt", Value: 1},
+				},
+				Options: options.Index().SetSparse(true),
+			},
+			{
+				Keys: bson.D{
+					{Key: "Category", Value: 1},
+					{Key: "StartAt", Value: 1},
+				},
+				Options: options.Index().SetSparse(true),
+			},
+		}); err != nil {
+		m.logger.WithFields(l.ErrorField(err)).Error("CreateTalkInfoIndexFailed")
+	}
+
+	// for searching the talks page by page
+	if _, err := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkInfo).Indexes().CreateMany(context.TODO(),
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "StartAt", Value: 1},
+					{Key: "_id", Value: 1},
+				},
+			},
+			{
+				Keys: bson.D{
+					{Key: "CreatorID", Value: 1},
+					{Key: "StartAt", Value: 1},
+					{Key: "_id", Value: 1},
+				},
+			},
+			{
+				Keys: bson.D{
+					{Key: "ServiceID", Value: 1},
+					{Key: "StartAt", Value: 1},
+					{Key: "_id", Value: 1},
+				},
+			},
+		}); err != nil {
+		m.logger.WithFields(l.ErrorField(err)).Error("CreateTalkInfoSearchIndexFailed")
+	}
+
+	// one pending offer per talk
+	if _, err := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkTransfers).Indexes().CreateMany(context.TODO(),
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "TalkID", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys: bson.D{{Key: "ExpireAt", Value: 1}},
+			},
+		}); err != nil {
+		m.logger.WithFields(l.ErrorField(err)).Error("CreateTalkTransfersIndexFailed")
+	}
 }
 
 func (m *mongoModelImpl) CreateTalk(ctx context.Context, talkInfo *defs.TalkInfoW) (talkID string, err error) {
@@ -77,31 +198,337 @@ func (m *mongoModelImpl) CreateTalk(ctx context.Context, talkInfo *defs.TalkInfo
 }
 
 func (m *mongoModelImpl) OpenTalk(ctx context.Context, talkID string) (err error) {
-	return m.updateTalkInfo(ctx, talkID, bson.M{
-		"Status": defs.TalkStatusOpened,
-	})
+	talkInfos, err := m.queryTalksEx(ctx, 0, 0, talkID, nil, nil)
+	if err != nil {
+		return
+	}
+
+	status, reopen, err := reopenTalkStatus(talkInfos)
+	if err != nil || !reopen {
+		return
+	}
+
+	return m.transitTalkStatus(ctx, talkID, defs.TalkStatusesReopenable, status, nil)
 }
 
 func (m *mongoModelImpl) CloseTalk(ctx context.Context, talkID string) (err error) {
-	return m.updateTalkInfo(ctx, talkID, bson.M{
-		"Status": defs.TalkStatusClosed,
+	return m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusClosed)
+}
+
+func (m *mongoModelImpl) UpdateTalkStatus(ctx context.Context, talkID string, status defs.TalkStatus) (err error) {
+	return m.transitTalkStatus(ctx, talkID, defs.TalkStatusesTransitTo(status), status, nil)
+}
+
+func (m *mongoModelImpl) ResolveTalk(ctx context.Context, talkID string, disposition, note string) (err error) {
+	return m.transitTalkStatus(ctx, talkID, defs.TalkStatusesTransitTo(defs.TalkStatusResolved), defs.TalkStatusResolved,
+		bson.M{
+			"Disposition": disposition,
+			"WrapUpNote":  note,
+		})
+}
+
+func (m *mongoModelImpl) RateTalk(ctx context.Context, talkID string, score int, comment string) (err error) {
+	objectID, err := primitive.ObjectIDFromHex(talkID)
+	if err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	collection := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkInfo)
+
+	r, err := collection.UpdateOne(ctx, bson.M{
+		"_id":     objectID,
+		"Status":  bson.M{"$in": defs.TalkStatusesClosed},
+		"RatedAt": bson.M{"$not": bson.M{"$gt": 0}},
+	}, bson.M{
+		"$set": bson.M{
+			"RatingScore":   score,
+			"RatingComment": comment,
+			"RatedAt":       time.Now().Unix(),
+		},
 	})
+	if err != nil {
+		return
+	}
+
+	if r.MatchedCount > 0 {
+		return
+	}
+
+	var talkInfo defs.TalkInfoR
+
+	err = collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&talkInfo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = commerr.ErrNotFound
+		}
+
+		return
+	}
+
+	if talkInfo.Rated() {
+		err = commerr.ErrAlreadyExists
+	} else {
+		err = defs.ErrInvalidTalkTransition
+	}
+
+	return
+}
+
+func (m *mongoModelImpl) ClassifyTalk(ctx context.Context, classification *defs.TalkClassification) (
+	talkInfo *defs.TalkInfoR, err error) {
+	if classification == nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(classification.TalkID)
+	if err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	setM := bson.M{}
+	unsetM := bson.M{}
+
+	if classification.Category != nil {
+		if *classification.Category == "" {
+			unsetM["Category"] = ""
+		} else {
+			setM["Category"] = *classification.Category
+		}
+	}
+
+	for key, value := range classification.CustomFields {
+		if value == "" {
+			unsetM["CustomFields."+key] = ""
+		} else {
+			setM["CustomFields."+key] = value
+		}
+	}
+
+	update := bson.M{}
+
+	if len(setM) > 0 {
+		update["$set"] = setM
+	}
+
+	if len(unsetM) > 0 {
+		update["$unset"] = unsetM
+	}
+
+	if len(classification.AddTags) > 0 {
+		update["$addToSet"] = bson.M{"Tags": bson.M{"$each": classification.AddTags}}
+	}
+
+	// adding to and pulling from Tags in one update conflict
+	updates := []bson.M{update}
+
+	if len(classification.RemoveTags) > 0 {
+		updates = append(updates, bson.M{"$pull": bson.M{"Tags": bson.M{"$in": classification.RemoveTags}}})
+	}
+
+	collection := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkInfo)
+
+	for _, u := range updates {
+		if len(u) == 0 {
+			continue
+		}
+
+		var r *mongo.UpdateResult
+
+		r, err = collection.UpdateOne(ctx, bson.M{"_id": objectID}, u)
+		if err != nil {
+			return
+		}
+
+		if r.MatchedCount == 0 {
+			err = commerr.ErrNotFound
+
+			return
+		}
+	}
+
+	talkInfo = &defs.TalkInfoR{}
+
+	err = collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(talkInfo)
+	if err != nil {
+		talkInfo = nil
+
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = commerr.ErrNotFound
+		}
+
+		return
+	}
+
+	sort.Strings(talkInfo.Tags)
+
+	return
 }
 
 func (m *mongoModelImpl) AddTalkMessage(ctx context.Context, talkID string, message *defs.TalkMessageW) (err error) {
-	_, err = m.mongoCli.Database(m.cfg.DB).Collection(m.talkCollectionKey(talkID)).InsertOne(ctx, message)
+	if message == nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(talkID)
+	if err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	talkCount, err := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkInfo).CountDocuments(ctx,
+		bson.M{"_id": objectID}, options.Count().SetLimit(1))
+	if err != nil {
+		return
+	}
+
+	if talkCount == 0 {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	// checked before a seq is taken, the unique index covers the concurrent retries
+	if err = m.findClientMessage(ctx, talkID, message); err != nil {
+		return
+	}
+
+	var talkSeq struct {
+		Seq uint64 `bson:"Seq"`
+	}
+
+	// MigrateMongoTalkMessages reserves the seqs of the messages migrated from the legacy collections
+	err = m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkSeqs).FindOneAndUpdate(ctx, bson.M{"_id": talkID},
+		bson.M{"$inc": bson.M{"Seq": 1}}, options.FindOneAndUpdate().SetUpsert(true).
+			SetReturnDocument(options.After)).Decode(&talkSeq)
+	if err != nil {
+		return
+	}
+
+	message.Seq = talkSeq.Seq
+
+	_, err = m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkMessages).InsertOne(ctx, &mongoTalkMessage{
+		TalkID:           talkID,
+		OutboxLeaseUntil: outboxFirstLeaseUntil(),
+		TalkMessageW:     *message,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		if err = m.findClientMessage(ctx, talkID, message); err == nil {
+			err = commerr.ErrAlreadyExists
+		}
+	}
 
 	return
 }
 
 func (m *mongoModelImpl) GetTalkMessages(ctx context.Context, talkID string, offset, count int64) (messages []*defs.TalkMessageR, err error) {
-	findOptions := options.Find()
+	findOptions := options.Find().SetSort(bson.D{{Key: "Seq", Value: 1}, {Key: "_id", Value: 1}})
 	if count > 0 {
 		findOptions.SetSkip(offset)
 		findOptions.SetLimit(count)
 	}
 
-	cursor, err := m.mongoCli.Database(m.cfg.DB).Collection(m.talkCollectionKey(talkID)).Find(ctx, bson.D{}, findOptions)
+	cursor, err := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkMessages).Find(ctx, bson.M{
+		"TalkID": talkID,
+	}, findOptions)
+	if err != nil {
+		return
+	}
+
+	err = cursor.All(ctx, &messages)
+
+	return
+}
+
+func (m *mongoModelImpl) GetTalkMessagesByCursor(ctx context.Context, talkID, messageID string, before bool,
+	count int64, messageFilter *defs.TalkMessageFilter) (messages []*defs.TalkMessageR, err error) {
+	collection := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkMessages)
+
+	filter := bson.M{
+		"TalkID": talkID,
+	}
+
+	m.addTalkMessageFilterBsonM(filter, messageFilter)
+
+	op, sortOrder := "$gt", 1
+	if before {
+		op, sortOrder = "$lt", -1
+	}
+
+	if messageID != "" {
+		var objectID primitive.ObjectID
+
+		objectID, err = primitive.ObjectIDFromHex(messageID)
+		if err != nil {
+			err = commerr.ErrInvalidArgument
+
+			return
+		}
+
+		var cursorMessage struct {
+			Seq uint64 `bson:"Seq"`
+		}
+
+		err = collection.FindOne(ctx, bson.M{"_id": objectID, "TalkID": talkID}).Decode(&cursorMessage)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				err = commerr.ErrNotFound
+			}
+
+			return
+		}
+
+		filter["$or"] = bson.A{
+			bson.M{"Seq": bson.M{op: cursorMessage.Seq}},
+			bson.M{"Seq": cursorMessage.Seq, "_id": bson.M{op: objectID}},
+		}
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "Seq", Value: sortOrder}, {Key: "_id", Value: sortOrder}})
+	if count > 0 {
+		findOptions.SetLimit(count)
+	}
+
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return
+	}
+
+	err = cursor.All(ctx, &messages)
+	if err != nil {
+		return
+	}
+
+	if before {
+		reverseTalkMessages(messages)
+	}
+
+	return
+}
+
+func (m *mongoModelImpl) GetTalkMessagesSince(ctx context.Context, talkID string, at int64, count int64,
+	messageFilter *defs.TalkMessageFilter) (messages []*defs.TalkMessageR, err error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "Seq", Value: 1}, {Key: "_id", Value: 1}})
+	if count > 0 {
+		findOptions.SetLimit(count)
+	}
+
+	filter := bson.M{
+		"TalkID": talkID,
+		"At":     bson.M{"$gte": at},
+	}
+
+	m.addTalkMessageFilterBsonM(filter, messageFilter)
+
+	cursor, err := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkMessages).Find(ctx, filter, findOptions)
 	if err != nil {
 		return
 	}
@@ -112,15 +539,52 @@ func (m *mongoModelImpl) GetTalkMessages(ctx context.Context, talkID string, off
 }
 
 func (m *mongoModelImpl) QueryTalks(ctx context.Context, creatorID, serviceID uint64, talkID string,
-	statuses []defs.TalkStatus) (talks []*defs.TalkInfoR, err error) {
-	return m.queryTalksEx(ctx, creatorID, serviceID, talkID, statuses, nil)
+	statuses []defs.TalkStatus, filter *defs.TalkFilter) (talks []*defs.TalkInfoR, err error) {
+	return m.queryTalksEx(ctx, creatorID, serviceID, talkID, statuses, m.talkFilterBsonM(filter))
+}
+
+func (m *mongoModelImpl) SearchTalks(ctx context.Context, search *defs.TalkSearch) (talks []*defs.TalkInfoR, err error) {
+	bsonM := m.talkFilterBsonM(search.Filter)
+	if bsonM == nil {
+		bsonM = bson.M{}
+	}
+
+	sortOrder, op := -1, "$lt"
+	if search.Sort == defs.TalkSortStartAtAsc {
+		sortOrder, op = 1, "$gt"
+	}
+
+	if search.After != nil {
+		objectID, e := primitive.ObjectIDFromHex(search.After.TalkID)
+		if e != nil {
+			err = commerr.ErrInvalidArgument
+
+			return
+		}
+
+		bsonM["$or"] = bson.A{
+			bson.M{"StartAt": bson.M{op: search.After.StartAt}},
+			bson.M{"StartAt": search.After.StartAt, "_id": bson.M{op: objectID}},
+		}
+	}
+
+	findOptions := options.Find().SetSort(bson.D{
+		{Key: "StartAt", Value: sortOrder},
+		{Key: "_id", Value: sortOrder},
+	})
+
+	if search.Limit > 0 {
+		findOptions.SetLimit(int64(search.Limit))
+	}
+
+	return m.queryTalksEx(ctx, search.CreatorID, search.ServiceID, "", search.Statuses, bsonM, findOptions)
 }
 
 func (m *mongoModelImpl) GetPendingTalkInfos(ctx context.Context) ([]*defs.TalkInfoR, error) {
 	bsonM := bson.M{}
 	bsonM["ServiceID"] = 0
 
-	talkInfos, err := m.queryTalksEx(ctx, 0, 0, "", []defs.TalkStatus{defs.TalkStatusOpened}, bsonM)
+	talkInfos, err := m.queryTalksEx(ctx, 0, 0, "", []defs.TalkStatus{defs.TalkStatusQueued}, bsonM)
 	if err != nil {
 		return nil, err
 	}
@@ -128,12 +592,196 @@ func (m *mongoModelImpl) GetPendingTalkInfos(ctx context.Context) ([]*defs.TalkI
 	return talkInfos, nil
 }
 
+func (m *mongoModelImpl) GetRatedTalkInfos(ctx context.Context, from, to int64) ([]*defs.TalkInfoR, error) {
+	return m.queryTalksEx(ctx, 0, 0, "", nil, bson.M{
+		"RatedAt": bson.M{"$gte": from, "$lt": to},
+	})
+}
+
 func (m *mongoModelImpl) UpdateTalkServiceID(ctx context.Context, talkID string, serviceID uint64) (err error) {
 	return m.updateTalkInfo(ctx, talkID, bson.M{
 		"ServiceID": serviceID,
 	})
 }
 
+func (m *mongoModelImpl) CompareAndSetTalkServiceID(ctx context.Context, talkID string, expectedServiceID, serviceID uint64) (swapped bool,
+	curServiceID uint64, err error) {
+	objectID, err := primitive.ObjectIDFromHex(talkID)
+	if err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	collection := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkInfo)
+
+	err = collection.FindOneAndUpdate(ctx, bson.M{
+		"_id":       objectID,
+		"ServiceID": expectedServiceID,
+	}, bson.M{
+		"$set": bson.M{"ServiceID": serviceID},
+	}).Err()
+	if err == nil {
+		swapped, curServiceID = true, serviceID
+
+		return
+	}
+
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return
+	}
+
+	var talkInfo defs.TalkInfoR
+
+	err = collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&talkInfo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = commerr.ErrNotFound
+		}
+
+		return
+	}
+
+	curServiceID = talkInfo.ServiceID
+
+	return
+}
+
+func (m *mongoModelImpl) OfferTalkTransfer(ctx context.Context, transfer *defs.TalkTransfer) (err error) {
+	if transfer == nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	if _, err = primitive.ObjectIDFromHex(transfer.TalkID); err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	_, err = m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkTransfers).InsertOne(ctx, transfer)
+	if mongo.IsDuplicateKeyError(err) {
+		err = commerr.ErrAlreadyExists
+	}
+
+	return
+}
+
+func (m *mongoModelImpl) GetTalkTransfer(ctx context.Context, talkID string) (transfer *defs.TalkTransfer, err error) {
+	transfer = &defs.TalkTransfer{}
+
+	err = m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkTransfers).FindOne(ctx, bson.M{
+		"TalkID": talkID,
+	}).Decode(transfer)
+	if err != nil {
+		transfer = nil
+
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = commerr.ErrNotFound
+		}
+	}
+
+	return
+}
+
+func (m *mongoModelImpl) RemoveTalkTransfer(ctx context.Context, talkID string, expireAt int64) (removed bool, err error) {
+	r, err := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkTransfers).DeleteOne(ctx, bson.M{
+		"TalkID":   talkID,
+		"ExpireAt": expireAt,
+	})
+	if err != nil {
+		return
+	}
+
+	removed = r.DeletedCount > 0
+
+	return
+}
+
+func (m *mongoModelImpl) GetExpiredTalkTransfers(ctx context.Context, now int64, count int64) (
+	transfers []*defs.TalkTransfer, err error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "ExpireAt", Value: 1}})
+	if count > 0 {
+		findOptions.SetLimit(count)
+	}
+
+	cursor, err := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkTransfers).Find(ctx, bson.M{
+		"ExpireAt": bson.M{"$lte": now},
+	}, findOptions)
+	if err != nil {
+		return
+	}
+
+	err = cursor.All(ctx, &transfers)
+
+	return
+}
+
+func (m *mongoModelImpl) LeaseOutboxMessages(ctx context.Context, now, leaseUntil int64, count int64) (messages []*defs.OutboxMessage, err error) {
+	collection := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkMessages)
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "OutboxLeaseUntil", Value: 1}})
+	if count > 0 {
+		findOptions.SetLimit(count)
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{
+		"OutboxLeaseUntil": bson.M{"$gt": 0, "$lte": now},
+	}, findOptions)
+	if err != nil {
+		return
+	}
+
+	var candidates []*struct {
+		OutboxLeaseUntil   int64 `bson:"OutboxLeaseUntil"`
+		defs.OutboxMessage `bson:"inline"`
+	}
+
+	if err = cursor.All(ctx, &candidates); err != nil {
+		return
+	}
+
+	for _, candidate := range candidates {
+		var objectID primitive.ObjectID
+
+		objectID, err = primitive.ObjectIDFromHex(candidate.MessageID)
+		if err != nil {
+			return
+		}
+
+		var r *mongo.UpdateResult
+
+		// another relay may have leased it since the find
+		r, err = collection.UpdateOne(ctx, bson.M{
+			"_id":              objectID,
+			"OutboxLeaseUntil": candidate.OutboxLeaseUntil,
+		}, bson.M{
+			"$set": bson.M{"OutboxLeaseUntil": leaseUntil},
+		})
+		if err != nil {
+			return
+		}
+
+		if r.ModifiedCount > 0 {
+			messages = append(messages, &candidate.OutboxMessage)
+		}
+	}
+
+	return
+}
+
+func (m *mongoModelImpl) AckOutboxMessage(ctx context.Context, talkID string, seq uint64) (err error) {
+	_, err = m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkMessages).UpdateOne(ctx, bson.M{
+		"TalkID": talkID,
+		"Seq":    seq,
+	}, bson.M{
+		"$unset": bson.M{"OutboxLeaseUntil": ""},
+	})
+
+	return
+}
+
 //
 //
 //
@@ -168,8 +816,94 @@ func (m *mongoModelImpl) queryTalkFilter(creatorID, serviceID uint64, talkID str
 	return
 }
 
+// findClientMessage sets message to the one its sender stored with the same ClientSeqID, commerr.ErrAlreadyExists
+// is returned then.
+func (m *mongoModelImpl) findClientMessage(ctx context.Context, talkID string, message *defs.TalkMessageW) (err error) {
+	if message.ClientSeqID == 0 {
+		return
+	}
+
+	var stored mongoTalkMessage
+
+	err = m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkMessages).FindOne(ctx, bson.M{
+		"TalkID":          talkID,
+		"CustomerMessage": message.CustomerMessage,
+		"SenderID":        message.SenderID,
+		"ClientSeqID":     message.ClientSeqID,
+	}).Decode(&stored)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = nil
+		}
+
+		return
+	}
+
+	*message = stored.TalkMessageW
+	err = commerr.ErrAlreadyExists
+
+	return
+}
+
+func (m *mongoModelImpl) addTalkMessageFilterBsonM(bsonM bson.M, filter *defs.TalkMessageFilter) {
+	if filter == nil {
+		return
+	}
+
+	if len(filter.ExcludedTypes) > 0 {
+		bsonM["Type"] = bson.M{"$nin": filter.ExcludedTypes}
+	}
+
+	if len(filter.ExcludedSystemEvents) > 0 {
+		bsonM["$nor"] = bson.A{bson.M{
+			"Type": defs.TalkMessageTypeSystem,
+			"Text": bson.M{"$in": filter.ExcludedSystemEvents},
+		}}
+	}
+}
+
+func (m *mongoModelImpl) talkFilterBsonM(filter *defs.TalkFilter) (bsonM bson.M) {
+	if filter == nil {
+		return
+	}
+
+	bsonM = bson.M{}
+
+	if len(filter.Tags) > 0 {
+		bsonM["Tags"] = bson.M{"$all": filter.Tags}
+	}
+
+	if filter.Category != "" {
+		bsonM["Category"] = filter.Category
+	}
+
+	if filter.Title != "" {
+		bsonM["Title"] = bson.M{"$regex": regexp.QuoteMeta(filter.Title), "$options": "i"}
+	}
+
+	for key, value := range filter.CustomFields {
+		bsonM["CustomFields."+key] = value
+	}
+
+	startAt := bson.M{}
+
+	if filter.StartAtFrom > 0 {
+		startAt["$gte"] = filter.StartAtFrom
+	}
+
+	if filter.StartAtTo > 0 {
+		startAt["$lt"] = filter.StartAtTo
+	}
+
+	if len(startAt) > 0 {
+		bsonM["StartAt"] = startAt
+	}
+
+	return
+}
+
 func (m *mongoModelImpl) queryTalksEx(ctx context.Context, creatorID, serviceID uint64, talkID string,
-	statuses []defs.TalkStatus, bsonM bson.M) (talks []*defs.TalkInfoR, err error) {
+	statuses []defs.TalkStatus, bsonM bson.M, findOptions ...*options.FindOptions) (talks []*defs.TalkInfoR, err error) {
 	collection := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkInfo)
 
 	filter, err := m.queryTalkFilter(creatorID, serviceID, talkID, statuses)
@@ -183,12 +917,73 @@ func (m *mongoModelImpl) queryTalksEx(ctx context.Context, creatorID, serviceID
 		filter[k] = v
 	}
 
-	cursor, err := collection.Find(ctx, filter)
+	cursor, err := collection.Find(ctx, filter, findOptions...)
 	if err != nil {
 		return
 	}
 
 	err = cursor.All(ctx, &talks)
+	if err != nil {
+		return
+	}
+
+	// $addToSet appends the tags
+	for _, talk := range talks {
+		sort.Strings(talk.Tags)
+	}
+
+	return
+}
+
+func (m *mongoModelImpl) transitTalkStatus(ctx context.Context, talkID string, from []defs.TalkStatus,
+	to defs.TalkStatus, updateMap bson.M) (err error) {
+	objectID, err := primitive.ObjectIDFromHex(talkID)
+	if err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	setM := bson.M{"Status": to}
+	update := bson.M{"$set": setM}
+
+	for k, v := range updateMap {
+		setM[k] = v
+	}
+
+	if timestamp, ok := talkStatusTimestamps[to]; ok {
+		// first-at fields are omitted until set, $min sets the missing ones only
+		if timestamp.once {
+			update["$min"] = bson.M{timestamp.field: time.Now().Unix()}
+		} else {
+			setM[timestamp.field] = time.Now().Unix()
+		}
+	}
+
+	collection := m.mongoCli.Database(m.cfg.DB).Collection(collectionTalkInfo)
+
+	r, err := collection.UpdateOne(ctx, bson.M{
+		"_id":    objectID,
+		"Status": bson.M{"$in": from},
+	}, update)
+	if err != nil {
+		return
+	}
+
+	if r.MatchedCount > 0 {
+		return
+	}
+
+	n, err := collection.CountDocuments(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return
+	}
+
+	if n == 0 {
+		err = commerr.ErrNotFound
+	} else {
+		err = defs.ErrInvalidTalkTransition
+	}
 
 	return
 }
@@ -196,6 +991,8 @@ func (m *mongoModelImpl) queryTalksEx(ctx context.Context, creatorID, serviceID
 func (m *mongoModelImpl) updateTalkInfo(ctx context.Context, talkID string, updateMap bson.M) (err error) {
 	objectID, err := primitive.ObjectIDFromHex(talkID)
 	if err != nil {
+		err = commerr.ErrInvalidArgument
+
 		return
 	}
 
@@ -207,10 +1004,9 @@ func (m *mongoModelImpl) updateTalkInfo(ctx context.Context, talkID string, upda
 		})
 
 	err = r.Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = commerr.ErrNotFound
+	}
 
 	return
 }
-
-func (m *mongoModelImpl) talkCollectionKey(talkID string) string {
-	return fmt.Sprintf(collectionTalkTemplate, talkID)
-}
@@ -2,16 +2,22 @@ package model
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/sbasestarter/customer-service-be/config"
 	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/libeasygo/commerr"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func Test1(t *testing.T) {
+	skipWithoutMongo(t)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -26,11 +32,17 @@ func Test1(t *testing.T) {
 		_ = m1.mongoCli.Database(m1.cfg.DB).Collection(name).Drop(context.TODO())
 	}
 
+	testModel(ctx, t, m)
+}
+
+// testModel is the conformance suite every defs.Model implementation must pass.
+func testModel(ctx context.Context, t *testing.T, m defs.Model) {
 	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
-		Status:    defs.TalkStatusOpened,
+		Status:    defs.TalkStatusQueued,
 		Title:     "testTalk1",
 		StartAt:   time.Now().Unix(),
 		CreatorID: 1,
+		Skill:     "billing",
 	})
 	assert.Nil(t, err)
 	t.Log(talkID)
@@ -41,6 +53,11 @@ func Test1(t *testing.T) {
 	})
 	assert.Nil(t, err)
 
+	err = m.AddTalkMessage(ctx, primitive.NewObjectID().Hex(), &defs.TalkMessageW{
+		Type: defs.TalkMessageTypeText,
+	})
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+
 	err = m.AddTalkMessage(ctx, talkID, &defs.TalkMessageW{
 		Type: defs.TalkMessageTypeImage,
 		Data: []byte("talk_image_1"),
@@ -53,11 +70,14 @@ func Test1(t *testing.T) {
 	})
 	assert.Nil(t, err)
 
-	err = m.AddTalkMessage(ctx, talkID, &defs.TalkMessageW{
+	message4 := &defs.TalkMessageW{
 		Type: defs.TalkMessageTypeText,
 		Text: "talk_message_4",
-	})
+	}
+
+	err = m.AddTalkMessage(ctx, talkID, message4)
 	assert.Nil(t, err)
+	assert.EqualValues(t, 4, message4.Seq)
 
 	messages, err := m.GetTalkMessages(ctx, talkID, 0, 0)
 	assert.Nil(t, err)
@@ -65,6 +85,38 @@ func Test1(t *testing.T) {
 	assert.EqualValues(t, "talk_message_1", messages[0].Text)
 	assert.EqualValues(t, "talk_message_4", messages[3].Text)
 
+	for idx, message := range messages {
+		assert.EqualValues(t, idx+1, message.Seq)
+	}
+
+	now := time.Now().UnixMilli()
+
+	outboxMessages, err := m.LeaseOutboxMessages(ctx, now, now+1000, 10)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(outboxMessages))
+
+	now += time.Hour.Milliseconds()
+
+	outboxMessages, err = m.LeaseOutboxMessages(ctx, now, now+1000, 10)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, len(outboxMessages))
+	assert.EqualValues(t, talkID, outboxMessages[0].TalkID)
+
+	outboxMessages, err = m.LeaseOutboxMessages(ctx, now, now+1000, 10)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(outboxMessages))
+
+	assert.Nil(t, m.AckOutboxMessage(ctx, talkID, 4))
+
+	outboxMessages, err = m.LeaseOutboxMessages(ctx, now+1000, now+2000, 10)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(outboxMessages))
+
+	for _, message := range outboxMessages {
+		assert.NotEqualValues(t, 4, message.Seq)
+		assert.NotEmpty(t, message.MessageID)
+	}
+
 	messages, err = m.GetTalkMessages(ctx, talkID, 0, 1)
 	assert.Nil(t, err)
 	assert.EqualValues(t, 1, len(messages))
@@ -75,25 +127,652 @@ func Test1(t *testing.T) {
 	assert.EqualValues(t, 2, len(messages))
 	assert.EqualValues(t, "talk_message_3", messages[1].Text)
 
-	err = m.CloseTalk(ctx, talkID)
+	messages, err = m.GetTalkMessagesByCursor(ctx, talkID, "", true, 2, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(messages))
+	assert.EqualValues(t, "talk_message_3", messages[0].Text)
+	assert.EqualValues(t, "talk_message_4", messages[1].Text)
+
+	messages, err = m.GetTalkMessagesByCursor(ctx, talkID, messages[0].MessageID, true, 0, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(messages))
+	assert.EqualValues(t, "talk_message_1", messages[0].Text)
+
+	messages, err = m.GetTalkMessagesByCursor(ctx, talkID, messages[0].MessageID, false, 2, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(messages))
+	assert.EqualValues(t, defs.TalkMessageTypeImage, messages[0].Type)
+	assert.EqualValues(t, "talk_message_3", messages[1].Text)
+
+	messages, err = m.GetTalkMessagesByCursor(ctx, talkID, "", false, 1, nil)
 	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(messages))
+	assert.EqualValues(t, "talk_message_1", messages[0].Text)
+
+	_, err = m.GetTalkMessagesByCursor(ctx, talkID, "badMessageID", true, 1, nil)
+	assert.ErrorIs(t, err, commerr.ErrInvalidArgument)
+
+	messages, err = m.GetTalkMessagesSince(ctx, talkID, 0, 3, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(messages))
+	assert.EqualValues(t, "talk_message_1", messages[0].Text)
+
+	messages, err = m.GetTalkMessagesSince(ctx, talkID, 1, 0, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(messages))
 
+	// a no-op on an unfinished talk
 	err = m.OpenTalk(ctx, talkID)
 	assert.Nil(t, err)
 
-	err = m.OpenTalk(ctx, talkID[0:len(talkID)-1]+"9")
+	lastChar := "9"
+	if talkID[len(talkID)-1:] == lastChar {
+		lastChar = "8"
+	}
+
+	err = m.OpenTalk(ctx, talkID[0:len(talkID)-1]+lastChar)
 	assert.NotNil(t, err)
 
-	talks, err := m.QueryTalks(ctx, 1, 0, "", nil)
+	talks, err := m.QueryTalks(ctx, 1, 0, "", nil, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+	assert.EqualValues(t, defs.TalkStatusQueued, talks[0].Status)
+
+	talks, err = m.QueryTalks(ctx, 1, 0, "", []defs.TalkStatus{defs.TalkStatusQueued}, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+
+	talks, err = m.QueryTalks(ctx, 1, 0, "", []defs.TalkStatus{defs.TalkStatusClosed}, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(talks))
+
+	talks, err = m.QueryTalks(ctx, 0, 0, talkID, nil, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+	assert.EqualValues(t, talkID, talks[0].TalkID)
+	assert.EqualValues(t, "testTalk1", talks[0].Title)
+	assert.EqualValues(t, "billing", talks[0].Skill)
+
+	_, err = m.QueryTalks(ctx, 0, 0, "badTalkID", nil, nil)
+	assert.ErrorIs(t, err, commerr.ErrInvalidArgument)
+
+	err = m.OpenTalk(ctx, "badTalkID")
+	assert.ErrorIs(t, err, commerr.ErrInvalidArgument)
+
+	pendingTalks, err := m.GetPendingTalkInfos(ctx)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(pendingTalks))
+
+	swapped, curServiceID, err := m.CompareAndSetTalkServiceID(ctx, talkID, 0, 100)
+	assert.Nil(t, err)
+	assert.True(t, swapped)
+	assert.EqualValues(t, 100, curServiceID)
+
+	swapped, curServiceID, err = m.CompareAndSetTalkServiceID(ctx, talkID, 0, 101)
+	assert.Nil(t, err)
+	assert.False(t, swapped)
+	assert.EqualValues(t, 100, curServiceID)
+
+	_, _, err = m.CompareAndSetTalkServiceID(ctx, talkID[0:len(talkID)-1]+lastChar, 0, 101)
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+
+	pendingTalks, err = m.GetPendingTalkInfos(ctx)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(pendingTalks))
+
+	talks, err = m.QueryTalks(ctx, 0, 100, "", nil, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+
+	testModelTalkStatus(ctx, t, m)
+	testModelTalkRating(ctx, t, m)
+	testModelTalkPreChat(ctx, t, m)
+	testModelTalkClassification(ctx, t, m)
+	testModelTalkSearch(ctx, t, m)
+	testModelTalkMessageFilter(ctx, t, m)
+	testModelTalkTransfers(ctx, t, m)
+	testModelClientSeqID(ctx, t, m)
+}
+
+func testModelTalkStatus(ctx context.Context, t *testing.T, m defs.Model) {
+	getTalk := func(talkID string) *defs.TalkInfoR {
+		talks, err := m.QueryTalks(ctx, 0, 0, talkID, nil, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, len(talks))
+
+		return talks[0]
+	}
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status:    defs.TalkStatusQueued,
+		Title:     "testTalk2",
+		StartAt:   time.Now().Unix(),
+		CreatorID: 2,
+		QueuedAt:  time.Now().Unix(),
+	})
+	assert.Nil(t, err)
+
+	err = m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusResolved)
+	assert.ErrorIs(t, err, defs.ErrInvalidTalkTransition)
+
+	err = m.UpdateTalkStatus(ctx, primitive.NewObjectID().Hex(), defs.TalkStatusAssigned)
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+
+	err = m.UpdateTalkStatus(ctx, "badTalkID", defs.TalkStatusAssigned)
+	assert.ErrorIs(t, err, commerr.ErrInvalidArgument)
+
+	_, _, err = m.CompareAndSetTalkServiceID(ctx, talkID, 0, 200)
+	assert.Nil(t, err)
+
+	err = m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusAssigned)
+	assert.Nil(t, err)
+
+	talk := getTalk(talkID)
+	assert.EqualValues(t, defs.TalkStatusAssigned, talk.Status)
+	assert.True(t, talk.QueuedAt > 0)
+	assert.True(t, talk.FirstAssignedAt > 0)
+	assert.EqualValues(t, 0, talk.FirstResponseAt)
+
+	err = m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusWaitingOnCustomer)
+	assert.Nil(t, err)
+
+	firstResponseAt := getTalk(talkID).FirstResponseAt
+	assert.True(t, firstResponseAt > 0)
+
+	err = m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusAbandoned)
+	assert.ErrorIs(t, err, defs.ErrInvalidTalkTransition)
+
+	err = m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusAssigned)
+	assert.Nil(t, err)
+
+	err = m.ResolveTalk(ctx, talkID, "solved", "refunded")
+	assert.Nil(t, err)
+
+	talk = getTalk(talkID)
+	assert.EqualValues(t, defs.TalkStatusResolved, talk.Status)
+	assert.EqualValues(t, "solved", talk.Disposition)
+	assert.EqualValues(t, "refunded", talk.WrapUpNote)
+	assert.True(t, talk.FinishedAt > 0)
+
+	err = m.ResolveTalk(ctx, talkID, "solved", "")
+	assert.ErrorIs(t, err, defs.ErrInvalidTalkTransition)
+
+	// only reopening takes a resolved talk back
+	for _, status := range defs.TalkStatusesOpened {
+		err = m.UpdateTalkStatus(ctx, talkID, status)
+		assert.ErrorIs(t, err, defs.ErrInvalidTalkTransition)
+	}
+
+	// reopened to the attached servicer
+	err = m.OpenTalk(ctx, talkID)
+	assert.Nil(t, err)
+	assert.EqualValues(t, defs.TalkStatusAssigned, getTalk(talkID).Status)
+
+	err = m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusWaitingOnCustomer)
+	assert.Nil(t, err)
+	assert.EqualValues(t, firstResponseAt, getTalk(talkID).FirstResponseAt)
+
+	err = m.CloseTalk(ctx, talkID)
+	assert.Nil(t, err)
+
+	talk = getTalk(talkID)
+	assert.EqualValues(t, defs.TalkStatusClosed, talk.Status)
+	assert.True(t, talk.FinishedAt > 0)
+
+	err = m.CloseTalk(ctx, talkID)
+	assert.ErrorIs(t, err, defs.ErrInvalidTalkTransition)
+
+	// closed talks are reopened like resolved ones
+	err = m.OpenTalk(ctx, talkID)
+	assert.Nil(t, err)
+	assert.EqualValues(t, defs.TalkStatusAssigned, getTalk(talkID).Status)
+
+	err = m.CloseTalk(ctx, talkID)
+	assert.Nil(t, err)
+
+	talks, err := m.QueryTalks(ctx, 2, 0, "", defs.TalkStatusesClosed, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+
+	talkID, err = m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status:    defs.TalkStatusQueued,
+		Title:     "testTalk3",
+		CreatorID: 2,
+	})
+	assert.Nil(t, err)
+
+	err = m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusAbandoned)
+	assert.Nil(t, err)
+
+	talk = getTalk(talkID)
+	assert.EqualValues(t, defs.TalkStatusAbandoned, talk.Status)
+	assert.True(t, talk.FinishedAt > 0)
+
+	err = m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusQueued)
+	assert.ErrorIs(t, err, defs.ErrInvalidTalkTransition)
+
+	talks, err = m.QueryTalks(ctx, 2, 0, "", defs.TalkStatusesOpened, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(talks))
+}
+
+func testModelTalkRating(ctx context.Context, t *testing.T, m defs.Model) {
+	from := time.Now().Unix()
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status:    defs.TalkStatusQueued,
+		Title:     "testTalk4",
+		CreatorID: 3,
+	})
+	assert.Nil(t, err)
+
+	err = m.RateTalk(ctx, talkID, 5, "good")
+	assert.ErrorIs(t, err, defs.ErrInvalidTalkTransition)
+
+	err = m.RateTalk(ctx, primitive.NewObjectID().Hex(), 5, "")
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+
+	_, _, err = m.CompareAndSetTalkServiceID(ctx, talkID, 0, 300)
+	assert.Nil(t, err)
+	assert.Nil(t, m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusAssigned))
+	assert.Nil(t, m.ResolveTalk(ctx, talkID, "solved", ""))
+
+	err = m.RateTalk(ctx, talkID, 4, "good")
+	assert.Nil(t, err)
+
+	err = m.RateTalk(ctx, talkID, 1, "bad")
+	assert.ErrorIs(t, err, commerr.ErrAlreadyExists)
+
+	talks, err := m.QueryTalks(ctx, 0, 0, talkID, nil, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+	assert.EqualValues(t, 4, talks[0].RatingScore)
+	assert.EqualValues(t, "good", talks[0].RatingComment)
+	assert.True(t, talks[0].Rated())
+
+	talks, err = m.GetRatedTalkInfos(ctx, from, time.Now().Unix()+1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+	assert.EqualValues(t, talkID, talks[0].TalkID)
+
+	talks, err = m.GetRatedTalkInfos(ctx, 0, from)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(talks))
+}
+
+func testModelTalkPreChat(ctx context.Context, t *testing.T, m defs.Model) {
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status:     defs.TalkStatusQueued,
+		Title:      "testTalk5",
+		CreatorID:  4,
+		FormFields: map[string]string{"email": "a@b.com", "orderNumber": "A-1024"},
+		ClientContext: defs.TalkClientContext{
+			PageURL: "https://shop.example.com/orders/1024",
+			Locale:  "zh-CN",
+		},
+	})
+	assert.Nil(t, err)
+
+	talks, err := m.QueryTalks(ctx, 0, 0, talkID, nil, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+
+	talkInfo := talks[0]
+	assert.EqualValues(t, map[string]string{"email": "a@b.com", "orderNumber": "A-1024"}, talkInfo.FormFields)
+	assert.EqualValues(t, "https://shop.example.com/orders/1024", talkInfo.ClientContext.PageURL)
+	assert.EqualValues(t, "zh-CN", talkInfo.ClientContext.Locale)
+	assert.EqualValues(t, "", talkInfo.ClientContext.UserAgent)
+
+	talkID, err = m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status:    defs.TalkStatusQueued,
+		Title:     "testTalk6",
+		CreatorID: 4,
+	})
+	assert.Nil(t, err)
+
+	talks, err = m.QueryTalks(ctx, 0, 0, talkID, nil, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+
+	talkInfo = talks[0]
+	assert.Nil(t, talkInfo.FormFields)
+	assert.True(t, talkInfo.ClientContext.IsZero())
+}
+
+func testModelTalkClassification(ctx context.Context, t *testing.T, m defs.Model) {
+	refund := "refund"
+
+	now := time.Now().Unix()
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status:    defs.TalkStatusQueued,
+		Title:     "testTalk7",
+		StartAt:   now,
+		CreatorID: 5,
+	})
+	assert.Nil(t, err)
+
+	otherTalkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status:    defs.TalkStatusQueued,
+		Title:     "testTalk8",
+		StartAt:   now - 8*24*3600,
+		CreatorID: 5,
+	})
+	assert.Nil(t, err)
+
+	talkInfo, err := m.ClassifyTalk(ctx, &defs.TalkClassification{
+		TalkID:       talkID,
+		AddTags:      []string{"vip", "urgent", "vip"},
+		Category:     &refund,
+		CustomFields: map[string]string{"amount": "99.5", "orderNumber": "A-1024"},
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"urgent", "vip"}, talkInfo.Tags)
+	assert.EqualValues(t, "refund", talkInfo.Category)
+	assert.EqualValues(t, map[string]string{"amount": "99.5", "orderNumber": "A-1024"}, talkInfo.CustomFields)
+
+	talkInfo, err = m.ClassifyTalk(ctx, &defs.TalkClassification{
+		TalkID:       talkID,
+		AddTags:      []string{"called"},
+		RemoveTags:   []string{"urgent", "missing"},
+		CustomFields: map[string]string{"orderNumber": ""},
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"called", "vip"}, talkInfo.Tags)
+	assert.EqualValues(t, "refund", talkInfo.Category)
+	assert.EqualValues(t, map[string]string{"amount": "99.5"}, talkInfo.CustomFields)
+
+	_, err = m.ClassifyTalk(ctx, &defs.TalkClassification{
+		TalkID:   otherTalkID,
+		AddTags:  []string{"vip"},
+		Category: &refund,
+	})
+	assert.Nil(t, err)
+
+	_, err = m.ClassifyTalk(ctx, &defs.TalkClassification{TalkID: primitive.NewObjectID().Hex(), AddTags: []string{"vip"}})
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+
+	talks, err := m.QueryTalks(ctx, 5, 0, "", nil, &defs.TalkFilter{Category: "refund"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(talks))
+
+	talks, err = m.QueryTalks(ctx, 0, 0, "", nil, &defs.TalkFilter{
+		Category:    "refund",
+		StartAtFrom: now - 7*24*3600,
+		StartAtTo:   now + 1,
+	})
 	assert.Nil(t, err)
 	assert.EqualValues(t, 1, len(talks))
-	assert.EqualValues(t, defs.TalkStatusOpened, talks[0].Status)
+	assert.EqualValues(t, talkID, talks[0].TalkID)
+
+	talks, err = m.QueryTalks(ctx, 0, 0, "", nil, &defs.TalkFilter{Tags: []string{"vip", "called"}})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+	assert.EqualValues(t, talkID, talks[0].TalkID)
+
+	talks, err = m.QueryTalks(ctx, 0, 0, "", nil, &defs.TalkFilter{Tags: []string{"vip"},
+		CustomFields: map[string]string{"amount": "99.5"}})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(talks))
+
+	talks, err = m.QueryTalks(ctx, 0, 0, "", nil, &defs.TalkFilter{CustomFields: map[string]string{"amount": "99"}})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(talks))
+
+	talks, err = m.QueryTalks(ctx, 5, 0, "", nil, &defs.TalkFilter{})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(talks))
+}
+
+func testModelTalkSearch(ctx context.Context, t *testing.T, m defs.Model) {
+	now := time.Now().Unix()
+
+	var talkIDs []string
+
+	for idx, startAt := range []int64{now - 300, now - 100, now - 100, now - 100, now} {
+		talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+			Status:    defs.TalkStatusQueued,
+			Title:     fmt.Sprintf("Order_%d 100%% late", idx),
+			StartAt:   startAt,
+			CreatorID: 6,
+		})
+		assert.Nil(t, err)
+
+		talkIDs = append(talkIDs, talkID)
+	}
+
+	sort.Strings(talkIDs[1:4])
+
+	assert.Nil(t, m.CloseTalk(ctx, talkIDs[2]))
+
+	searchAll := func(search *defs.TalkSearch) (pages [][]string) {
+		for {
+			talks, err := m.SearchTalks(ctx, search)
+			assert.Nil(t, err)
+
+			if len(talks) == 0 {
+				return
+			}
+
+			var page []string
+
+			for _, talk := range talks {
+				page = append(page, talk.TalkID)
+			}
+
+			pages = append(pages, page)
+
+			search.After = talks[len(talks)-1].Cursor()
+		}
+	}
+
+	assert.EqualValues(t, [][]string{{talkIDs[4], talkIDs[3]}, {talkIDs[2], talkIDs[1]}, {talkIDs[0]}},
+		searchAll(&defs.TalkSearch{CreatorID: 6, Limit: 2}))
+	assert.EqualValues(t, [][]string{{talkIDs[0], talkIDs[1], talkIDs[2]}, {talkIDs[3], talkIDs[4]}},
+		searchAll(&defs.TalkSearch{CreatorID: 6, Sort: defs.TalkSortStartAtAsc, Limit: 3}))
+	assert.EqualValues(t, [][]string{{talkIDs[2]}},
+		searchAll(&defs.TalkSearch{CreatorID: 6, Statuses: []defs.TalkStatus{defs.TalkStatusClosed}}))
+	assert.EqualValues(t, [][]string{{talkIDs[3], talkIDs[2], talkIDs[1]}},
+		searchAll(&defs.TalkSearch{CreatorID: 6, Filter: &defs.TalkFilter{StartAtFrom: now - 200, StartAtTo: now}}))
 
-	talks, err = m.QueryTalks(ctx, 1, 0, "", []defs.TalkStatus{defs.TalkStatusOpened})
+	talks, err := m.SearchTalks(ctx, &defs.TalkSearch{CreatorID: 6, Filter: &defs.TalkFilter{Title: "order_3 100%"}})
 	assert.Nil(t, err)
 	assert.EqualValues(t, 1, len(talks))
 
-	talks, err = m.QueryTalks(ctx, 1, 0, "", []defs.TalkStatus{defs.TalkStatusClosed})
+	talks, err = m.SearchTalks(ctx, &defs.TalkSearch{CreatorID: 6, Filter: &defs.TalkFilter{Title: "order__"}})
 	assert.Nil(t, err)
 	assert.EqualValues(t, 0, len(talks))
+
+	_, err = m.SearchTalks(ctx, &defs.TalkSearch{After: &defs.TalkCursor{StartAt: now, TalkID: "x"}})
+	assert.ErrorIs(t, err, commerr.ErrInvalidArgument)
+}
+
+func testModelTalkMessageFilter(ctx context.Context, t *testing.T, m defs.Model) {
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status: defs.TalkStatusQueued,
+		Title:  "notes",
+	})
+	assert.Nil(t, err)
+
+	for idx, messageType := range []defs.TalkMessageType{defs.TalkMessageTypeText, defs.TalkMessageTypeNote,
+		defs.TalkMessageTypeText, defs.TalkMessageTypeNote, defs.TalkMessageTypeNote, defs.TalkMessageTypeText} {
+		err = m.AddTalkMessage(ctx, talkID, &defs.TalkMessageW{
+			At:   int64(idx + 1),
+			Type: messageType,
+			Text: fmt.Sprintf("message_%d", idx+1),
+		})
+		assert.Nil(t, err)
+	}
+
+	filter := defs.CustomerTalkMessageFilter()
+
+	// full pages, the notes don't eat into count
+	messages, err := m.GetTalkMessagesByCursor(ctx, talkID, "", true, 2, filter)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(messages))
+	assert.EqualValues(t, "message_3", messages[0].Text)
+	assert.EqualValues(t, "message_6", messages[1].Text)
+
+	messages, err = m.GetTalkMessagesByCursor(ctx, talkID, messages[0].MessageID, true, 2, filter)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(messages))
+	assert.EqualValues(t, "message_1", messages[0].Text)
+
+	messages, err = m.GetTalkMessagesByCursor(ctx, talkID, messages[0].MessageID, false, 2, filter)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(messages))
+	assert.EqualValues(t, "message_3", messages[0].Text)
+	assert.EqualValues(t, "message_6", messages[1].Text)
+
+	messages, err = m.GetTalkMessagesSince(ctx, talkID, 2, 2, filter)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(messages))
+	assert.EqualValues(t, "message_3", messages[0].Text)
+	assert.EqualValues(t, "message_6", messages[1].Text)
+
+	messages, err = m.GetTalkMessagesByCursor(ctx, talkID, "", true, 2, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(messages))
+	assert.EqualValues(t, defs.TalkMessageTypeNote, messages[0].Type)
+
+	// the transfer results are kept from the customers, the other system events aren't
+	for idx, event := range []defs.TalkSystemEvent{defs.TalkSystemEventTransferDeclined,
+		defs.TalkSystemEventServicerJoined, defs.TalkSystemEventTransferExpired} {
+		err = m.AddTalkMessage(ctx, talkID, &defs.TalkMessageW{
+			At:   int64(idx + 7),
+			Type: defs.TalkMessageTypeSystem,
+			Text: string(event),
+		})
+		assert.Nil(t, err)
+	}
+
+	messages, err = m.GetTalkMessagesSince(ctx, talkID, 6, 10, filter)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(messages))
+	assert.EqualValues(t, "message_6", messages[0].Text)
+	assert.EqualValues(t, defs.TalkSystemEventServicerJoined, messages[1].Text)
+
+	messages, err = m.GetTalkMessagesByCursor(ctx, talkID, "", true, 1, filter)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(messages))
+	assert.EqualValues(t, defs.TalkSystemEventServicerJoined, messages[0].Text)
+
+	messages, err = m.GetTalkMessagesSince(ctx, talkID, 7, 10, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(messages))
+}
+
+func testModelTalkTransfers(ctx context.Context, t *testing.T, m defs.Model) {
+	talkID1 := primitive.NewObjectID().Hex()
+	talkID2 := primitive.NewObjectID().Hex()
+
+	_, err := m.GetTalkTransfer(ctx, talkID1)
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+
+	err = m.OfferTalkTransfer(ctx, &defs.TalkTransfer{TalkID: "x"})
+	assert.ErrorIs(t, err, commerr.ErrInvalidArgument)
+
+	transfer := &defs.TalkTransfer{
+		TalkID:         talkID1,
+		FromServicerID: 1,
+		ToTeam:         "billing",
+		Reason:         "refund asked",
+		ExpireAt:       100,
+		State:          defs.TalkTransferStateOffered,
+	}
+
+	assert.Nil(t, m.OfferTalkTransfer(ctx, transfer))
+	assert.ErrorIs(t, m.OfferTalkTransfer(ctx, transfer), commerr.ErrAlreadyExists)
+
+	assert.Nil(t, m.OfferTalkTransfer(ctx, &defs.TalkTransfer{
+		TalkID:         talkID2,
+		FromServicerID: 2,
+		ToServicerID:   3,
+		ExpireAt:       200,
+		State:          defs.TalkTransferStateOffered,
+	}))
+
+	stored, err := m.GetTalkTransfer(ctx, talkID1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, transfer, stored)
+
+	transfers, err := m.GetExpiredTalkTransfers(ctx, 99, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(transfers))
+
+	transfers, err = m.GetExpiredTalkTransfers(ctx, 200, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(transfers))
+	assert.EqualValues(t, talkID1, transfers[0].TalkID)
+	assert.EqualValues(t, 3, transfers[1].ToServicerID)
+
+	transfers, err = m.GetExpiredTalkTransfers(ctx, 200, 1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(transfers))
+
+	// resolved once only
+	removed, err := m.RemoveTalkTransfer(ctx, talkID1, 99)
+	assert.Nil(t, err)
+	assert.False(t, removed)
+
+	removed, err = m.RemoveTalkTransfer(ctx, talkID1, 100)
+	assert.Nil(t, err)
+	assert.True(t, removed)
+
+	removed, err = m.RemoveTalkTransfer(ctx, talkID1, 100)
+	assert.Nil(t, err)
+	assert.False(t, removed)
+
+	_, err = m.GetTalkTransfer(ctx, talkID1)
+	assert.ErrorIs(t, err, commerr.ErrNotFound)
+
+	removed, err = m.RemoveTalkTransfer(ctx, talkID2, 200)
+	assert.Nil(t, err)
+	assert.True(t, removed)
+}
+
+func testModelClientSeqID(ctx context.Context, t *testing.T, m defs.Model) {
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status: defs.TalkStatusAssigned,
+		Title:  "retries",
+	})
+	assert.Nil(t, err)
+
+	message := &defs.TalkMessageW{
+		At:              1,
+		CustomerMessage: true,
+		Type:            defs.TalkMessageTypeText,
+		SenderID:        1,
+		Text:            "hello",
+		ClientSeqID:     7,
+	}
+	assert.Nil(t, m.AddTalkMessage(ctx, talkID, message))
+
+	// retried, maybe on another instance
+	retried := &defs.TalkMessageW{
+		At:              2,
+		CustomerMessage: true,
+		Type:            defs.TalkMessageTypeText,
+		SenderID:        1,
+		Text:            "hello",
+		ClientSeqID:     7,
+	}
+	assert.ErrorIs(t, m.AddTalkMessage(ctx, talkID, retried), commerr.ErrAlreadyExists)
+	assert.EqualValues(t, message, retried)
+
+	// the same SeqId of other senders
+	for _, other := range []*defs.TalkMessageW{
+		{At: 3, SenderID: 1, Type: defs.TalkMessageTypeText, Text: "servicer", ClientSeqID: 7},
+		{At: 4, CustomerMessage: true, SenderID: 2, Type: defs.TalkMessageTypeText, Text: "customer", ClientSeqID: 7},
+		{At: 5, SenderID: 1, Type: defs.TalkMessageTypeSystem, Text: string(defs.TalkSystemEventServicerJoined)},
+		{At: 6, SenderID: 1, Type: defs.TalkMessageTypeSystem, Text: string(defs.TalkSystemEventServicerLeft)},
+	} {
+		assert.Nil(t, m.AddTalkMessage(ctx, talkID, other))
+	}
+
+	messages, err := m.GetTalkMessages(ctx, talkID, 0, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, len(messages))
+	assert.EqualValues(t, 2, messages[1].Seq)
+	assert.EqualValues(t, 7, messages[0].ClientSeqID)
 }
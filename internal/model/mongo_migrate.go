@@ -0,0 +1,342 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sgostarter/i/l"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	collectionLegacyTalkPrefix      = "talk:"
+	collectionTalkMessagesMigration = "talk_messages_migration"
+
+	mongoMigrateBatchSize = 500
+)
+
+type MongoMigrateCollectionReport struct {
+	Collection string
+	TalkID     string
+	Total      int64 // messages in the legacy collection
+	Migrated   int64 // messages of the talk already in talk_messages
+	Done       bool
+	Sequenced  bool // the migrated messages have their Seq and millisecond At
+}
+
+type MongoMigrateReport struct {
+	DryRun      bool
+	Collections []*MongoMigrateCollectionReport
+}
+
+type mongoMigrateProgress struct {
+	Collection string      `bson:"_id"`
+	LastID     interface{} `bson:"LastID"`
+	Done       bool        `bson:"Done"`
+	// LegacyCount is the number of the migrated messages, whose seqs 1 to LegacyCount are reserved in talk_seqs,
+	// the messages the talk got before the reservation with seqs up to ShiftUpTo are moved after them.
+	SeqReserved bool   `bson:"SeqReserved"`
+	LegacyCount int64  `bson:"LegacyCount"`
+	ShiftUpTo   uint64 `bson:"ShiftUpTo"`
+	Sequenced   bool   `bson:"Sequenced"`
+}
+
+// MigrateMongoTalkMessages copies the legacy talk:<id> collections into talk_messages.
+// Messages keep their _id, so running it again is harmless, and the progress of every
+// collection is checkpointed per batch, so an interrupted run resumes where it stopped.
+// The migrated messages are then numbered in _id order before the messages the talk got since, like the sql
+// migration backfills the seqs, and their At is turned from seconds into milliseconds.
+func MigrateMongoTalkMessages(ctx context.Context, cfg *config.MongoConfig, dryRun, dropLegacy bool,
+	logger l.Wrapper) (report *MongoMigrateReport, err error) {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	m, ok := NewMongoModel(cfg, logger).(*mongoModelImpl)
+	if !ok {
+		err = errors.New("notMongoModel")
+
+		return
+	}
+
+	db := m.mongoCli.Database(m.cfg.DB)
+
+	names, err := db.ListCollectionNames(ctx, bson.M{
+		"name": bson.M{"$regex": "^" + collectionLegacyTalkPrefix},
+	})
+	if err != nil {
+		return
+	}
+
+	report = &MongoMigrateReport{
+		DryRun: dryRun,
+	}
+
+	for _, name := range names {
+		collectionReport := &MongoMigrateCollectionReport{
+			Collection: name,
+			TalkID:     strings.TrimPrefix(name, collectionLegacyTalkPrefix),
+		}
+
+		report.Collections = append(report.Collections, collectionReport)
+
+		if err = migrateMongoTalkCollection(ctx, db, collectionReport, dryRun, dropLegacy); err != nil {
+			logger.WithFields(l.StringField("collection", name), l.ErrorField(err)).Error("MigrateCollectionFailed")
+
+			return
+		}
+	}
+
+	return
+}
+
+func migrateMongoTalkCollection(ctx context.Context, db *mongo.Database, report *MongoMigrateCollectionReport,
+	dryRun, dropLegacy bool) (err error) {
+	legacy := db.Collection(report.Collection)
+	messages := db.Collection(collectionTalkMessages)
+	progresses := db.Collection(collectionTalkMessagesMigration)
+
+	var progress mongoMigrateProgress
+
+	err = progresses.FindOne(ctx, bson.M{"_id": report.Collection}).Decode(&progress)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return
+	}
+
+	progress.Collection = report.Collection
+
+	if report.Total, err = legacy.CountDocuments(ctx, bson.D{}); err != nil {
+		return
+	}
+
+	if progress.needCopy(dryRun) {
+		if err = copyMongoTalkCollection(ctx, legacy, messages, progresses, report.TalkID, &progress); err != nil {
+			return
+		}
+	}
+
+	if progress.needSequence(dryRun) {
+		if err = sequenceMongoMigratedMessages(ctx, db, report.TalkID, &progress); err != nil {
+			return
+		}
+	}
+
+	report.Done = progress.Done
+	report.Sequenced = progress.Sequenced
+
+	if report.Migrated, err = messages.CountDocuments(ctx, bson.M{"TalkID": report.TalkID}); err != nil {
+		return
+	}
+
+	if progress.canDropLegacy(dryRun, dropLegacy) {
+		err = legacy.Drop(ctx)
+	}
+
+	return
+}
+
+func (progress *mongoMigrateProgress) needCopy(dryRun bool) bool {
+	return !dryRun && !progress.Done
+}
+
+func (progress *mongoMigrateProgress) needSequence(dryRun bool) bool {
+	return !dryRun && progress.Done && !progress.Sequenced
+}
+
+func (progress *mongoMigrateProgress) canDropLegacy(dryRun, dropLegacy bool) bool {
+	return !dryRun && dropLegacy && progress.Done
+}
+
+// nextBatchFilter resumes the copy right after the last copied legacy message.
+func (progress *mongoMigrateProgress) nextBatchFilter() bson.M {
+	filter := bson.M{}
+	if progress.LastID != nil {
+		filter["_id"] = bson.M{"$gt": progress.LastID}
+	}
+
+	return filter
+}
+
+// planCopyBatch returns the upserts of the legacy docs, the batch of docs read by nextBatchFilter,
+// and moves the progress past them. An empty batch finishes the copy.
+func (progress *mongoMigrateProgress) planCopyBatch(talkID string, docs []bson.M) (writeModels []mongo.WriteModel) {
+	if len(docs) == 0 {
+		progress.Done = true
+
+		return
+	}
+
+	writeModels = make([]mongo.WriteModel, 0, len(docs))
+
+	for _, doc := range docs {
+		doc["TalkID"] = talkID
+
+		writeModels = append(writeModels, mongo.NewReplaceOneModel().SetFilter(bson.M{"_id": doc["_id"]}).
+			SetReplacement(doc).SetUpsert(true))
+	}
+
+	progress.LastID = docs[len(docs)-1]["_id"]
+
+	return
+}
+
+func copyMongoTalkCollection(ctx context.Context, legacy, messages, progresses *mongo.Collection, talkID string,
+	progress *mongoMigrateProgress) (err error) {
+	for {
+		var cursor *mongo.Cursor
+
+		cursor, err = legacy.Find(ctx, progress.nextBatchFilter(), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).
+			SetLimit(mongoMigrateBatchSize))
+		if err != nil {
+			return
+		}
+
+		var docs []bson.M
+
+		if err = cursor.All(ctx, &docs); err != nil {
+			return
+		}
+
+		if writeModels := progress.planCopyBatch(talkID, docs); len(writeModels) > 0 {
+			if _, err = messages.BulkWrite(ctx, writeModels, options.BulkWrite().SetOrdered(false)); err != nil {
+				return
+			}
+		}
+
+		if _, err = progresses.ReplaceOne(ctx, bson.M{"_id": progress.Collection}, progress,
+			options.Replace().SetUpsert(true)); err != nil {
+			return
+		}
+
+		if progress.Done {
+			return
+		}
+	}
+}
+
+// sequenceMongoMigratedMessages gives the messages of the talk without Seq the seqs 1 to n in _id order,
+// every step of it can be run again after an interruption.
+func sequenceMongoMigratedMessages(ctx context.Context, db *mongo.Database, talkID string,
+	progress *mongoMigrateProgress) (err error) {
+	messages := db.Collection(collectionTalkMessages)
+	progresses := db.Collection(collectionTalkMessagesMigration)
+
+	if !progress.SeqReserved {
+		if progress.LegacyCount, err = messages.CountDocuments(ctx, bson.M{
+			"TalkID": talkID,
+			"Seq":    bson.M{"$exists": false},
+		}); err != nil {
+			return
+		}
+
+		// the messages added from now on get seqs after the reserved ones
+		var talkSeq struct {
+			Seq uint64 `bson:"Seq"`
+		}
+
+		err = db.Collection(collectionTalkSeqs).FindOneAndUpdate(ctx, bson.M{"_id": talkID},
+			bson.M{"$inc": bson.M{"Seq": progress.LegacyCount}}, options.FindOneAndUpdate().SetUpsert(true).
+				SetReturnDocument(options.Before)).Decode(&talkSeq)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return
+		}
+
+		progress.ShiftUpTo = talkSeq.Seq
+		progress.SeqReserved = true
+
+		if _, err = progresses.ReplaceOne(ctx, bson.M{"_id": progress.Collection}, progress,
+			options.Replace().SetUpsert(true)); err != nil {
+			return
+		}
+	}
+
+	if progress.ShiftUpTo > 0 {
+		if _, err = messages.UpdateMany(ctx, bson.M{
+			"TalkID":           talkID,
+			"Seq":              bson.M{"$gte": 1, "$lte": progress.ShiftUpTo},
+			"MigrationShifted": bson.M{"$exists": false},
+		}, bson.M{
+			"$inc": bson.M{"Seq": progress.LegacyCount},
+			"$set": bson.M{"MigrationShifted": true},
+		}); err != nil {
+			return
+		}
+	}
+
+	// the numbered migrated messages are the only ones with seqs up to LegacyCount now
+	var lastSequenced struct {
+		Seq int64 `bson:"Seq"`
+	}
+
+	err = messages.FindOne(ctx, bson.M{
+		"TalkID": talkID,
+		"Seq":    bson.M{"$gte": 1, "$lte": progress.LegacyCount},
+	}, options.FindOne().SetSort(bson.D{{Key: "Seq", Value: -1}})).Decode(&lastSequenced)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return
+	}
+
+	seq := lastSequenced.Seq
+
+	for {
+		var cursor *mongo.Cursor
+
+		cursor, err = messages.Find(ctx, bson.M{
+			"TalkID": talkID,
+			"Seq":    bson.M{"$exists": false},
+		}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(mongoMigrateBatchSize).
+			SetProjection(bson.M{"At": 1}))
+		if err != nil {
+			return
+		}
+
+		var docs []*mongoUnsequencedMessage
+
+		if err = cursor.All(ctx, &docs); err != nil {
+			return
+		}
+
+		if len(docs) == 0 {
+			break
+		}
+
+		var writeModels []mongo.WriteModel
+
+		writeModels, seq = planSequenceBatch(seq, docs)
+
+		if _, err = messages.BulkWrite(ctx, writeModels, options.BulkWrite().SetOrdered(true)); err != nil {
+			return
+		}
+	}
+
+	progress.Sequenced = true
+
+	_, err = progresses.ReplaceOne(ctx, bson.M{"_id": progress.Collection}, progress, options.Replace().SetUpsert(true))
+
+	return
+}
+
+type mongoUnsequencedMessage struct {
+	ID interface{} `bson:"_id"`
+	At int64       `bson:"At"` // unix seconds
+}
+
+// planSequenceBatch numbers docs in order after lastSeq, lastSeq is the seq of the last doc then.
+func planSequenceBatch(lastSeq int64, docs []*mongoUnsequencedMessage) (writeModels []mongo.WriteModel, seq int64) {
+	seq = lastSeq
+
+	writeModels = make([]mongo.WriteModel, 0, len(docs))
+
+	for _, doc := range docs {
+		seq++
+
+		writeModels = append(writeModels, mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": doc.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"Seq": seq, "At": doc.At * 1000}}))
+	}
+
+	return
+}
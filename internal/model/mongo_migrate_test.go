@@ -0,0 +1,157 @@
+package model
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestMigrateMongoTalkMessages(t *testing.T) {
+	skipWithoutMongo(t)
+
+	ctx := context.Background()
+
+	cfg := &config.GetConfig().MongoConfig
+
+	m, ok := NewMongoModel(cfg, nil).(*mongoModelImpl)
+	assert.True(t, ok)
+
+	db := m.mongoCli.Database(m.cfg.DB)
+
+	collectionNames, err := db.ListCollectionNames(ctx, bson.D{})
+	assert.Nil(t, err)
+
+	for _, name := range collectionNames {
+		_ = db.Collection(name).Drop(ctx)
+	}
+
+	talkID, err := m.CreateTalk(ctx, &defs.TalkInfoW{
+		Status: defs.TalkStatusQueued,
+		Title:  "legacy",
+	})
+	assert.Nil(t, err)
+
+	for i := 0; i < mongoMigrateBatchSize+1; i++ {
+		_, err = db.Collection("talk:"+talkID).InsertOne(ctx, bson.M{"At": i, "Text": "legacy"})
+		assert.Nil(t, err)
+	}
+
+	// a message the talk got before the migration
+	assert.Nil(t, m.AddTalkMessage(ctx, talkID, &defs.TalkMessageW{At: 1_000_000_000_000, Text: "new"}))
+
+	report, err := MigrateMongoTalkMessages(ctx, cfg, true, false, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(report.Collections))
+	assert.EqualValues(t, mongoMigrateBatchSize+1, report.Collections[0].Total)
+	assert.EqualValues(t, 1, report.Collections[0].Migrated)
+
+	for i := 0; i < 2; i++ {
+		report, err = MigrateMongoTalkMessages(ctx, cfg, false, false, nil)
+		assert.Nil(t, err)
+		assert.True(t, report.Collections[0].Done)
+		assert.True(t, report.Collections[0].Sequenced)
+		assert.EqualValues(t, mongoMigrateBatchSize+2, report.Collections[0].Migrated)
+	}
+
+	messages, err := m.GetTalkMessages(ctx, talkID, 0, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, mongoMigrateBatchSize+2, len(messages))
+
+	// the history pages back from the newest message through all the migrated ones
+	var history []*defs.TalkMessageR
+
+	messageID := ""
+
+	for {
+		page, err := m.GetTalkMessagesByCursor(ctx, talkID, messageID, true, 100, nil)
+		assert.Nil(t, err)
+
+		if len(page) == 0 {
+			break
+		}
+
+		history = append(page, history...)
+		messageID = page[0].MessageID
+	}
+
+	assert.EqualValues(t, mongoMigrateBatchSize+2, len(history))
+
+	for i, message := range history[:mongoMigrateBatchSize+1] {
+		assert.EqualValues(t, i+1, message.Seq)
+		assert.EqualValues(t, i*1000, message.At)
+		assert.EqualValues(t, "legacy", message.Text)
+	}
+
+	assert.EqualValues(t, mongoMigrateBatchSize+2, history[mongoMigrateBatchSize+1].Seq)
+	assert.EqualValues(t, "new", history[mongoMigrateBatchSize+1].Text)
+
+	message := &defs.TalkMessageW{At: 1_000_000_000_001, Text: "after"}
+	assert.Nil(t, m.AddTalkMessage(ctx, talkID, message))
+	assert.EqualValues(t, mongoMigrateBatchSize+3, message.Seq)
+}
+
+func TestMongoMigrateProgress(t *testing.T) {
+	progress := &mongoMigrateProgress{Collection: "talk:t1"}
+
+	assert.False(t, progress.needCopy(true))
+	assert.True(t, progress.needCopy(false))
+	assert.False(t, progress.needSequence(false))
+	assert.False(t, progress.canDropLegacy(false, true))
+	assert.EqualValues(t, bson.M{}, progress.nextBatchFilter())
+
+	writeModels := progress.planCopyBatch("t1", []bson.M{{"_id": 1, "At": 10}, {"_id": 2, "At": 20}})
+	assert.EqualValues(t, 2, len(writeModels))
+	assert.False(t, progress.Done)
+	assert.EqualValues(t, 2, progress.LastID)
+
+	replace, ok := writeModels[1].(*mongo.ReplaceOneModel)
+	assert.True(t, ok)
+	assert.EqualValues(t, bson.M{"_id": 2}, replace.Filter)
+	assert.EqualValues(t, bson.M{"_id": 2, "At": 20, "TalkID": "t1"}, replace.Replacement)
+	assert.True(t, *replace.Upsert)
+
+	// an interrupted run resumes after the checkpointed batch
+	resumed := &mongoMigrateProgress{Collection: progress.Collection, LastID: progress.LastID}
+	assert.EqualValues(t, bson.M{"_id": bson.M{"$gt": 2}}, resumed.nextBatchFilter())
+
+	assert.EqualValues(t, 0, len(resumed.planCopyBatch("t1", nil)))
+	assert.True(t, resumed.Done)
+	assert.False(t, resumed.needCopy(false))
+	assert.True(t, resumed.needSequence(false))
+	assert.False(t, resumed.needSequence(true))
+	assert.True(t, resumed.canDropLegacy(false, true))
+	assert.False(t, resumed.canDropLegacy(true, true))
+
+	resumed.Sequenced = true
+	assert.False(t, resumed.needSequence(false))
+}
+
+func TestPlanSequenceBatch(t *testing.T) {
+	writeModels, seq := planSequenceBatch(3, []*mongoUnsequencedMessage{{ID: "a", At: 1}, {ID: "b", At: 2}})
+	assert.EqualValues(t, 5, seq)
+	assert.EqualValues(t, 2, len(writeModels))
+
+	update, ok := writeModels[1].(*mongo.UpdateOneModel)
+	assert.True(t, ok)
+	assert.EqualValues(t, bson.M{"_id": "b"}, update.Filter)
+	assert.EqualValues(t, bson.M{"$set": bson.M{"Seq": int64(5), "At": int64(2000)}}, update.Update)
+
+	writeModels, seq = planSequenceBatch(5, nil)
+	assert.EqualValues(t, 5, seq)
+	assert.EqualValues(t, 0, len(writeModels))
+}
+
+// skipWithoutMongo skips the tests against the mongo in the config unless UT_MONGO is set.
+func skipWithoutMongo(t *testing.T) {
+	t.Helper()
+
+	if os.Getenv("UT_MONGO") == "" {
+		t.Skip("set UT_MONGO to run against the mongo in the config")
+	}
+}
@@ -0,0 +1,226 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/sbasestarter/bizmongolib/mongolib"
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/i/l"
+	"github.com/sgostarter/libeasygo/commerr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const servicerCapacitySuffix = "_capacity"
+
+// NewServicerCapacityModel stores the overrides in the database of the servicer accounts named name,
+// see NewUserPasswordModel.
+func NewServicerCapacityModel(cfg *config.Config, name string, logger l.Wrapper) defs.ServicerCapacityModel {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	if cfg == nil {
+		logger.Fatal("NoCfgOnCreateServicerCapacityModel")
+
+		return nil
+	}
+
+	if _, ok := sqlDialectByModelType(cfg.ModelType); ok {
+		return NewSQLServicerCapacityModel(cfg.ModelType, cfg.SQLDSN, name, logger)
+	}
+
+	mongoCli, mongoOptions, err := mongolib.InitMongo(cfg.UserMongoDSN)
+	if err != nil {
+		logger.WithFields(l.ErrorField(err)).Fatal("InitMongoFailed")
+
+		return nil
+	}
+
+	return &mongoServicerCapacityModelImpl{
+		collection: mongoCli.Database(mongoOptions.Auth.AuthSource).Collection(name + servicerCapacitySuffix),
+	}
+}
+
+//
+// memory
+//
+
+func NewMemoryServicerCapacityModel() defs.ServicerCapacityModel {
+	return &memoryServicerCapacityModelImpl{
+		maxTalks: make(map[uint64]int),
+	}
+}
+
+type memoryServicerCapacityModelImpl struct {
+	lock     sync.RWMutex
+	maxTalks map[uint64]int
+}
+
+func (m *memoryServicerCapacityModelImpl) GetServicerMaxTalks(_ context.Context, servicerID uint64) (maxTalks int, ok bool, err error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	maxTalks, ok = m.maxTalks[servicerID]
+
+	return
+}
+
+func (m *memoryServicerCapacityModelImpl) SetServicerMaxTalks(_ context.Context, servicerID uint64, maxTalks int) (err error) {
+	if maxTalks < 0 {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.maxTalks[servicerID] = maxTalks
+
+	return
+}
+
+func (m *memoryServicerCapacityModelImpl) RemoveServicerMaxTalks(_ context.Context, servicerID uint64) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.maxTalks, servicerID)
+
+	return
+}
+
+//
+// sql
+//
+
+func NewSQLServicerCapacityModel(modelType, dsn, name string, logger l.Wrapper) defs.ServicerCapacityModel {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	d, ok := sqlDialectByModelType(modelType)
+	if !ok {
+		logger.WithFields(l.StringField("modelType", modelType)).Fatal("NotSQLModelType")
+
+		return nil
+	}
+
+	db, err := openSQLDB(d, dsn)
+	if err != nil {
+		logger.WithFields(l.ErrorField(err)).Fatal("OpenSQLDBFailed")
+
+		return nil
+	}
+
+	m := &sqlServicerCapacityModelImpl{
+		db:        db,
+		tableName: name + servicerCapacitySuffix,
+	}
+
+	// the table name is chosen by the caller, so it can't live in the fixed migrations
+	if _, err = db.Exec(`CREATE TABLE IF NOT EXISTS ` + m.tableName + ` (
+		user_id BIGINT PRIMARY KEY,
+		max_talks INTEGER NOT NULL
+	)`); err != nil {
+		logger.WithFields(l.ErrorField(err)).Error("CreateTableFailed")
+	}
+
+	return m
+}
+
+type sqlServicerCapacityModelImpl struct {
+	db        *sql.DB
+	tableName string
+}
+
+func (m *sqlServicerCapacityModelImpl) GetServicerMaxTalks(ctx context.Context, servicerID uint64) (maxTalks int, ok bool, err error) {
+	err = m.db.QueryRowContext(ctx, `SELECT max_talks FROM `+m.tableName+` WHERE user_id = $1`, servicerID).
+		Scan(&maxTalks)
+	if err != nil {
+		if isSQLNoRows(err) {
+			err = nil
+		}
+
+		return
+	}
+
+	ok = true
+
+	return
+}
+
+func (m *sqlServicerCapacityModelImpl) SetServicerMaxTalks(ctx context.Context, servicerID uint64, maxTalks int) (err error) {
+	if maxTalks < 0 {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	_, err = m.db.ExecContext(ctx, `INSERT INTO `+m.tableName+` (user_id, max_talks) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET max_talks = excluded.max_talks`, servicerID, maxTalks)
+
+	return
+}
+
+func (m *sqlServicerCapacityModelImpl) RemoveServicerMaxTalks(ctx context.Context, servicerID uint64) (err error) {
+	_, err = m.db.ExecContext(ctx, `DELETE FROM `+m.tableName+` WHERE user_id = $1`, servicerID)
+
+	return
+}
+
+//
+// mongo
+//
+
+type mongoServicerCapacity struct {
+	ServicerID uint64 `bson:"_id"`
+	MaxTalks   int    `bson:"MaxTalks"`
+}
+
+type mongoServicerCapacityModelImpl struct {
+	collection *mongo.Collection
+}
+
+func (m *mongoServicerCapacityModelImpl) GetServicerMaxTalks(ctx context.Context, servicerID uint64) (maxTalks int, ok bool, err error) {
+	var capacity mongoServicerCapacity
+
+	err = m.collection.FindOne(ctx, bson.M{"_id": servicerID}).Decode(&capacity)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = nil
+		}
+
+		return
+	}
+
+	maxTalks = capacity.MaxTalks
+	ok = true
+
+	return
+}
+
+func (m *mongoServicerCapacityModelImpl) SetServicerMaxTalks(ctx context.Context, servicerID uint64, maxTalks int) (err error) {
+	if maxTalks < 0 {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	_, err = m.collection.UpdateOne(ctx, bson.M{"_id": servicerID}, bson.M{
+		"$set": bson.M{"MaxTalks": maxTalks},
+	}, options.Update().SetUpsert(true))
+
+	return
+}
+
+func (m *mongoServicerCapacityModelImpl) RemoveServicerMaxTalks(ctx context.Context, servicerID uint64) (err error) {
+	_, err = m.collection.DeleteOne(ctx, bson.M{"_id": servicerID})
+
+	return
+}
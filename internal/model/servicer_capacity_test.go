@@ -0,0 +1,40 @@
+package model
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServicerCapacityModel(t *testing.T) {
+	testServicerCapacityModel(t, NewMemoryServicerCapacityModel())
+	testServicerCapacityModel(t, NewSQLServicerCapacityModel(config.ModelTypeSQLite,
+		filepath.Join(t.TempDir(), "ut.db"), "ut_users", nil))
+}
+
+func testServicerCapacityModel(t *testing.T, m defs.ServicerCapacityModel) {
+	ctx := context.Background()
+
+	_, ok, err := m.GetServicerMaxTalks(ctx, 1)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, m.SetServicerMaxTalks(ctx, 1, 3))
+	assert.Nil(t, m.SetServicerMaxTalks(ctx, 1, 0))
+	assert.NotNil(t, m.SetServicerMaxTalks(ctx, 1, -1))
+
+	maxTalks, ok, err := m.GetServicerMaxTalks(ctx, 1)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 0, maxTalks)
+
+	assert.Nil(t, m.RemoveServicerMaxTalks(ctx, 1))
+
+	_, ok, err = m.GetServicerMaxTalks(ctx, 1)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,1491 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/lib/pq"           // postgres driver
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/i/l"
+	"github.com/sgostarter/libeasygo/commerr"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	sqlDriverSQLite   = "sqlite3"
+	sqlDriverPostgres = "postgres"
+
+	defaultSQLiteDSN = "customer-service.db?_busy_timeout=5000&_journal_mode=WAL"
+)
+
+type sqlDialect struct {
+	driverName string
+	autoIDType string
+	blobType   string
+}
+
+var (
+	sqlDialectSQLite = sqlDialect{
+		driverName: sqlDriverSQLite,
+		autoIDType: "INTEGER PRIMARY KEY AUTOINCREMENT",
+		blobType:   "BLOB",
+	}
+
+	sqlDialectPostgres = sqlDialect{
+		driverName: sqlDriverPostgres,
+		autoIDType: "BIGSERIAL PRIMARY KEY",
+		blobType:   "BYTEA",
+	}
+)
+
+type sqlMigration struct {
+	version    int
+	statements func(d sqlDialect) []string
+}
+
+// sqlMigrations must only be appended, never edited once released.
+var sqlMigrations = []sqlMigration{
+	{
+		version: 1,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`CREATE TABLE talk_infos (
+					talk_id VARCHAR(24) PRIMARY KEY,
+					status INTEGER NOT NULL DEFAULT 0,
+					title TEXT NOT NULL DEFAULT '',
+					start_at BIGINT NOT NULL DEFAULT 0,
+					finished_at BIGINT NOT NULL DEFAULT 0,
+					creator_id BIGINT NOT NULL DEFAULT 0,
+					service_id BIGINT NOT NULL DEFAULT 0,
+					creator_user_name TEXT NOT NULL DEFAULT ''
+				)`,
+				`CREATE INDEX idx_talk_infos_creator_id ON talk_infos (creator_id)`,
+				`CREATE INDEX idx_talk_infos_service_id ON talk_infos (service_id)`,
+				`CREATE INDEX idx_talk_infos_status ON talk_infos (status)`,
+				fmt.Sprintf(`CREATE TABLE talk_messages (
+					id %s,
+					message_id VARCHAR(24) NOT NULL UNIQUE,
+					talk_id VARCHAR(24) NOT NULL,
+					at BIGINT NOT NULL DEFAULT 0,
+					customer_message BOOLEAN NOT NULL DEFAULT FALSE,
+					type INTEGER NOT NULL DEFAULT 0,
+					sender_id BIGINT NOT NULL DEFAULT 0,
+					sender_user_name TEXT NOT NULL DEFAULT '',
+					text TEXT NOT NULL DEFAULT '',
+					data %s
+				)`, d.autoIDType, d.blobType),
+				`CREATE INDEX idx_talk_messages_talk_id ON talk_messages (talk_id, id)`,
+			}
+		},
+	},
+	{
+		version: 2,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`CREATE TABLE talk_seqs (
+					talk_id VARCHAR(24) PRIMARY KEY,
+					seq BIGINT NOT NULL DEFAULT 0
+				)`,
+				`ALTER TABLE talk_messages ADD COLUMN seq BIGINT NOT NULL DEFAULT 0`,
+				`UPDATE talk_messages SET seq = (SELECT COUNT(*) FROM talk_messages t
+					WHERE t.talk_id = talk_messages.talk_id AND t.id <= talk_messages.id)`,
+				`INSERT INTO talk_seqs (talk_id, seq) SELECT talk_id, MAX(seq) FROM talk_messages GROUP BY talk_id`,
+				`CREATE UNIQUE INDEX idx_talk_messages_talk_id_seq ON talk_messages (talk_id, seq)`,
+			}
+		},
+	},
+	{
+		version: 3,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`CREATE TABLE talk_outbox (
+					talk_id VARCHAR(24) NOT NULL,
+					seq BIGINT NOT NULL,
+					lease_until BIGINT NOT NULL,
+					PRIMARY KEY (talk_id, seq)
+				)`,
+				`CREATE INDEX idx_talk_outbox_lease_until ON talk_outbox (lease_until)`,
+			}
+		},
+	},
+	{
+		version: 4,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`ALTER TABLE talk_infos ADD COLUMN queued_at BIGINT NOT NULL DEFAULT 0`,
+				`ALTER TABLE talk_infos ADD COLUMN first_assigned_at BIGINT NOT NULL DEFAULT 0`,
+				`ALTER TABLE talk_infos ADD COLUMN first_response_at BIGINT NOT NULL DEFAULT 0`,
+			}
+		},
+	},
+	{
+		version: 5,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`ALTER TABLE talk_infos ADD COLUMN disposition TEXT NOT NULL DEFAULT ''`,
+				`ALTER TABLE talk_infos ADD COLUMN wrap_up_note TEXT NOT NULL DEFAULT ''`,
+			}
+		},
+	},
+	{
+		version: 6,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`ALTER TABLE talk_infos ADD COLUMN skill TEXT NOT NULL DEFAULT ''`,
+			}
+		},
+	},
+	{
+		version: 7,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`ALTER TABLE talk_infos ADD COLUMN rating_score INTEGER NOT NULL DEFAULT 0`,
+				`ALTER TABLE talk_infos ADD COLUMN rating_comment TEXT NOT NULL DEFAULT ''`,
+				`ALTER TABLE talk_infos ADD COLUMN rated_at BIGINT NOT NULL DEFAULT 0`,
+				`CREATE INDEX idx_talk_infos_rated_at ON talk_infos (rated_at)`,
+			}
+		},
+	},
+	{
+		version: 8,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`ALTER TABLE talk_infos ADD COLUMN form_fields TEXT NOT NULL DEFAULT ''`, // json object
+				`ALTER TABLE talk_infos ADD COLUMN client_page_url TEXT NOT NULL DEFAULT ''`,
+				`ALTER TABLE talk_infos ADD COLUMN client_user_agent TEXT NOT NULL DEFAULT ''`,
+				`ALTER TABLE talk_infos ADD COLUMN client_locale TEXT NOT NULL DEFAULT ''`,
+				`ALTER TABLE talk_infos ADD COLUMN client_referrer TEXT NOT NULL DEFAULT ''`,
+			}
+		},
+	},
+	{
+		version: 9,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`ALTER TABLE talk_infos ADD COLUMN category TEXT NOT NULL DEFAULT ''`,
+				`CREATE INDEX idx_talk_infos_category ON talk_infos (category, start_at)`,
+				`CREATE TABLE talk_tags (
+					talk_id VARCHAR(24) NOT NULL,
+					tag TEXT NOT NULL,
+					PRIMARY KEY (talk_id, tag)
+				)`,
+				`CREATE INDEX idx_talk_tags_tag ON talk_tags (tag)`,
+				`CREATE TABLE talk_custom_fields (
+					talk_id VARCHAR(24) NOT NULL,
+					field_key TEXT NOT NULL,
+					value TEXT NOT NULL,
+					PRIMARY KEY (talk_id, field_key)
+				)`,
+				`CREATE INDEX idx_talk_custom_fields_key_value ON talk_custom_fields (field_key, value)`,
+			}
+		},
+	},
+	{
+		version: 10,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`CREATE INDEX idx_talk_infos_start_at ON talk_infos (start_at, talk_id)`,
+				`CREATE INDEX idx_talk_infos_creator_id_start_at ON talk_infos (creator_id, start_at, talk_id)`,
+				`CREATE INDEX idx_talk_infos_service_id_start_at ON talk_infos (service_id, start_at, talk_id)`,
+			}
+		},
+	},
+	{
+		version: 11,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`CREATE TABLE talk_transfers (
+					talk_id VARCHAR(24) PRIMARY KEY,
+					from_servicer_id BIGINT NOT NULL,
+					to_servicer_id BIGINT NOT NULL,
+					to_team TEXT NOT NULL,
+					reason TEXT NOT NULL,
+					expire_at BIGINT NOT NULL
+				)`,
+				`CREATE INDEX idx_talk_transfers_expire_at ON talk_transfers (expire_at)`,
+			}
+		},
+	},
+	{
+		version: 12,
+		statements: func(d sqlDialect) []string {
+			return []string{
+				`ALTER TABLE talk_messages ADD COLUMN client_seq_id BIGINT NOT NULL DEFAULT 0`,
+				// a message retried by the client is stored once
+				`CREATE UNIQUE INDEX idx_talk_messages_client_seq_id
+					ON talk_messages (talk_id, customer_message, sender_id, client_seq_id) WHERE client_seq_id > 0`,
+			}
+		},
+	},
+}
+
+var (
+	sqlDBsLock sync.Mutex
+	sqlDBs     = make(map[string]*sql.DB)
+)
+
+func sqlDialectByModelType(modelType string) (sqlDialect, bool) {
+	switch modelType {
+	case config.ModelTypeSQLite:
+		return sqlDialectSQLite, true
+	case config.ModelTypePostgres:
+		return sqlDialectPostgres, true
+	default:
+		return sqlDialect{}, false
+	}
+}
+
+// openSQLDB opens and migrates the database once per dsn, so the talk model and the user model share one pool.
+func openSQLDB(d sqlDialect, dsn string) (db *sql.DB, err error) {
+	if dsn == "" && d.driverName == sqlDriverSQLite {
+		dsn = defaultSQLiteDSN
+	}
+
+	key := d.driverName + ":" + dsn
+
+	sqlDBsLock.Lock()
+	defer sqlDBsLock.Unlock()
+
+	if db = sqlDBs[key]; db != nil {
+		return
+	}
+
+	db, err = sql.Open(d.driverName, dsn)
+	if err != nil {
+		return
+	}
+
+	if d.driverName == sqlDriverSQLite {
+		// sqlite allows only one writer, serialize on a single connection instead of failing with SQLITE_BUSY
+		db.SetMaxOpenConns(1)
+	}
+
+	if err = db.Ping(); err != nil {
+		_ = db.Close()
+
+		return
+	}
+
+	if err = migrateSQLDB(context.Background(), db, d); err != nil {
+		_ = db.Close()
+
+		return
+	}
+
+	sqlDBs[key] = db
+
+	return
+}
+
+func migrateSQLDB(ctx context.Context, db *sql.DB, d sqlDialect) (err error) {
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`)
+	if err != nil {
+		return
+	}
+
+	var curVersion int
+
+	err = db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&curVersion)
+	if err != nil {
+		return
+	}
+
+	for _, migration := range sqlMigrations {
+		if migration.version <= curVersion {
+			continue
+		}
+
+		if err = applySQLMigration(ctx, db, d, migration); err != nil {
+			err = fmt.Errorf("migration %d: %w", migration.version, err)
+
+			return
+		}
+	}
+
+	return
+}
+
+func applySQLMigration(ctx context.Context, db *sql.DB, d sqlDialect, migration sqlMigration) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	for _, statement := range migration.statements(d) {
+		if _, err = tx.ExecContext(ctx, statement); err != nil {
+			return
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, migration.version); err != nil {
+		return
+	}
+
+	err = tx.Commit()
+
+	return
+}
+
+func NewSQLModel(modelType, dsn string, logger l.Wrapper) defs.Model {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	d, ok := sqlDialectByModelType(modelType)
+	if !ok {
+		logger.WithFields(l.StringField("modelType", modelType)).Fatal("NotSQLModelType")
+
+		return nil
+	}
+
+	db, err := openSQLDB(d, dsn)
+	if err != nil {
+		logger.WithFields(l.ErrorField(err)).Fatal("OpenSQLDBFailed")
+
+		return nil
+	}
+
+	return &sqlModelImpl{
+		db: db,
+	}
+}
+
+type sqlModelImpl struct {
+	db *sql.DB
+}
+
+const (
+	sqlTalkInfoColumns = `talk_id, status, title, start_at, finished_at, creator_id, service_id, creator_user_name,
+		queued_at, first_assigned_at, first_response_at, disposition, wrap_up_note, skill, rating_score, rating_comment, rated_at,
+		form_fields, client_page_url, client_user_agent, client_locale, client_referrer, category`
+
+	// the talk ids of a query on talk_tags and talk_custom_fields, far below the sqlite variables limit
+	sqlMaxTalkIDsPerQuery = 500
+	sqlTalkMessageColumns = `message_id, seq, at, customer_message, type, sender_id, sender_user_name, text, data, client_seq_id`
+	sqlTransferColumns    = `talk_id, from_servicer_id, to_servicer_id, to_team, reason, expire_at`
+)
+
+func (m *sqlModelImpl) CreateTalk(ctx context.Context, talkInfo *defs.TalkInfoW) (talkID string, err error) {
+	if talkInfo == nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	formFields, err := sqlFormFields(talkInfo.FormFields)
+	if err != nil {
+		return
+	}
+
+	id := primitive.NewObjectID().Hex()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO talk_infos (`+sqlTalkInfoColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+		$11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)`,
+		id, talkInfo.Status, talkInfo.Title, talkInfo.StartAt, talkInfo.FinishedAt, talkInfo.CreatorID,
+		talkInfo.ServiceID, talkInfo.CreatorUserName, talkInfo.QueuedAt, talkInfo.FirstAssignedAt, talkInfo.FirstResponseAt,
+		talkInfo.Disposition, talkInfo.WrapUpNote, talkInfo.Skill, talkInfo.RatingScore, talkInfo.RatingComment, talkInfo.RatedAt,
+		formFields, talkInfo.ClientContext.PageURL, talkInfo.ClientContext.UserAgent, talkInfo.ClientContext.Locale,
+		talkInfo.ClientContext.Referrer, talkInfo.Category)
+	if err != nil {
+		return
+	}
+
+	if err = m.classifyTalk(ctx, tx, &defs.TalkClassification{
+		TalkID:       id,
+		AddTags:      talkInfo.Tags,
+		CustomFields: talkInfo.CustomFields,
+	}); err != nil {
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	talkID = id
+
+	return
+}
+
+func (m *sqlModelImpl) OpenTalk(ctx context.Context, talkID string) (err error) {
+	talkInfos, err := m.queryTalksEx(ctx, 0, 0, talkID, nil, nil)
+	if err != nil {
+		return
+	}
+
+	status, reopen, err := reopenTalkStatus(talkInfos)
+	if err != nil || !reopen {
+		return
+	}
+
+	return m.transitTalkStatus(ctx, talkID, defs.TalkStatusesReopenable, status, nil)
+}
+
+func (m *sqlModelImpl) CloseTalk(ctx context.Context, talkID string) error {
+	return m.UpdateTalkStatus(ctx, talkID, defs.TalkStatusClosed)
+}
+
+func (m *sqlModelImpl) UpdateTalkStatus(ctx context.Context, talkID string, status defs.TalkStatus) (err error) {
+	return m.transitTalkStatus(ctx, talkID, defs.TalkStatusesTransitTo(status), status, nil)
+}
+
+func (m *sqlModelImpl) ResolveTalk(ctx context.Context, talkID string, disposition, note string) (err error) {
+	return m.transitTalkStatus(ctx, talkID, defs.TalkStatusesTransitTo(defs.TalkStatusResolved), defs.TalkStatusResolved,
+		map[string]interface{}{
+			"disposition":  disposition,
+			"wrap_up_note": note,
+		})
+}
+
+func (m *sqlModelImpl) RateTalk(ctx context.Context, talkID string, score int, comment string) (err error) {
+	if _, err = primitive.ObjectIDFromHex(talkID); err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	where := &sqlWhere{args: []interface{}{score, comment, time.Now().Unix()}}
+
+	where.add("talk_id = ?", talkID)
+	where.add("rated_at = 0")
+
+	placeholders := make([]string, 0, len(defs.TalkStatusesClosed))
+	statusArgs := make([]interface{}, 0, len(defs.TalkStatusesClosed))
+
+	for _, status := range defs.TalkStatusesClosed {
+		placeholders = append(placeholders, "?")
+		statusArgs = append(statusArgs, status)
+	}
+
+	where.add("status IN ("+strings.Join(placeholders, ", ")+")", statusArgs...)
+
+	r, err := m.db.ExecContext(ctx, `UPDATE talk_infos SET rating_score = $1, rating_comment = $2, rated_at = $3`+
+		where.String(), where.args...)
+	if err != nil {
+		return
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if n > 0 {
+		return
+	}
+
+	var ratedAt int64
+
+	err = m.db.QueryRowContext(ctx, `SELECT rated_at FROM talk_infos WHERE talk_id = $1`, talkID).Scan(&ratedAt)
+	if err != nil {
+		if isSQLNoRows(err) {
+			err = commerr.ErrNotFound
+		}
+
+		return
+	}
+
+	if ratedAt > 0 {
+		err = commerr.ErrAlreadyExists
+	} else {
+		err = defs.ErrInvalidTalkTransition
+	}
+
+	return
+}
+
+func (m *sqlModelImpl) ClassifyTalk(ctx context.Context, classification *defs.TalkClassification) (
+	talkInfo *defs.TalkInfoR, err error) {
+	if classification == nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	if _, err = primitive.ObjectIDFromHex(classification.TalkID); err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var exists int
+
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM talk_infos WHERE talk_id = $1`, classification.TalkID).Scan(&exists)
+	if err != nil {
+		if isSQLNoRows(err) {
+			err = commerr.ErrNotFound
+		}
+
+		return
+	}
+
+	if err = m.classifyTalk(ctx, tx, classification); err != nil {
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	talks, err := m.queryTalksEx(ctx, 0, 0, classification.TalkID, nil, nil)
+	if err != nil {
+		return
+	}
+
+	if len(talks) == 0 {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	talkInfo = talks[0]
+
+	return
+}
+
+func (m *sqlModelImpl) AddTalkMessage(ctx context.Context, talkID string, message *defs.TalkMessageW) (err error) {
+	if message == nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var talkCount int
+
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM talk_infos WHERE talk_id = $1`, talkID).Scan(&talkCount)
+	if err != nil {
+		return
+	}
+
+	if talkCount == 0 {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	var seq uint64
+
+	// the upsert locks the counter row until commit, so concurrent writers of a talk are serialized
+	err = tx.QueryRowContext(ctx, `INSERT INTO talk_seqs (talk_id, seq) VALUES ($1, 1)
+		ON CONFLICT (talk_id) DO UPDATE SET seq = talk_seqs.seq + 1 RETURNING seq`, talkID).Scan(&seq)
+	if err != nil {
+		return
+	}
+
+	// the talk_seqs row is locked, so the retries of a message are serialized too
+	if message.ClientSeqID > 0 {
+		var storedMessages []*defs.TalkMessageR
+
+		storedMessages, err = m.queryTalkMessagesTx(ctx, tx, `SELECT `+sqlTalkMessageColumns+` FROM talk_messages
+			WHERE talk_id = $1 AND customer_message = $2 AND sender_id = $3 AND client_seq_id = $4`,
+			talkID, message.CustomerMessage, message.SenderID, message.ClientSeqID)
+		if err != nil {
+			return
+		}
+
+		if len(storedMessages) > 0 {
+			*message = storedMessages[0].TalkMessageW
+			err = commerr.ErrAlreadyExists
+
+			return
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO talk_messages (talk_id, `+sqlTalkMessageColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		talkID, primitive.NewObjectID().Hex(), seq, message.At, message.CustomerMessage, message.Type, message.SenderID,
+		message.SenderUserName, message.Text, message.Data, message.ClientSeqID)
+	if err != nil {
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO talk_outbox (talk_id, seq, lease_until) VALUES ($1, $2, $3)`,
+		talkID, seq, outboxFirstLeaseUntil())
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	message.Seq = seq
+
+	return
+}
+
+func (m *sqlModelImpl) GetTalkMessages(ctx context.Context, talkID string, offset, count int64) (messages []*defs.TalkMessageR, err error) {
+	query := `SELECT ` + sqlTalkMessageColumns + ` FROM talk_messages WHERE talk_id = $1 ORDER BY seq`
+	queryArgs := []interface{}{talkID}
+
+	if count > 0 {
+		query += ` LIMIT $2 OFFSET $3`
+
+		queryArgs = append(queryArgs, count, offset)
+	}
+
+	return m.queryTalkMessages(ctx, query, queryArgs...)
+}
+
+func (m *sqlModelImpl) GetTalkMessagesByCursor(ctx context.Context, talkID, messageID string, before bool,
+	count int64, filter *defs.TalkMessageFilter) (messages []*defs.TalkMessageR, err error) {
+	where := &sqlWhere{}
+
+	where.add("talk_id = ?", talkID)
+	m.addTalkMessageFilterWhere(where, filter)
+
+	op, sortOrder := ">", "ASC"
+	if before {
+		op, sortOrder = "<", "DESC"
+	}
+
+	if messageID != "" {
+		if _, err = primitive.ObjectIDFromHex(messageID); err != nil {
+			err = commerr.ErrInvalidArgument
+
+			return
+		}
+
+		var seq uint64
+
+		err = m.db.QueryRowContext(ctx, `SELECT seq FROM talk_messages WHERE talk_id = $1 AND message_id = $2`,
+			talkID, messageID).Scan(&seq)
+		if err != nil {
+			if isSQLNoRows(err) {
+				err = commerr.ErrNotFound
+			}
+
+			return
+		}
+
+		where.add("seq "+op+" ?", seq)
+	}
+
+	query := `SELECT ` + sqlTalkMessageColumns + ` FROM talk_messages` + where.String() + ` ORDER BY seq ` + sortOrder
+
+	if count > 0 {
+		where.args = append(where.args, count)
+		query += fmt.Sprintf(" LIMIT $%d", len(where.args))
+	}
+
+	messages, err = m.queryTalkMessages(ctx, query, where.args...)
+	if err != nil {
+		return
+	}
+
+	if before {
+		reverseTalkMessages(messages)
+	}
+
+	return
+}
+
+func (m *sqlModelImpl) GetTalkMessagesSince(ctx context.Context, talkID string, at int64, count int64,
+	filter *defs.TalkMessageFilter) (messages []*defs.TalkMessageR, err error) {
+	where := &sqlWhere{}
+
+	where.add("talk_id = ?", talkID)
+	where.add("at >= ?", at)
+	m.addTalkMessageFilterWhere(where, filter)
+
+	query := `SELECT ` + sqlTalkMessageColumns + ` FROM talk_messages` + where.String() + ` ORDER BY seq`
+
+	if count > 0 {
+		where.args = append(where.args, count)
+		query += fmt.Sprintf(" LIMIT $%d", len(where.args))
+	}
+
+	return m.queryTalkMessages(ctx, query, where.args...)
+}
+
+func (m *sqlModelImpl) QueryTalks(ctx context.Context, creatorID, serviceID uint64, talkID string,
+	statuses []defs.TalkStatus, filter *defs.TalkFilter) (talks []*defs.TalkInfoR, err error) {
+	where, err := m.queryTalkWhere(creatorID, serviceID, talkID, statuses)
+	if err != nil {
+		return
+	}
+
+	m.addTalkFilterWhere(where, filter)
+
+	return m.queryTalksByWhere(ctx, where)
+}
+
+func (m *sqlModelImpl) SearchTalks(ctx context.Context, search *defs.TalkSearch) (talks []*defs.TalkInfoR, err error) {
+	where, err := m.queryTalkWhere(search.CreatorID, search.ServiceID, "", search.Statuses)
+	if err != nil {
+		return
+	}
+
+	m.addTalkFilterWhere(where, search.Filter)
+
+	op, sortOrder := "<", "DESC"
+	if search.Sort == defs.TalkSortStartAtAsc {
+		op, sortOrder = ">", "ASC"
+	}
+
+	if search.After != nil {
+		if _, err = primitive.ObjectIDFromHex(search.After.TalkID); err != nil {
+			err = commerr.ErrInvalidArgument
+
+			return
+		}
+
+		where.add("(start_at "+op+" ? OR start_at = ? AND talk_id "+op+" ?)", search.After.StartAt,
+			search.After.StartAt, search.After.TalkID)
+	}
+
+	return m.queryTalksPage(ctx, where, "start_at "+sortOrder+", talk_id "+sortOrder, search.Limit)
+}
+
+func (m *sqlModelImpl) GetPendingTalkInfos(ctx context.Context) ([]*defs.TalkInfoR, error) {
+	return m.queryTalksEx(ctx, 0, 0, "", []defs.TalkStatus{defs.TalkStatusQueued}, map[string]interface{}{
+		"service_id": 0,
+	})
+}
+
+func (m *sqlModelImpl) GetRatedTalkInfos(ctx context.Context, from, to int64) ([]*defs.TalkInfoR, error) {
+	where := &sqlWhere{}
+
+	where.add("rated_at > 0")
+	where.add("rated_at >= ?", from)
+	where.add("rated_at < ?", to)
+
+	return m.queryTalksByWhere(ctx, where)
+}
+
+func (m *sqlModelImpl) UpdateTalkServiceID(ctx context.Context, talkID string, serviceID uint64) (err error) {
+	return m.updateTalkInfo(ctx, talkID, "service_id", serviceID)
+}
+
+func (m *sqlModelImpl) CompareAndSetTalkServiceID(ctx context.Context, talkID string, expectedServiceID, serviceID uint64) (swapped bool,
+	curServiceID uint64, err error) {
+	if _, err = primitive.ObjectIDFromHex(talkID); err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	r, err := m.db.ExecContext(ctx, `UPDATE talk_infos SET service_id = $1 WHERE talk_id = $2 AND service_id = $3`,
+		serviceID, talkID, expectedServiceID)
+	if err != nil {
+		return
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if n > 0 {
+		swapped, curServiceID = true, serviceID
+
+		return
+	}
+
+	err = m.db.QueryRowContext(ctx, `SELECT service_id FROM talk_infos WHERE talk_id = $1`, talkID).Scan(&curServiceID)
+	if isSQLNoRows(err) {
+		err = commerr.ErrNotFound
+	}
+
+	return
+}
+
+func (m *sqlModelImpl) OfferTalkTransfer(ctx context.Context, transfer *defs.TalkTransfer) (err error) {
+	if transfer == nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	if _, err = primitive.ObjectIDFromHex(transfer.TalkID); err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	r, err := m.db.ExecContext(ctx, `INSERT INTO talk_transfers (`+sqlTransferColumns+`) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (talk_id) DO NOTHING`, transfer.TalkID, transfer.FromServicerID, transfer.ToServicerID, transfer.ToTeam,
+		transfer.Reason, transfer.ExpireAt)
+	if err != nil {
+		return
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if n == 0 {
+		err = commerr.ErrAlreadyExists
+	}
+
+	return
+}
+
+func (m *sqlModelImpl) GetTalkTransfer(ctx context.Context, talkID string) (transfer *defs.TalkTransfer, err error) {
+	transfers, err := m.queryTalkTransfers(ctx, `SELECT `+sqlTransferColumns+` FROM talk_transfers WHERE talk_id = $1`,
+		talkID)
+	if err != nil {
+		return
+	}
+
+	if len(transfers) == 0 {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	transfer = transfers[0]
+
+	return
+}
+
+func (m *sqlModelImpl) RemoveTalkTransfer(ctx context.Context, talkID string, expireAt int64) (removed bool, err error) {
+	r, err := m.db.ExecContext(ctx, `DELETE FROM talk_transfers WHERE talk_id = $1 AND expire_at = $2`, talkID, expireAt)
+	if err != nil {
+		return
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	removed = n > 0
+
+	return
+}
+
+func (m *sqlModelImpl) GetExpiredTalkTransfers(ctx context.Context, now int64, count int64) (
+	transfers []*defs.TalkTransfer, err error) {
+	query := `SELECT ` + sqlTransferColumns + ` FROM talk_transfers WHERE expire_at <= $1 ORDER BY expire_at`
+	queryArgs := []interface{}{now}
+
+	if count > 0 {
+		query += ` LIMIT $2`
+
+		queryArgs = append(queryArgs, count)
+	}
+
+	return m.queryTalkTransfers(ctx, query, queryArgs...)
+}
+
+func (m *sqlModelImpl) LeaseOutboxMessages(ctx context.Context, now, leaseUntil int64, count int64) (messages []*defs.OutboxMessage, err error) {
+	query := `SELECT talk_id, seq, lease_until FROM talk_outbox WHERE lease_until <= $1 ORDER BY lease_until`
+	queryArgs := []interface{}{now}
+
+	if count > 0 {
+		query += ` LIMIT $2`
+
+		queryArgs = append(queryArgs, count)
+	}
+
+	type outboxEntry struct {
+		talkID     string
+		seq        uint64
+		leaseUntil int64
+	}
+
+	rows, err := m.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return
+	}
+
+	var entries []outboxEntry
+
+	for rows.Next() {
+		var entry outboxEntry
+
+		if err = rows.Scan(&entry.talkID, &entry.seq, &entry.leaseUntil); err != nil {
+			_ = rows.Close()
+
+			return
+		}
+
+		entries = append(entries, entry)
+	}
+
+	_ = rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		var r sql.Result
+
+		// another relay may have leased it since the select
+		r, err = m.db.ExecContext(ctx, `UPDATE talk_outbox SET lease_until = $1 WHERE talk_id = $2 AND seq = $3 AND lease_until = $4`,
+			leaseUntil, entry.talkID, entry.seq, entry.leaseUntil)
+		if err != nil {
+			return
+		}
+
+		var n int64
+
+		if n, err = r.RowsAffected(); err != nil {
+			return
+		}
+
+		if n == 0 {
+			continue
+		}
+
+		var talkMessages []*defs.TalkMessageR
+
+		talkMessages, err = m.queryTalkMessages(ctx, `SELECT `+sqlTalkMessageColumns+` FROM talk_messages WHERE talk_id = $1 AND seq = $2`,
+			entry.talkID, entry.seq)
+		if err != nil {
+			return
+		}
+
+		for _, message := range talkMessages {
+			messages = append(messages, &defs.OutboxMessage{
+				TalkID:       entry.talkID,
+				TalkMessageR: *message,
+			})
+		}
+	}
+
+	return
+}
+
+func (m *sqlModelImpl) AckOutboxMessage(ctx context.Context, talkID string, seq uint64) (err error) {
+	_, err = m.db.ExecContext(ctx, `DELETE FROM talk_outbox WHERE talk_id = $1 AND seq = $2`, talkID, seq)
+
+	return
+}
+
+//
+//
+//
+
+var sqlLikeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+type sqlWhere struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (w *sqlWhere) add(condition string, args ...interface{}) {
+	for _, arg := range args {
+		w.args = append(w.args, arg)
+		condition = strings.Replace(condition, "?", fmt.Sprintf("$%d", len(w.args)), 1)
+	}
+
+	w.conditions = append(w.conditions, condition)
+}
+
+func (w *sqlWhere) String() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+func (m *sqlModelImpl) queryTalkMessages(ctx context.Context, query string, args ...interface{}) (messages []*defs.TalkMessageR, err error) {
+	return m.queryTalkMessagesTx(ctx, nil, query, args...)
+}
+
+// queryTalkMessagesTx queries out of a transaction if tx is nil.
+func (m *sqlModelImpl) queryTalkMessagesTx(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (
+	messages []*defs.TalkMessageR, err error) {
+	var rows *sql.Rows
+
+	if tx != nil {
+		rows, err = tx.QueryContext(ctx, query, args...)
+	} else {
+		rows, err = m.db.QueryContext(ctx, query, args...)
+	}
+
+	if err != nil {
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var message defs.TalkMessageR
+
+		if err = rows.Scan(&message.MessageID, &message.Seq, &message.At, &message.CustomerMessage, &message.Type,
+			&message.SenderID, &message.SenderUserName, &message.Text, &message.Data, &message.ClientSeqID); err != nil {
+			return
+		}
+
+		messages = append(messages, &message)
+	}
+
+	err = rows.Err()
+
+	return
+}
+
+func (m *sqlModelImpl) queryTalkWhere(creatorID, serviceID uint64, talkID string, statuses []defs.TalkStatus) (where *sqlWhere, err error) {
+	where = &sqlWhere{}
+
+	if creatorID > 0 {
+		where.add("creator_id = ?", creatorID)
+	}
+
+	if serviceID > 0 {
+		where.add("service_id = ?", serviceID)
+	}
+
+	if len(statuses) > 0 {
+		placeholders := make([]string, 0, len(statuses))
+		statusArgs := make([]interface{}, 0, len(statuses))
+
+		for _, status := range statuses {
+			placeholders = append(placeholders, "?")
+			statusArgs = append(statusArgs, status)
+		}
+
+		where.add("status IN ("+strings.Join(placeholders, ", ")+")", statusArgs...)
+	}
+
+	if talkID != "" {
+		if _, err = primitive.ObjectIDFromHex(talkID); err != nil {
+			err = commerr.ErrInvalidArgument
+
+			return
+		}
+
+		where.add("talk_id = ?", talkID)
+	}
+
+	return
+}
+
+func (m *sqlModelImpl) queryTalksEx(ctx context.Context, creatorID, serviceID uint64, talkID string,
+	statuses []defs.TalkStatus, columnValues map[string]interface{}) (talks []*defs.TalkInfoR, err error) {
+	where, err := m.queryTalkWhere(creatorID, serviceID, talkID, statuses)
+	if err != nil {
+		return
+	}
+
+	for column, value := range columnValues {
+		where.add(column+" = ?", value)
+	}
+
+	return m.queryTalksByWhere(ctx, where)
+}
+
+func (m *sqlModelImpl) queryTalksByWhere(ctx context.Context, where *sqlWhere) (talks []*defs.TalkInfoR, err error) {
+	return m.queryTalksPage(ctx, where, "talk_id", 0)
+}
+
+// queryTalksPage orderBy is the ORDER BY list, limit 0 for no limit.
+func (m *sqlModelImpl) queryTalksPage(ctx context.Context, where *sqlWhere, orderBy string,
+	limit int) (talks []*defs.TalkInfoR, err error) {
+	query := `SELECT ` + sqlTalkInfoColumns + ` FROM talk_infos` + where.String() + ` ORDER BY ` + orderBy
+
+	if limit > 0 {
+		where.args = append(where.args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(where.args))
+	}
+
+	rows, err := m.db.QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			talkInfo   defs.TalkInfoR
+			formFields string
+		)
+
+		if err = rows.Scan(&talkInfo.TalkID, &talkInfo.Status, &talkInfo.Title, &talkInfo.StartAt, &talkInfo.FinishedAt,
+			&talkInfo.CreatorID, &talkInfo.ServiceID, &talkInfo.CreatorUserName, &talkInfo.QueuedAt,
+			&talkInfo.FirstAssignedAt, &talkInfo.FirstResponseAt, &talkInfo.Disposition, &talkInfo.WrapUpNote,
+			&talkInfo.Skill, &talkInfo.RatingScore, &talkInfo.RatingComment, &talkInfo.RatedAt, &formFields,
+			&talkInfo.ClientContext.PageURL, &talkInfo.ClientContext.UserAgent, &talkInfo.ClientContext.Locale,
+			&talkInfo.ClientContext.Referrer, &talkInfo.Category); err != nil {
+			return
+		}
+
+		if formFields != "" {
+			if err = json.Unmarshal([]byte(formFields), &talkInfo.FormFields); err != nil {
+				return
+			}
+		}
+
+		talks = append(talks, &talkInfo)
+	}
+
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	err = m.loadTalkClassifications(ctx, talks)
+
+	return
+}
+
+func (m *sqlModelImpl) addTalkMessageFilterWhere(where *sqlWhere, filter *defs.TalkMessageFilter) {
+	if filter == nil {
+		return
+	}
+
+	if len(filter.ExcludedTypes) > 0 {
+		placeholders := make([]string, 0, len(filter.ExcludedTypes))
+		typeArgs := make([]interface{}, 0, len(filter.ExcludedTypes))
+
+		for _, messageType := range filter.ExcludedTypes {
+			placeholders = append(placeholders, "?")
+			typeArgs = append(typeArgs, messageType)
+		}
+
+		where.add("type NOT IN ("+strings.Join(placeholders, ", ")+")", typeArgs...)
+	}
+
+	if len(filter.ExcludedSystemEvents) > 0 {
+		placeholders := make([]string, 0, len(filter.ExcludedSystemEvents))
+		eventArgs := []interface{}{defs.TalkMessageTypeSystem}
+
+		for _, event := range filter.ExcludedSystemEvents {
+			placeholders = append(placeholders, "?")
+			eventArgs = append(eventArgs, string(event))
+		}
+
+		where.add("NOT (type = ? AND text IN ("+strings.Join(placeholders, ", ")+"))", eventArgs...)
+	}
+}
+
+func (m *sqlModelImpl) addTalkFilterWhere(where *sqlWhere, filter *defs.TalkFilter) {
+	if filter == nil {
+		return
+	}
+
+	for _, tag := range filter.Tags {
+		where.add("EXISTS (SELECT 1 FROM talk_tags WHERE talk_tags.talk_id = talk_infos.talk_id AND tag = ?)", tag)
+	}
+
+	if filter.Category != "" {
+		where.add("category = ?", filter.Category)
+	}
+
+	if filter.Title != "" {
+		where.add(`LOWER(title) LIKE ? ESCAPE '\'`, "%"+sqlLikeEscaper.Replace(strings.ToLower(filter.Title))+"%")
+	}
+
+	for key, value := range filter.CustomFields {
+		where.add("EXISTS (SELECT 1 FROM talk_custom_fields WHERE talk_custom_fields.talk_id = talk_infos.talk_id"+
+			" AND field_key = ? AND value = ?)", key, value)
+	}
+
+	if filter.StartAtFrom > 0 {
+		where.add("start_at >= ?", filter.StartAtFrom)
+	}
+
+	if filter.StartAtTo > 0 {
+		where.add("start_at < ?", filter.StartAtTo)
+	}
+}
+
+// classifyTalk the tags are removed after added.
+func (m *sqlModelImpl) classifyTalk(ctx context.Context, tx *sql.Tx, classification *defs.TalkClassification) (err error) {
+	for _, tag := range classification.AddTags {
+		_, err = tx.ExecContext(ctx, `INSERT INTO talk_tags (talk_id, tag) VALUES ($1, $2)
+			ON CONFLICT (talk_id, tag) DO NOTHING`, classification.TalkID, tag)
+		if err != nil {
+			return
+		}
+	}
+
+	for _, tag := range classification.RemoveTags {
+		_, err = tx.ExecContext(ctx, `DELETE FROM talk_tags WHERE talk_id = $1 AND tag = $2`, classification.TalkID, tag)
+		if err != nil {
+			return
+		}
+	}
+
+	if classification.Category != nil {
+		_, err = tx.ExecContext(ctx, `UPDATE talk_infos SET category = $1 WHERE talk_id = $2`,
+			*classification.Category, classification.TalkID)
+		if err != nil {
+			return
+		}
+	}
+
+	for key, value := range classification.CustomFields {
+		if value == "" {
+			_, err = tx.ExecContext(ctx, `DELETE FROM talk_custom_fields WHERE talk_id = $1 AND field_key = $2`,
+				classification.TalkID, key)
+		} else {
+			_, err = tx.ExecContext(ctx, `INSERT INTO talk_custom_fields (talk_id, field_key, value) VALUES ($1, $2, $3)
+				ON CONFLICT (talk_id, field_key) DO UPDATE SET value = excluded.value`, classification.TalkID, key, value)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// loadTalkClassifications fills the tags and the custom fields kept in their own tables.
+func (m *sqlModelImpl) loadTalkClassifications(ctx context.Context, talks []*defs.TalkInfoR) (err error) {
+	talksByID := make(map[string]*defs.TalkInfoR, len(talks))
+
+	for _, talk := range talks {
+		talksByID[talk.TalkID] = talk
+	}
+
+	for start := 0; start < len(talks); start += sqlMaxTalkIDsPerQuery {
+		end := start + sqlMaxTalkIDsPerQuery
+		if end > len(talks) {
+			end = len(talks)
+		}
+
+		placeholders := make([]string, 0, end-start)
+		args := make([]interface{}, 0, end-start)
+
+		for idx, talk := range talks[start:end] {
+			placeholders = append(placeholders, fmt.Sprintf("$%d", idx+1))
+			args = append(args, talk.TalkID)
+		}
+
+		in := " WHERE talk_id IN (" + strings.Join(placeholders, ", ") + ")"
+
+		err = m.queryRows(ctx, `SELECT talk_id, tag FROM talk_tags`+in+` ORDER BY tag`, args, func(rows *sql.Rows) error {
+			var talkID, tag string
+
+			if err := rows.Scan(&talkID, &tag); err != nil {
+				return err
+			}
+
+			talksByID[talkID].Tags = append(talksByID[talkID].Tags, tag)
+
+			return nil
+		})
+		if err != nil {
+			return
+		}
+
+		err = m.queryRows(ctx, `SELECT talk_id, field_key, value FROM talk_custom_fields`+in, args, func(rows *sql.Rows) error {
+			var talkID, key, value string
+
+			if err := rows.Scan(&talkID, &key, &value); err != nil {
+				return err
+			}
+
+			talk := talksByID[talkID]
+			if talk.CustomFields == nil {
+				talk.CustomFields = make(map[string]string)
+			}
+
+			talk.CustomFields[key] = value
+
+			return nil
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// queryTalkTransfers returns the pending offers, their State is always offered.
+func (m *sqlModelImpl) queryTalkTransfers(ctx context.Context, query string, args ...interface{}) (
+	transfers []*defs.TalkTransfer, err error) {
+	err = m.queryRows(ctx, query, args, func(rows *sql.Rows) error {
+		transfer := &defs.TalkTransfer{
+			State: defs.TalkTransferStateOffered,
+		}
+
+		if err := rows.Scan(&transfer.TalkID, &transfer.FromServicerID, &transfer.ToServicerID, &transfer.ToTeam,
+			&transfer.Reason, &transfer.ExpireAt); err != nil {
+			return err
+		}
+
+		transfers = append(transfers, transfer)
+
+		return nil
+	})
+
+	return
+}
+
+func (m *sqlModelImpl) queryRows(ctx context.Context, query string, args []interface{}, scan func(rows *sql.Rows) error) (err error) {
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = scan(rows); err != nil {
+			return
+		}
+	}
+
+	err = rows.Err()
+
+	return
+}
+
+func (m *sqlModelImpl) transitTalkStatus(ctx context.Context, talkID string, from []defs.TalkStatus,
+	to defs.TalkStatus, columnValues map[string]interface{}) (err error) {
+	if _, err = primitive.ObjectIDFromHex(talkID); err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	where := &sqlWhere{args: []interface{}{to}}
+	set := "status = $1"
+
+	if timestamp, ok := talkStatusTimestamps[to]; ok {
+		where.args = append(where.args, time.Now().Unix())
+
+		if timestamp.once {
+			set += fmt.Sprintf(", %[1]s = CASE WHEN %[1]s = 0 THEN $2 ELSE %[1]s END", timestamp.column)
+		} else {
+			set += fmt.Sprintf(", %s = $2", timestamp.column)
+		}
+	}
+
+	for column, value := range columnValues {
+		where.args = append(where.args, value)
+		set += fmt.Sprintf(", %s = $%d", column, len(where.args))
+	}
+
+	where.add("talk_id = ?", talkID)
+
+	placeholders := make([]string, 0, len(from))
+	statusArgs := make([]interface{}, 0, len(from))
+
+	for _, status := range from {
+		placeholders = append(placeholders, "?")
+		statusArgs = append(statusArgs, status)
+	}
+
+	if len(from) > 0 {
+		where.add("status IN ("+strings.Join(placeholders, ", ")+")", statusArgs...)
+	} else {
+		where.add("1 = 0")
+	}
+
+	r, err := m.db.ExecContext(ctx, `UPDATE talk_infos SET `+set+where.String(), where.args...)
+	if err != nil {
+		return
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if n > 0 {
+		return
+	}
+
+	var exists int
+
+	err = m.db.QueryRowContext(ctx, `SELECT 1 FROM talk_infos WHERE talk_id = $1`, talkID).Scan(&exists)
+	if err == nil {
+		err = defs.ErrInvalidTalkTransition
+	} else if isSQLNoRows(err) {
+		err = commerr.ErrNotFound
+	}
+
+	return
+}
+
+func (m *sqlModelImpl) updateTalkInfo(ctx context.Context, talkID string, column string, value interface{}) (err error) {
+	if _, err = primitive.ObjectIDFromHex(talkID); err != nil {
+		err = commerr.ErrInvalidArgument
+
+		return
+	}
+
+	r, err := m.db.ExecContext(ctx, `UPDATE talk_infos SET `+column+` = $1 WHERE talk_id = $2`, value, talkID)
+	if err != nil {
+		return
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if n == 0 {
+		err = commerr.ErrNotFound
+	}
+
+	return
+}
+
+func isSQLNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
+
+// sqlFormFields stores the pre-chat form as a json object, empty if the customer filled in nothing.
+func sqlFormFields(formFields map[string]string) (s string, err error) {
+	if len(formFields) == 0 {
+		return
+	}
+
+	d, err := json.Marshal(formFields)
+	if err != nil {
+		return
+	}
+
+	s = string(d)
+
+	return
+}
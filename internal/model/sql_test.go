@@ -0,0 +1,81 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSQLiteModel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dsn := filepath.Join(t.TempDir(), "ut.db")
+
+	testModel(ctx, t, NewSQLModel(config.ModelTypeSQLite, dsn, nil))
+
+	// reopen: migrations must not be applied twice
+	db, err := openSQLDB(sqlDialectSQLite, dsn)
+	assert.Nil(t, err)
+	assert.Nil(t, migrateSQLDB(ctx, db, sqlDialectSQLite))
+
+	userModel := NewSQLUserPasswordModel(config.ModelTypeSQLite, dsn, "ut_users", nil)
+
+	user, err := userModel.AddUser(ctx, "u1", "p1")
+	assert.Nil(t, err)
+	assert.True(t, user.ID > 0)
+
+	_, err = userModel.AddUser(ctx, "u1", "p2")
+	assert.NotNil(t, err)
+
+	user2, err := userModel.GetUserByUserName(ctx, "u1")
+	assert.Nil(t, err)
+	assert.EqualValues(t, user.ID, user2.ID)
+	assert.EqualValues(t, "p1", user2.Password)
+
+	_, err = userModel.GetUser(ctx, user.ID+1)
+	assert.NotNil(t, err)
+}
+
+func TestSQLiteMigrateMessageSeq(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open(sqlDriverSQLite, filepath.Join(t.TempDir(), "ut.db"))
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY)`)
+	assert.Nil(t, err)
+	assert.Nil(t, applySQLMigration(ctx, db, sqlDialectSQLite, sqlMigrations[0]))
+
+	for _, talkID := range []string{"t1", "t2", "t1"} {
+		_, err = db.Exec(`INSERT INTO talk_messages (message_id, talk_id) VALUES ($1, $2)`,
+			primitive.NewObjectID().Hex(), talkID)
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, migrateSQLDB(ctx, db, sqlDialectSQLite))
+
+	m := &sqlModelImpl{db: db}
+
+	// messages are added to the existing talks only
+	_, err = db.Exec(`INSERT INTO talk_infos (talk_id) VALUES ($1)`, "t1")
+	assert.Nil(t, err)
+
+	messages, err := m.GetTalkMessages(ctx, "t1", 0, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(messages))
+	assert.EqualValues(t, 1, messages[0].Seq)
+	assert.EqualValues(t, 2, messages[1].Seq)
+
+	message := &defs.TalkMessageW{}
+	assert.Nil(t, m.AddTalkMessage(ctx, "t1", message))
+	assert.EqualValues(t, 3, message.Seq)
+}
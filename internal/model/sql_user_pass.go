@@ -0,0 +1,135 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sbasestarter/bizinters/userinters/userpass"
+	"github.com/sgostarter/i/l"
+	"github.com/sgostarter/libeasygo/commerr"
+)
+
+func NewSQLUserPasswordModel(modelType, dsn, tableName string, logger l.Wrapper) userpass.UserPasswordModel {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	d, ok := sqlDialectByModelType(modelType)
+	if !ok {
+		logger.WithFields(l.StringField("modelType", modelType)).Fatal("NotSQLModelType")
+
+		return nil
+	}
+
+	db, err := openSQLDB(d, dsn)
+	if err != nil {
+		logger.WithFields(l.ErrorField(err)).Fatal("OpenSQLDBFailed")
+
+		return nil
+	}
+
+	impl := &sqlUserPasswordModelImpl{
+		logger:    logger.WithFields(l.StringField(l.ClsKey, "sqlUserPasswordModelImpl")),
+		db:        db,
+		dialect:   d,
+		tableName: tableName,
+	}
+
+	impl.init()
+
+	return impl
+}
+
+type sqlUserPasswordModelImpl struct {
+	logger l.Wrapper
+
+	db        *sql.DB
+	dialect   sqlDialect
+	tableName string
+}
+
+func (impl *sqlUserPasswordModelImpl) init() {
+	// the table name is chosen by the caller, so it can't live in the fixed migrations
+	if _, err := impl.db.Exec(`CREATE TABLE IF NOT EXISTS ` + impl.tableName + ` (
+		id ` + impl.dialect.autoIDType + `,
+		user_name VARCHAR(255) NOT NULL UNIQUE,
+		password TEXT NOT NULL,
+		create_at BIGINT NOT NULL
+	)`); err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("CreateTableFailed")
+	}
+}
+
+func (impl *sqlUserPasswordModelImpl) AddUser(ctx context.Context, userName, password string) (user *userpass.User, err error) {
+	u := &userpass.User{
+		UserName: userName,
+		Password: password,
+		CreateAt: time.Now().Unix(),
+	}
+
+	err = impl.db.QueryRowContext(ctx, `INSERT INTO `+impl.tableName+` (user_name, password, create_at) VALUES ($1, $2, $3) RETURNING id`,
+		u.UserName, u.Password, u.CreateAt).Scan(&u.ID)
+	if err != nil {
+		return
+	}
+
+	user = u
+
+	return
+}
+
+func (impl *sqlUserPasswordModelImpl) DeleteUser(ctx context.Context, userID uint64) error {
+	_, err := impl.db.ExecContext(ctx, `DELETE FROM `+impl.tableName+` WHERE id = $1`, userID)
+
+	return err
+}
+
+func (impl *sqlUserPasswordModelImpl) GetUser(ctx context.Context, userID uint64) (user *userpass.User, err error) {
+	return impl.findUserOne(ctx, "id", userID)
+}
+
+func (impl *sqlUserPasswordModelImpl) GetUserByUserName(ctx context.Context, userName string) (user *userpass.User, err error) {
+	return impl.findUserOne(ctx, "user_name", userName)
+}
+
+func (impl *sqlUserPasswordModelImpl) ListUsers(ctx context.Context) (users []*userpass.User, err error) {
+	rows, err := impl.db.QueryContext(ctx, `SELECT id, user_name, password, create_at FROM `+impl.tableName+` ORDER BY id`)
+	if err != nil {
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var u userpass.User
+
+		if err = rows.Scan(&u.ID, &u.UserName, &u.Password, &u.CreateAt); err != nil {
+			return
+		}
+
+		users = append(users, &u)
+	}
+
+	err = rows.Err()
+
+	return
+}
+
+func (impl *sqlUserPasswordModelImpl) findUserOne(ctx context.Context, column string, value interface{}) (user *userpass.User, err error) {
+	var u userpass.User
+
+	err = impl.db.QueryRowContext(ctx, `SELECT id, user_name, password, create_at FROM `+impl.tableName+` WHERE `+column+` = $1`,
+		value).Scan(&u.ID, &u.UserName, &u.Password, &u.CreateAt)
+	if err != nil {
+		if isSQLNoRows(err) {
+			err = commerr.ErrNotFound
+		}
+
+		return
+	}
+
+	user = &u
+
+	return
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sgostarter/libeasygo/commerr"
+)
+
+// outboxFirstLease gives the direct fan-out after AddTalkMessage time to ack before the relay picks the message up.
+const outboxFirstLease = 10 * time.Second
+
+func outboxFirstLeaseUntil() int64 {
+	return time.Now().Add(outboxFirstLease).UnixMilli()
+}
+
+type talkStatusTimestamp struct {
+	field  string // the bson field name
+	column string // the sql column name
+	once   bool   // first-at timestamps are never overwritten
+}
+
+// talkStatusTimestamps are stamped when a talk moves to the status.
+var talkStatusTimestamps = map[defs.TalkStatus]talkStatusTimestamp{
+	defs.TalkStatusQueued:            {field: "QueuedAt", column: "queued_at"},
+	defs.TalkStatusAssigned:          {field: "FirstAssignedAt", column: "first_assigned_at", once: true},
+	defs.TalkStatusWaitingOnCustomer: {field: "FirstResponseAt", column: "first_response_at", once: true},
+	defs.TalkStatusResolved:          {field: "FinishedAt", column: "finished_at"},
+	defs.TalkStatusClosed:            {field: "FinishedAt", column: "finished_at"},
+	defs.TalkStatusAbandoned:         {field: "FinishedAt", column: "finished_at"},
+}
+
+// reopenTalkStatus returns the status OpenTalk moves talkInfo to, reopen is false if it's unfinished already.
+func reopenTalkStatus(talkInfos []*defs.TalkInfoR) (status defs.TalkStatus, reopen bool, err error) {
+	if len(talkInfos) == 0 {
+		err = commerr.ErrNotFound
+
+		return
+	}
+
+	switch talkInfos[0].Status {
+	case defs.TalkStatusQueued, defs.TalkStatusAssigned, defs.TalkStatusWaitingOnCustomer:
+	case defs.TalkStatusResolved, defs.TalkStatusClosed:
+		status, reopen = defs.TalkStatusAssigned, true
+		if talkInfos[0].ServiceID == 0 {
+			status = defs.TalkStatusQueued
+		}
+	default:
+		err = defs.ErrInvalidTalkTransition
+	}
+
+	return
+}
+
+func reverseTalkMessages(messages []*defs.TalkMessageR) {
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+}
+
+func cloneStrings(ss []string) []string {
+	if ss == nil {
+		return nil
+	}
+
+	return append(make([]string, 0, len(ss)), ss...)
+}
+
+func cloneStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	mCopy := make(map[string]string, len(m))
+
+	for key, value := range m {
+		mCopy[key] = value
+	}
+
+	return mCopy
+}
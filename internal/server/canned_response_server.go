@@ -0,0 +1,259 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/sbasestarter/customer-service-be/gens/cannedresponsepb"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/vo"
+	"github.com/sgostarter/i/l"
+	"github.com/sgostarter/libeasygo/commerr"
+	"google.golang.org/grpc/codes"
+)
+
+// NewCannedResponseServer servicerTeams are the teams of the servicers, the shared responses of a team are listed
+// to its servicers and managed by the supervisors.
+func NewCannedResponseServer(m defs.CannedResponseModel, modelEx defs.ModelEx, userTokenHelper defs.UserTokenHelper,
+	supervisorIDs []uint64, servicerTeams map[uint64][]string, logger l.Wrapper) cannedresponsepb.CannedResponseServiceServer {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	supervisors := make(map[uint64]bool, len(supervisorIDs))
+	for _, id := range supervisorIDs {
+		supervisors[id] = true
+	}
+
+	teamSet := make(map[string]bool)
+
+	for _, teams := range servicerTeams {
+		for _, team := range teams {
+			teamSet[team] = true
+		}
+	}
+
+	allTeams := make([]string, 0, len(teamSet))
+	for team := range teamSet {
+		allTeams = append(allTeams, team)
+	}
+
+	sort.Strings(allTeams)
+
+	return &cannedResponseServerImpl{
+		logger:          logger.WithFields(l.StringField(l.ClsKey, "cannedResponseServerImpl")),
+		model:           m,
+		modelEx:         modelEx,
+		userTokenHelper: userTokenHelper,
+		supervisors:     supervisors,
+		servicerTeams:   servicerTeams,
+		allTeams:        allTeams,
+	}
+}
+
+type cannedResponseServerImpl struct {
+	cannedresponsepb.UnimplementedCannedResponseServiceServer
+
+	logger          l.Wrapper
+	model           defs.CannedResponseModel
+	modelEx         defs.ModelEx
+	userTokenHelper defs.UserTokenHelper
+	supervisors     map[uint64]bool
+	servicerTeams   map[uint64][]string
+	allTeams        []string
+}
+
+func (impl *cannedResponseServerImpl) ListCannedResponses(ctx context.Context,
+	_ *cannedresponsepb.ListCannedResponsesRequest) (*cannedresponsepb.ListCannedResponsesResponse, error) {
+	userID, err := impl.extractUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := impl.servicerTeams[userID]
+	if impl.supervisors[userID] {
+		teams = impl.allTeams
+	}
+
+	responses, err := impl.model.ListCannedResponses(ctx, userID, teams)
+	if err != nil {
+		impl.logger.WithFields(l.ErrorField(err)).Error("ListCannedResponsesFailed")
+
+		return nil, gRpcError(codes.Internal, err)
+	}
+
+	return &cannedresponsepb.ListCannedResponsesResponse{
+		Responses: vo.CannedResponsesDB2Pb(responses),
+	}, nil
+}
+
+func (impl *cannedResponseServerImpl) CreateCannedResponse(ctx context.Context,
+	request *cannedresponsepb.CreateCannedResponseRequest) (*cannedresponsepb.CreateCannedResponseResponse, error) {
+	userID, err := impl.extractUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := vo.CannedResponsePb2Db(request.GetResponse())
+	if !validCannedResponse(response) {
+		return nil, gRpcMessageError(codes.InvalidArgument, "")
+	}
+
+	response.OwnerID = userID
+
+	if response.Team != "" {
+		if !impl.supervisors[userID] {
+			return nil, gRpcMessageError(codes.PermissionDenied, "")
+		}
+
+		response.OwnerID = 0
+	}
+
+	if err = impl.model.CreateCannedResponse(ctx, response); err != nil {
+		return nil, impl.modelError(err, "CreateCannedResponseFailed")
+	}
+
+	return &cannedresponsepb.CreateCannedResponseResponse{
+		Response: vo.CannedResponseDB2Pb(response),
+	}, nil
+}
+
+func (impl *cannedResponseServerImpl) UpdateCannedResponse(ctx context.Context,
+	request *cannedresponsepb.UpdateCannedResponseRequest) (*cannedresponsepb.UpdateCannedResponseResponse, error) {
+	userID, err := impl.extractUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := vo.CannedResponsePb2Db(request.GetResponse())
+	if !validCannedResponse(response) {
+		return nil, gRpcMessageError(codes.InvalidArgument, "")
+	}
+
+	if err = impl.checkResponseEditable(ctx, userID, response.ID); err != nil {
+		return nil, err
+	}
+
+	if err = impl.model.UpdateCannedResponse(ctx, response); err != nil {
+		return nil, impl.modelError(err, "UpdateCannedResponseFailed")
+	}
+
+	return &cannedresponsepb.UpdateCannedResponseResponse{
+		Response: vo.CannedResponseDB2Pb(response),
+	}, nil
+}
+
+func (impl *cannedResponseServerImpl) DeleteCannedResponse(ctx context.Context,
+	request *cannedresponsepb.DeleteCannedResponseRequest) (*cannedresponsepb.DeleteCannedResponseResponse, error) {
+	userID, err := impl.extractUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = impl.checkResponseEditable(ctx, userID, request.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err = impl.model.DeleteCannedResponse(ctx, request.GetId()); err != nil {
+		return nil, impl.modelError(err, "DeleteCannedResponseFailed")
+	}
+
+	return &cannedresponsepb.DeleteCannedResponseResponse{}, nil
+}
+
+func (impl *cannedResponseServerImpl) RenderCannedResponse(ctx context.Context,
+	request *cannedresponsepb.RenderCannedResponseRequest) (*cannedresponsepb.RenderCannedResponseResponse, error) {
+	userID, err := impl.extractUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := impl.model.GetCannedResponse(ctx, request.GetId())
+	if err != nil {
+		return nil, impl.modelError(err, "GetCannedResponseFailed")
+	}
+
+	if !impl.canUse(userID, response) {
+		return nil, gRpcMessageError(codes.PermissionDenied, "")
+	}
+
+	var talkInfo *defs.TalkInfoR
+
+	if request.GetTalkId() != "" {
+		talkInfo, err = impl.modelEx.GetTalkInfo(ctx, request.GetTalkId())
+		if err != nil {
+			return nil, impl.modelError(err, "GetTalkInfoFailed")
+		}
+	}
+
+	return &cannedresponsepb.RenderCannedResponseResponse{
+		Text: response.Render(talkInfo),
+	}, nil
+}
+
+//
+//
+//
+
+func (impl *cannedResponseServerImpl) extractUser(ctx context.Context) (userID uint64, err error) {
+	_, userID, _, err = impl.userTokenHelper.ExtractUserFromGRPCContext(ctx, false)
+	if err != nil {
+		err = gRpcError(codes.Unauthenticated, err)
+
+		return
+	}
+
+	return
+}
+
+// checkResponseEditable the personal responses are edited by their owners, the shared ones by the supervisors.
+func (impl *cannedResponseServerImpl) checkResponseEditable(ctx context.Context, userID uint64, id string) (err error) {
+	response, err := impl.model.GetCannedResponse(ctx, id)
+	if err != nil {
+		err = impl.modelError(err, "GetCannedResponseFailed")
+
+		return
+	}
+
+	if response.Shared() && !impl.supervisors[userID] || !response.Shared() && response.OwnerID != userID {
+		err = gRpcMessageError(codes.PermissionDenied, "")
+
+		return
+	}
+
+	return
+}
+
+func (impl *cannedResponseServerImpl) canUse(userID uint64, response *defs.CannedResponse) bool {
+	if !response.Shared() {
+		return response.OwnerID == userID
+	}
+
+	if impl.supervisors[userID] {
+		return true
+	}
+
+	for _, team := range impl.servicerTeams[userID] {
+		if team == response.Team {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (impl *cannedResponseServerImpl) modelError(err error, msg string) error {
+	switch {
+	case errors.Is(err, commerr.ErrNotFound):
+		return gRpcError(codes.NotFound, err)
+	case errors.Is(err, commerr.ErrInvalidArgument):
+		return gRpcError(codes.InvalidArgument, err)
+	case errors.Is(err, commerr.ErrAlreadyExists):
+		return gRpcError(codes.AlreadyExists, err)
+	}
+
+	impl.logger.WithFields(l.ErrorField(err)).Error(msg)
+
+	return gRpcError(codes.Internal, err)
+}
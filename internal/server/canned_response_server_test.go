@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/gens/cannedresponsepb"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/impls"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// utUserTokenHelper takes the user id as the token.
+type utUserTokenHelper struct{}
+
+func (utUserTokenHelper) ExtractTokenFromGRPCContext(ctx context.Context) (token string, err error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vs := md.Get("token"); len(vs) > 0 {
+			token = vs[0]
+		}
+	}
+
+	return
+}
+
+func (utUserTokenHelper) ExplainToken(_ context.Context, token string, _ bool) (newToken string, userID uint64,
+	userName string, err error) {
+	userID, err = strconv.ParseUint(token, 10, 64)
+	userName = token
+
+	return
+}
+
+func (h utUserTokenHelper) ExtractUserFromGRPCContext(ctx context.Context, renewToken bool) (newToken string, userID uint64,
+	userName string, err error) {
+	token, _ := h.ExtractTokenFromGRPCContext(ctx)
+
+	return h.ExplainToken(ctx, token, renewToken)
+}
+
+func TestCannedResponseServer(t *testing.T) {
+	modelEx := impls.NewModelEx(model.NewMemoryModel())
+
+	s := NewCannedResponseServer(model.NewMemoryCannedResponseModel(), modelEx, utUserTokenHelper{}, []uint64{9},
+		map[uint64][]string{1: {"billing"}}, nil)
+
+	userCtx := func(userID uint64) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("token", strconv.FormatUint(userID, 10)))
+	}
+
+	errCode := func(err error) codes.Code {
+		return status.Code(err)
+	}
+
+	_, err := s.CreateCannedResponse(userCtx(1), &cannedresponsepb.CreateCannedResponseRequest{
+		Response: &cannedresponsepb.CannedResponse{Team: "billing", Shortcut: "/refund", Text: "refund"},
+	})
+	assert.EqualValues(t, codes.PermissionDenied, errCode(err))
+
+	shared, err := s.CreateCannedResponse(userCtx(9), &cannedresponsepb.CreateCannedResponseRequest{
+		Response: &cannedresponsepb.CannedResponse{Team: "billing", Shortcut: "/refund", Text: "{{customer_name}}，退款已受理：{{talk_title}}"},
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, shared.GetResponse().GetOwnerId())
+
+	personal, err := s.CreateCannedResponse(userCtx(1), &cannedresponsepb.CreateCannedResponseRequest{
+		Response: &cannedresponsepb.CannedResponse{OwnerId: 2, Shortcut: "/hi", Text: "hi"},
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, personal.GetResponse().GetOwnerId())
+
+	listResp, err := s.ListCannedResponses(userCtx(1), &cannedresponsepb.ListCannedResponsesRequest{})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(listResp.GetResponses()))
+
+	listResp, err = s.ListCannedResponses(userCtx(2), &cannedresponsepb.ListCannedResponsesRequest{})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(listResp.GetResponses()))
+
+	_, err = s.UpdateCannedResponse(userCtx(1), &cannedresponsepb.UpdateCannedResponseRequest{
+		Response: &cannedresponsepb.CannedResponse{Id: shared.GetResponse().GetId(), Shortcut: "/refund", Text: "x"},
+	})
+	assert.EqualValues(t, codes.PermissionDenied, errCode(err))
+
+	_, err = s.DeleteCannedResponse(userCtx(2), &cannedresponsepb.DeleteCannedResponseRequest{Id: personal.GetResponse().GetId()})
+	assert.EqualValues(t, codes.PermissionDenied, errCode(err))
+
+	talkID, err := modelEx.CreateTalk(context.Background(), &defs.TalkInfoW{
+		Status:          defs.TalkStatusQueued,
+		Title:           "订单 42",
+		CreatorUserName: "张三",
+	})
+	assert.Nil(t, err)
+
+	renderResp, err := s.RenderCannedResponse(userCtx(1), &cannedresponsepb.RenderCannedResponseRequest{
+		Id:     shared.GetResponse().GetId(),
+		TalkId: talkID,
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "张三，退款已受理：订单 42", renderResp.GetText())
+
+	_, err = s.RenderCannedResponse(userCtx(2), &cannedresponsepb.RenderCannedResponseRequest{
+		Id:     shared.GetResponse().GetId(),
+		TalkId: talkID,
+	})
+	assert.EqualValues(t, codes.PermissionDenied, errCode(err))
+
+	_, err = s.DeleteCannedResponse(userCtx(1), &cannedresponsepb.DeleteCannedResponseRequest{Id: personal.GetResponse().GetId()})
+	assert.Nil(t, err)
+
+	_, err = s.DeleteCannedResponse(userCtx(1), &cannedresponsepb.DeleteCannedResponseRequest{Id: personal.GetResponse().GetId()})
+	assert.EqualValues(t, codes.NotFound, errCode(err))
+}
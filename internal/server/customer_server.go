@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,26 +10,35 @@ import (
 	"github.com/sbasestarter/customer-service-be/config"
 	"github.com/sbasestarter/customer-service-be/internal/controller"
 	"github.com/sbasestarter/customer-service-be/internal/defs"
-	"github.com/sbasestarter/customer-service-be/internal/impls"
-	"github.com/sbasestarter/customer-service-be/internal/model"
 	"github.com/sbasestarter/customer-service-be/internal/vo"
 	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
 	"github.com/sgostarter/i/l"
+	"github.com/sgostarter/libeasygo/commerr"
 	"google.golang.org/grpc/codes"
 )
 
-func NewCustomerServer(controller *controller.CustomerController, userTokenHelper defs.UserTokenHelper, logger l.Wrapper) customertalkpb.CustomerTalkServiceServer {
+const (
+	notifyRatingAccepted   = "ratingAccepted"
+	notifyInvalidRating    = "invalidRating"
+	notifyRatingNotAllowed = "ratingNotAllowed"
+	notifyTalkRated        = "talkRated"
+	notifyRatingFailed     = "ratingFailed"
+)
+
+// NewCustomerServer preChatCfg is the form the customers fill in on creating a talk, nil for none.
+func NewCustomerServer(controller *controller.CustomerController, m defs.ModelEx, userTokenHelper defs.UserTokenHelper,
+	preChatCfg *config.PreChatConfig, logger l.Wrapper) customertalkpb.CustomerTalkServiceServer {
 	if logger == nil {
 		logger = l.NewNopLoggerWrapper()
 	}
 
-	m := impls.NewModelEx(model.NewMongoModel(&config.GetConfig().MongoConfig, logger))
-
 	return &customerServerImpl{
 		logger:          logger,
 		controller:      controller,
 		userTokenHelper: userTokenHelper,
 		model:           m,
+		dedup:           newMessageDedup(defMessageDedupWindow, defMessageDedupMaxSize),
+		preChatForm:     newPreChatForm(preChatCfg, logger),
 	}
 }
 
@@ -38,6 +48,8 @@ type customerServerImpl struct {
 	logger          l.Wrapper
 	userTokenHelper defs.UserTokenHelper
 	model           defs.ModelEx
+	dedup           *messageDedup
+	preChatForm     *preChatForm
 
 	controller *controller.CustomerController
 }
@@ -50,7 +62,7 @@ func (impl *customerServerImpl) QueryTalks(ctx context.Context, request *custome
 		return nil, gRpcError(codes.Unauthenticated, nil)
 	}
 
-	talkInfos, err := impl.model.QueryTalks(ctx, userID, 0, "", vo.TaskStatusesMapPb2Db(request.GetStatuses()))
+	talkInfos, err := impl.model.QueryTalks(ctx, userID, 0, "", vo.TaskStatusesMapPb2Db(request.GetStatuses()), nil)
 	if err != nil {
 		impl.logger.WithFields(l.ErrorField(err)).Error("QueryTalksFailed")
 
@@ -102,9 +114,15 @@ func (impl *customerServerImpl) Talk(server customertalkpb.CustomerTalkService_T
 
 	logger = logger.WithFields(l.StringField("talkID", talkID))
 
+	var resumePoint *defs.ResumePoint
+
+	if !createTalkFlag {
+		resumePoint = resumePointFromGRPCContext(server.Context())
+	}
+
 	chSendMessage := make(chan *customertalkpb.TalkResponse, 100)
 
-	customer := controller.NewCustomer(uniqueID, talkID, createTalkFlag, userID, chSendMessage)
+	customer := controller.NewCustomer(uniqueID, talkID, createTalkFlag, userID, resumePoint, chSendMessage)
 
 	err = impl.controller.InstallCustomer(customer)
 	if err != nil {
@@ -175,12 +193,31 @@ func (impl *customerServerImpl) handleTalkStart(ctx context.Context, userID uint
 			return
 		}
 
+		formFields, ok := vo.TalkCreateFormFromUnknown(request.GetCreate())
+		if !ok {
+			err = gRpcMessageError(codes.InvalidArgument, "invalidPreChatForm")
+
+			return
+		}
+
+		if invalidKey, valid := impl.preChatForm.Validate(formFields); !valid {
+			err = gRpcMessageError(codes.InvalidArgument, "invalidPreChatField:"+invalidKey)
+
+			return
+		}
+
+		now := time.Now().Unix()
+
 		talkID, err = impl.model.CreateTalk(ctx, &defs.TalkInfoW{
-			Status:          defs.TalkStatusOpened,
+			Status:          defs.TalkStatusQueued,
 			Title:           request.GetCreate().GetTitle(),
-			StartAt:         time.Now().Unix(),
+			StartAt:         now,
+			QueuedAt:        now,
+			Skill:           talkSkillFromGRPCContext(ctx),
 			CreatorID:       userID,
 			CreatorUserName: userName,
+			FormFields:      formFields,
+			ClientContext:   talkClientContextFromGRPCContext(ctx),
 		})
 
 		talkCreateFlag = true
@@ -218,15 +255,45 @@ func (impl *customerServerImpl) customerReceiveRoutine(server customertalkpb.Cus
 		}
 
 		if message := request.GetMessage(); message != nil {
+			dedupKey := messageDedupKey{
+				customer: true,
+				senderID: userID,
+				talkID:   customer.GetTalkID(),
+				seqID:    message.SeqId,
+			}
+
+			if message.SeqId > 0 {
+				if storedMessage, dup := impl.dedup.reserve(dedupKey); dup {
+					impl.confirmDuplicatedMessage(customer, message.SeqId, storedMessage, logger)
+
+					continue
+				}
+			}
+
 			dbMessage := vo.TalkMessageWPb2Db(message)
-			dbMessage.At = time.Now().Unix()
+			dbMessage.At = time.Now().UnixMilli()
 			dbMessage.CustomerMessage = true
 			dbMessage.SenderID = userID
 			dbMessage.SenderUserName = userName
+			dbMessage.ClientSeqID = message.SeqId
 
-			err = impl.model.AddTalkMessage(server.Context(), customer.GetTalkID(), dbMessage)
-			if err != nil {
-				logger.WithFields(l.ErrorField(err)).Error("AddTalkMessageFailed")
+			dup, reason := impl.storeMessage(server.Context(), customer, dbMessage, logger)
+			if reason != "" {
+				impl.dedup.release(dedupKey)
+
+				if err = customer.SendMessage(vo.MessageFailed4Customer(message.SeqId, reason)); err != nil {
+					logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+					break
+				}
+
+				continue
+			}
+
+			impl.dedup.done(dedupKey, dbMessage)
+
+			if dup {
+				impl.confirmDuplicatedMessage(customer, message.SeqId, dbMessage, logger)
 
 				continue
 			}
@@ -242,6 +309,21 @@ func (impl *customerServerImpl) customerReceiveRoutine(server customertalkpb.Cus
 			if err != nil {
 				logger.WithFields(l.ErrorField(err)).Error("CustomerCloseFailed")
 
+				break
+			}
+		} else if queryRequest := vo.TalkQueryMessagesRequestFromUnknown(request); queryRequest != nil {
+			err = impl.controller.CustomerQueryMessages(customer, queryRequest.MessageID, queryRequest.Before,
+				queryRequest.Count)
+			if err != nil {
+				logger.WithFields(l.ErrorField(err)).Error("CustomerQueryMessagesFailed")
+
+				break
+			}
+		} else if rateRequest := vo.TalkRateRequestFromUnknown(request); rateRequest != nil {
+			err = impl.sendNotify(customer, impl.rateTalk(server.Context(), customer, userID, rateRequest, logger))
+			if err != nil {
+				logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
 				break
 			}
 		} else {
@@ -249,3 +331,121 @@ func (impl *customerServerImpl) customerReceiveRoutine(server customertalkpb.Cus
 		}
 	}
 }
+
+// rateTalk returns the notify the customer gets, the rating is accepted on the stream even after the talk is closed.
+func (impl *customerServerImpl) rateTalk(ctx context.Context, customer defs.Customer, userID uint64,
+	rateRequest *vo.TalkRateRequest, logger l.Wrapper) (notify string) {
+	if !validTalkRateRequest(rateRequest) {
+		notify = notifyInvalidRating
+
+		return
+	}
+
+	talkInfo, err := impl.model.GetTalkInfo(ctx, customer.GetTalkID())
+	if err != nil {
+		logger.WithFields(l.ErrorField(err)).Error("GetTalkInfoFailed")
+
+		notify = notifyRatingFailed
+
+		return
+	}
+
+	if talkInfo.CreatorID != userID || talkInfo.ServiceID == 0 {
+		notify = notifyRatingNotAllowed
+
+		return
+	}
+
+	err = impl.model.RateTalk(ctx, customer.GetTalkID(), rateRequest.Score, rateRequest.Comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, commerr.ErrAlreadyExists):
+			notify = notifyTalkRated
+		case errors.Is(err, defs.ErrInvalidTalkTransition):
+			notify = notifyRatingNotAllowed
+		default:
+			logger.WithFields(l.ErrorField(err)).Error("RateTalkFailed")
+
+			notify = notifyRatingFailed
+		}
+
+		return
+	}
+
+	notify = notifyRatingAccepted
+
+	return
+}
+
+func (impl *customerServerImpl) sendNotify(customer defs.Customer, msg string) error {
+	return customer.SendMessage(&customertalkpb.TalkResponse{
+		Talk: &customertalkpb.TalkResponse_Notify{
+			Notify: &customertalkpb.TalkNotifyResponse{
+				Msg: msg,
+			},
+		},
+	})
+}
+
+// storeMessage returns the reason if the message is rejected.
+// storeMessage dup is true if the message was stored before, by an earlier connection or on another instance,
+// dbMessage is the stored one then.
+func (impl *customerServerImpl) storeMessage(ctx context.Context, customer defs.Customer, dbMessage *defs.TalkMessageW,
+	logger l.Wrapper) (dup bool, reason defs.MessageFailedReason) {
+	if messageTooLarge(dbMessage) {
+		reason = defs.MessageFailedReasonTooLarge
+
+		return
+	}
+
+	talkInfo, err := impl.model.GetTalkInfo(ctx, customer.GetTalkID())
+	if err != nil {
+		logger.WithFields(l.ErrorField(err)).Error("GetTalkInfoFailed")
+
+		reason = defs.MessageFailedReasonStorage
+
+		return
+	}
+
+	// a resolved talk must be reopened first
+	if !talkInfo.Status.Opened() {
+		reason = defs.MessageFailedReasonTalkClosed
+
+		return
+	}
+
+	if err = impl.model.AddTalkMessage(ctx, customer.GetTalkID(), dbMessage); err != nil {
+		if errors.Is(err, commerr.ErrAlreadyExists) {
+			dup = true
+
+			return
+		}
+
+		logger.WithFields(l.ErrorField(err)).Error("AddTalkMessageFailed")
+
+		reason = defs.MessageFailedReasonStorage
+
+		return
+	}
+
+	if talkInfo.Status == defs.TalkStatusWaitingOnCustomer {
+		if err = impl.model.UpdateTalkStatus(ctx, customer.GetTalkID(), defs.TalkStatusAssigned); err != nil {
+			logger.WithFields(l.ErrorField(err)).Warn("UpdateTalkStatusFailed")
+		}
+	}
+
+	return
+}
+
+func (impl *customerServerImpl) confirmDuplicatedMessage(customer defs.Customer, seqID uint64,
+	storedMessage *defs.TalkMessageW, logger l.Wrapper) {
+	if storedMessage == nil {
+		logger.WithFields(l.UInt64Field("seqID", seqID)).Warn("DuplicatedMessageInFlight")
+
+		return
+	}
+
+	if err := customer.SendMessage(vo.MessageConfirmed4Customer(seqID, storedMessage)); err != nil {
+		logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+)
+
+const (
+	defMessageDedupWindow  = 10 * time.Minute
+	defMessageDedupMaxSize = 100000
+)
+
+type messageDedupKey struct {
+	customer bool
+	senderID uint64
+	talkID   string
+	seqID    uint64
+}
+
+type messageDedupEntry struct {
+	key      messageDedupKey
+	message  *defs.TalkMessageW // nil while the first submission is still being stored
+	expireAt time.Time
+}
+
+// messageDedup remembers recently stored messages by the client SeqId, so a retried
+// submission is confirmed again instead of being stored twice. It's the fast path only, the model
+// stores a message once per talk, sender and SeqId across the connections and the instances.
+type messageDedup struct {
+	lock sync.Mutex
+
+	window  time.Duration
+	maxSize int
+	entries map[messageDedupKey]*list.Element
+	order   *list.List // oldest first
+}
+
+func newMessageDedup(window time.Duration, maxSize int) *messageDedup {
+	if window <= 0 {
+		window = defMessageDedupWindow
+	}
+
+	if maxSize <= 0 {
+		maxSize = defMessageDedupMaxSize
+	}
+
+	return &messageDedup{
+		window:  window,
+		maxSize: maxSize,
+		entries: make(map[messageDedupKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// reserve returns dup true if key was seen in the window, message is nil if the first submission is still in flight.
+// Otherwise, the key is reserved and must be finished by done or release.
+func (d *messageDedup) reserve(key messageDedupKey) (message *defs.TalkMessageW, dup bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := time.Now()
+
+	d.evict(now)
+
+	if element, ok := d.entries[key]; ok {
+		message, dup = element.Value.(*messageDedupEntry).message, true
+
+		return
+	}
+
+	d.entries[key] = d.order.PushBack(&messageDedupEntry{
+		key:      key,
+		expireAt: now.Add(d.window),
+	})
+
+	return
+}
+
+func (d *messageDedup) done(key messageDedupKey, message *defs.TalkMessageW) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if element, ok := d.entries[key]; ok {
+		element.Value.(*messageDedupEntry).message = message
+	}
+}
+
+func (d *messageDedup) release(key messageDedupKey) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if element, ok := d.entries[key]; ok {
+		d.order.Remove(element)
+		delete(d.entries, key)
+	}
+}
+
+func (d *messageDedup) evict(now time.Time) {
+	for element := d.order.Front(); element != nil; element = d.order.Front() {
+		entry := element.Value.(*messageDedupEntry)
+
+		if len(d.entries) < d.maxSize && now.Before(entry.expireAt) {
+			break
+		}
+
+		d.order.Remove(element)
+		delete(d.entries, entry.key)
+	}
+}
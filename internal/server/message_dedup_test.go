@@ -0,0 +1,55 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageDedup(t *testing.T) {
+	d := newMessageDedup(time.Hour, 2)
+
+	key1 := messageDedupKey{customer: true, senderID: 1, talkID: "t", seqID: 1}
+	key2 := messageDedupKey{customer: true, senderID: 1, talkID: "t", seqID: 2}
+	key3 := messageDedupKey{customer: true, senderID: 1, talkID: "t", seqID: 3}
+
+	_, dup := d.reserve(key1)
+	assert.False(t, dup)
+
+	message, dup := d.reserve(key1)
+	assert.True(t, dup)
+	assert.Nil(t, message)
+
+	d.done(key1, &defs.TalkMessageW{At: 100})
+
+	message, dup = d.reserve(key1)
+	assert.True(t, dup)
+	assert.EqualValues(t, 100, message.At)
+
+	_, dup = d.reserve(key2)
+	assert.False(t, dup)
+
+	d.release(key2)
+
+	_, dup = d.reserve(key2)
+	assert.False(t, dup)
+
+	// over maxSize, the oldest is forgotten
+	_, dup = d.reserve(key3)
+	assert.False(t, dup)
+
+	_, dup = d.reserve(key1)
+	assert.False(t, dup)
+
+	d = newMessageDedup(time.Millisecond, 10)
+
+	_, dup = d.reserve(key1)
+	assert.False(t, dup)
+
+	time.Sleep(time.Millisecond * 5)
+
+	_, dup = d.reserve(key1)
+	assert.False(t, dup)
+}
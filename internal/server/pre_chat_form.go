@@ -0,0 +1,151 @@
+package server
+
+import (
+	"net/mail"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sgostarter/i/l"
+)
+
+const (
+	defMaxPreChatFieldLength = 256
+	defMaxPreChatKeyLength   = 64
+
+	defOrderNumberPattern = `^[A-Za-z0-9-]{4,64}$`
+)
+
+type preChatField struct {
+	config.PreChatFieldConfig
+
+	pattern *regexp.Regexp
+	choices map[string]bool
+}
+
+// preChatForm validates the pre-chat forms against the configured fields.
+type preChatForm struct {
+	fields         []*preChatField
+	fieldsByKey    map[string]*preChatField
+	maxExtraFields int
+}
+
+// newPreChatForm compiles the patterns once, the fields with a bad pattern are logged and checked without it.
+func newPreChatForm(cfg *config.PreChatConfig, logger l.Wrapper) *preChatForm {
+	form := &preChatForm{
+		fieldsByKey: make(map[string]*preChatField),
+	}
+
+	if cfg == nil {
+		return form
+	}
+
+	form.maxExtraFields = cfg.MaxExtraFields
+
+	for _, fieldCfg := range cfg.Fields {
+		if fieldCfg.Key == "" {
+			continue
+		}
+
+		field := &preChatField{
+			PreChatFieldConfig: fieldCfg,
+		}
+
+		if field.MaxLen <= 0 {
+			field.MaxLen = defMaxPreChatFieldLength
+		}
+
+		pattern := field.Pattern
+		if pattern == "" && field.Type == config.PreChatFieldTypeOrderNumber {
+			pattern = defOrderNumberPattern
+		}
+
+		if pattern != "" {
+			var err error
+
+			field.pattern, err = regexp.Compile(pattern)
+			if err != nil {
+				logger.WithFields(l.StringField("key", field.Key), l.ErrorField(err)).Error("InvalidPreChatFieldPattern")
+			}
+		}
+
+		if field.Type == config.PreChatFieldTypeChoice {
+			field.choices = make(map[string]bool, len(field.Choices))
+			for _, choice := range field.Choices {
+				field.choices[choice] = true
+			}
+		}
+
+		form.fields = append(form.fields, field)
+		form.fieldsByKey[field.Key] = field
+	}
+
+	return form
+}
+
+// Validate returns the key of the first field failing the check, the empty fields are dropped from values.
+func (form *preChatForm) Validate(values map[string]string) (invalidKey string, ok bool) {
+	for key, value := range values {
+		if value == "" {
+			delete(values, key)
+		}
+	}
+
+	for _, field := range form.fields {
+		value, exists := values[field.Key]
+		if !exists {
+			if field.Required {
+				return field.Key, false
+			}
+
+			continue
+		}
+
+		if !field.valid(value) {
+			return field.Key, false
+		}
+	}
+
+	var extraFields int
+
+	for key, value := range values {
+		if _, exists := form.fieldsByKey[key]; exists {
+			continue
+		}
+
+		extraFields++
+
+		if extraFields > form.maxExtraFields || key == "" || len(key) > defMaxPreChatKeyLength ||
+			len(value) > defMaxPreChatFieldLength || !utf8.ValidString(key) || !utf8.ValidString(value) {
+			return key, false
+		}
+	}
+
+	ok = true
+
+	return
+}
+
+func (field *preChatField) valid(value string) bool {
+	if utf8.RuneCountInString(value) > field.MaxLen || !utf8.ValidString(value) {
+		return false
+	}
+
+	if field.pattern != nil && !field.pattern.MatchString(value) {
+		return false
+	}
+
+	switch field.Type {
+	case config.PreChatFieldTypeEmail:
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value {
+			return false
+		}
+	case config.PreChatFieldTypeChoice:
+		if !field.choices[value] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sgostarter/i/l"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreChatForm(t *testing.T) {
+	form := newPreChatForm(&config.PreChatConfig{
+		Fields: []config.PreChatFieldConfig{
+			{Key: "email", Type: config.PreChatFieldTypeEmail, Required: true},
+			{Key: "orderNumber", Type: config.PreChatFieldTypeOrderNumber},
+			{Key: "category", Type: config.PreChatFieldTypeChoice, Choices: []string{"退款", "物流"}},
+			{Key: "phone", Pattern: `^\d{11}$`},
+			{Key: "memo", MaxLen: 4},
+		},
+		MaxExtraFields: 1,
+	}, l.NewNopLoggerWrapper())
+
+	check := func(values map[string]string) string {
+		invalidKey, ok := form.Validate(values)
+		assert.Equal(t, ok, invalidKey == "")
+
+		return invalidKey
+	}
+
+	assert.EqualValues(t, "", check(map[string]string{"email": "a@b.com", "orderNumber": "A-1024", "category": "退款",
+		"phone": "13800000000", "memo": "加急处理", "source": "app"}))
+	assert.EqualValues(t, "email", check(nil))
+	assert.EqualValues(t, "email", check(map[string]string{"email": ""}))
+	assert.EqualValues(t, "email", check(map[string]string{"email": "张三 <a@b.com>"}))
+	assert.EqualValues(t, "orderNumber", check(map[string]string{"email": "a@b.com", "orderNumber": "#1"}))
+	assert.EqualValues(t, "category", check(map[string]string{"email": "a@b.com", "category": "其他"}))
+	assert.EqualValues(t, "phone", check(map[string]string{"email": "a@b.com", "phone": "138"}))
+	assert.EqualValues(t, "memo", check(map[string]string{"email": "a@b.com", "memo": "加急处理!"}))
+	assert.EqualValues(t, "source", check(map[string]string{"email": "a@b.com",
+		"source": strings.Repeat("x", defMaxPreChatFieldLength+1)}))
+
+	invalidKey, ok := form.Validate(map[string]string{"email": "a@b.com", "a": "1", "b": "2"})
+	assert.False(t, ok)
+	assert.Contains(t, []string{"a", "b"}, invalidKey)
+
+	values := map[string]string{"a": ""}
+	_, ok = newPreChatForm(nil, l.NewNopLoggerWrapper()).Validate(values)
+	assert.True(t, ok)
+	assert.Empty(t, values)
+
+	_, ok = newPreChatForm(nil, l.NewNopLoggerWrapper()).Validate(map[string]string{"a": "1"})
+	assert.False(t, ok)
+}
@@ -1,10 +1,36 @@
 package server
 
 import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/vo"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	mdKeyLastMessageID = "last-message-id"
+	mdKeyLastMessageAt = "last-message-at"
+	mdKeyForceTakeOver = "force-take-over"
+	mdKeyTalkSkill     = "talk-skill"
+
+	// the client context of a created talk, passed by the ws gateway
+	mdKeyClientPageURL   = "client-page-url"
+	mdKeyClientUserAgent = "client-user-agent"
+	mdKeyClientLocale    = "client-locale"
+	mdKeyClientReferrer  = "client-referrer"
+
+	defMaxMessageTextLength   = 4096
+	defMaxMessageImageSize    = 4 << 20
+	defMaxDispositionLength   = 64
+	defMaxShortcutLength      = 32
+	defMaxClientContextLength = 1024
+)
+
 func gRpcError(c codes.Code, err error) error {
 	var errMsg string
 	if err != nil {
@@ -17,3 +43,110 @@ func gRpcError(c codes.Code, err error) error {
 func gRpcMessageError(c codes.Code, msg string) error {
 	return status.Error(c, msg)
 }
+
+// resumePointFromGRPCContext returns the last message the client already has, nil means a full history is needed.
+func resumePointFromGRPCContext(ctx context.Context) *defs.ResumePoint {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	resumePoint := &defs.ResumePoint{}
+
+	if vs := md.Get(mdKeyLastMessageID); len(vs) > 0 {
+		resumePoint.MessageID = vs[0]
+	}
+
+	if vs := md.Get(mdKeyLastMessageAt); len(vs) > 0 {
+		resumePoint.At, _ = strconv.ParseInt(vs[0], 10, 64)
+	}
+
+	if resumePoint.MessageID == "" && resumePoint.At <= 0 {
+		return nil
+	}
+
+	return resumePoint
+}
+
+func forceTakeOverFromGRPCContext(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+
+	vs := md.Get(mdKeyForceTakeOver)
+	if len(vs) == 0 {
+		return false
+	}
+
+	force, _ := strconv.ParseBool(vs[0])
+
+	return force
+}
+
+// talkSkillFromGRPCContext returns the servicer skill a created talk asks for.
+func talkSkillFromGRPCContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if vs := md.Get(mdKeyTalkSkill); len(vs) > 0 {
+		return vs[0]
+	}
+
+	return ""
+}
+
+// talkClientContextFromGRPCContext returns where the customer creates the talk from, the overlong values are truncated.
+func talkClientContextFromGRPCContext(ctx context.Context) (clientContext defs.TalkClientContext) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return
+	}
+
+	get := func(key string) string {
+		vs := md.Get(key)
+		if len(vs) == 0 {
+			return ""
+		}
+
+		if len(vs[0]) > defMaxClientContextLength {
+			return strings.ToValidUTF8(vs[0][:defMaxClientContextLength], "")
+		}
+
+		return vs[0]
+	}
+
+	clientContext.PageURL = get(mdKeyClientPageURL)
+	clientContext.UserAgent = get(mdKeyClientUserAgent)
+	clientContext.Locale = get(mdKeyClientLocale)
+	clientContext.Referrer = get(mdKeyClientReferrer)
+
+	return
+}
+
+func messageTooLarge(message *defs.TalkMessageW) bool {
+	return len(message.Text) > defMaxMessageTextLength || len(message.Data) > defMaxMessageImageSize
+}
+
+func validCloseRequest(closeRequest *vo.ServiceCloseRequest) bool {
+	return closeRequest.Disposition != "" && len(closeRequest.Disposition) <= defMaxDispositionLength &&
+		len(closeRequest.Note) <= defMaxMessageTextLength
+}
+
+// validTransferRequest requires exactly one of the target servicer and the target team.
+func validTransferRequest(transferRequest *vo.ServiceTransferRequest) bool {
+	return transferRequest.TalkID != "" && (transferRequest.TargetServicerID == 0) != (transferRequest.TargetTeam == "") &&
+		len(transferRequest.Reason) <= defMaxMessageTextLength
+}
+
+func validCannedResponse(response *defs.CannedResponse) bool {
+	return response != nil && response.Shortcut != "" && len(response.Shortcut) <= defMaxShortcutLength &&
+		response.Text != "" && len(response.Text) <= defMaxMessageTextLength && len(response.Title) <= defMaxMessageTextLength
+}
+
+func validTalkRateRequest(rateRequest *vo.TalkRateRequest) bool {
+	return rateRequest.Score >= defs.TalkRatingScoreMin && rateRequest.Score <= defs.TalkRatingScoreMax &&
+		len(rateRequest.Comment) <= defMaxMessageTextLength
+}
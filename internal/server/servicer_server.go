@@ -1,6 +1,8 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -8,26 +10,33 @@ import (
 	"github.com/sbasestarter/customer-service-be/config"
 	"github.com/sbasestarter/customer-service-be/internal/controller"
 	"github.com/sbasestarter/customer-service-be/internal/defs"
-	"github.com/sbasestarter/customer-service-be/internal/impls"
-	"github.com/sbasestarter/customer-service-be/internal/model"
 	"github.com/sbasestarter/customer-service-be/internal/vo"
 	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
 	"github.com/sgostarter/i/l"
+	"github.com/sgostarter/libeasygo/commerr"
 	"google.golang.org/grpc/codes"
 )
 
-func NewServicerServer(controller *controller.ServicerController, userTokenHelper defs.UserTokenHelper, logger l.Wrapper) customertalkpb.ServiceTalkServiceServer {
+// NewServicerServer classifyCfg is how the servicers classify the talks, nil for free-form tags and categories only.
+func NewServicerServer(controller *controller.ServicerController, m defs.ModelEx, userTokenHelper defs.UserTokenHelper,
+	supervisorIDs []uint64, classifyCfg *config.TalkClassifyConfig, logger l.Wrapper) customertalkpb.ServiceTalkServiceServer {
 	if logger == nil {
 		logger = l.NewNopLoggerWrapper()
 	}
 
-	m := impls.NewModelEx(model.NewMongoModel(&config.GetConfig().MongoConfig, logger))
+	supervisors := make(map[uint64]bool, len(supervisorIDs))
+	for _, id := range supervisorIDs {
+		supervisors[id] = true
+	}
 
 	return &servicerServerImpl{
 		logger:          logger,
 		controller:      controller,
 		userTokenHelper: userTokenHelper,
 		model:           m,
+		dedup:           newMessageDedup(defMessageDedupWindow, defMessageDedupMaxSize),
+		supervisors:     supervisors,
+		classifier:      newTalkClassifier(classifyCfg),
 	}
 }
 
@@ -37,6 +46,9 @@ type servicerServerImpl struct {
 	logger          l.Wrapper
 	userTokenHelper defs.UserTokenHelper
 	model           defs.ModelEx
+	dedup           *messageDedup
+	supervisors     map[uint64]bool
+	classifier      *talkClassifier
 
 	controller *controller.ServicerController
 }
@@ -67,7 +79,7 @@ func (impl *servicerServerImpl) Service(server customertalkpb.ServiceTalkService
 
 	chSendMessage := make(chan *customertalkpb.ServiceResponse, 100)
 
-	servicer := controller.NewServicer(userID, uniqueID, chSendMessage)
+	servicer := controller.NewServicer(userID, uniqueID, impl.supervisors[userID], chSendMessage)
 
 	err = impl.controller.InstallServicer(servicer)
 	if err != nil {
@@ -78,7 +90,10 @@ func (impl *servicerServerImpl) Service(server customertalkpb.ServiceTalkService
 
 	chTerminal := make(chan error, 2)
 
-	go impl.serverReceiveRoutine(server, servicer, userID, userName, chTerminal, logger)
+	// a supervisor console opts in to take over attached talks for the whole stream
+	forceTakeOver := servicer.IsSupervisor() && forceTakeOverFromGRPCContext(server.Context())
+
+	go impl.serverReceiveRoutine(server, servicer, userID, userName, forceTakeOver, chTerminal, logger)
 
 	loop := true
 
@@ -124,7 +139,7 @@ func (impl *servicerServerImpl) Service(server customertalkpb.ServiceTalkService
 //
 
 func (impl *servicerServerImpl) serverReceiveRoutine(server customertalkpb.ServiceTalkService_ServiceServer,
-	servicer defs.Servicer, userID uint64, userName string, chTerminal chan<- error, logger l.Wrapper) {
+	servicer defs.Servicer, userID uint64, userName string, forceTakeOver bool, chTerminal chan<- error, logger l.Wrapper) {
 	var err error
 
 	var request *customertalkpb.ServiceRequest
@@ -154,30 +169,76 @@ func (impl *servicerServerImpl) serverReceiveRoutine(server customertalkpb.Servi
 				continue
 			}
 		} else if reload := request.GetReload(); reload != nil {
-			err = impl.controller.ServicerReloadTalk(servicer, reload.GetTalkId())
+			err = impl.controller.ServicerReloadTalk(servicer, reload.GetTalkId(), vo.ServiceReloadResumePoint(reload))
 			if err != nil {
 				logger.WithFields(l.ErrorField(err), l.StringField("talkID", reload.GetTalkId())).
 					Error("ServicerReloadTalk")
 
+				continue
+			}
+		} else if queryRequest := vo.ServiceQueryMessagesRequestFromUnknown(request); queryRequest != nil {
+			err = impl.controller.ServicerQueryMessages(servicer, queryRequest.TalkID, queryRequest.MessageID,
+				queryRequest.Before, queryRequest.Count)
+			if err != nil {
+				logger.WithFields(l.ErrorField(err), l.StringField("talkID", queryRequest.TalkID)).
+					Error("ServicerQueryMessagesFailed")
+
 				continue
 			}
 		} else if message := request.GetMessage(); message != nil {
-			dbMessage := vo.TalkMessageWPb2Db(message.GetMessage())
-			dbMessage.At = time.Now().Unix()
+			var seqID uint64
+			if message.GetMessage() != nil {
+				seqID = message.GetMessage().GetSeqId()
+			}
+
+			dedupKey := messageDedupKey{
+				senderID: userID,
+				talkID:   message.GetTalkId(),
+				seqID:    seqID,
+			}
+
+			if seqID > 0 {
+				if storedMessage, dup := impl.dedup.reserve(dedupKey); dup {
+					impl.confirmDuplicatedMessage(servicer, seqID, storedMessage, logger)
+
+					continue
+				}
+			}
+
+			dbMessage := vo.TalkMessageWPb2Db4Servicer(message.GetMessage())
+			if dbMessage == nil {
+				impl.dedup.release(dedupKey)
+
+				logger.Error("noMessage")
+
+				continue
+			}
+
+			dbMessage.At = time.Now().UnixMilli()
 			dbMessage.CustomerMessage = false
 			dbMessage.SenderID = userID
 			dbMessage.SenderUserName = userName
+			dbMessage.ClientSeqID = seqID
 
-			err = impl.model.AddTalkMessage(server.Context(), message.GetTalkId(), dbMessage)
-			if err != nil {
-				logger.WithFields(l.ErrorField(err)).Error("AddTalkMessageFailed")
+			dup, reason := impl.storeMessage(server.Context(), servicer, message.GetTalkId(), dbMessage, logger)
+			if reason != "" {
+				impl.dedup.release(dedupKey)
+
+				if err = servicer.SendMessage(vo.MessageFailed4Servicer(seqID, reason)); err != nil {
+					logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+					break
+				}
 
-				break
+				continue
 			}
 
-			var seqID uint64
-			if message.GetMessage() != nil {
-				seqID = message.GetMessage().GetSeqId()
+			impl.dedup.done(dedupKey, dbMessage)
+
+			if dup {
+				impl.confirmDuplicatedMessage(servicer, seqID, dbMessage, logger)
+
+				continue
 			}
 
 			err = impl.controller.ServicerMessageIncoming(servicer, seqID, message.GetTalkId(), dbMessage)
@@ -187,7 +248,7 @@ func (impl *servicerServerImpl) serverReceiveRoutine(server customertalkpb.Servi
 				continue
 			}
 		} else if attach := request.GetAttach(); attach != nil {
-			err = impl.controller.ServicerAttachTalk(servicer, attach.GetTalkId())
+			err = impl.controller.ServicerAttachTalk(servicer, attach.GetTalkId(), forceTakeOver)
 
 			if err != nil {
 				logger.WithFields(l.ErrorField(err)).Error("AttachTalkFailed")
@@ -200,6 +261,168 @@ func (impl *servicerServerImpl) serverReceiveRoutine(server customertalkpb.Servi
 			if err != nil {
 				logger.WithFields(l.ErrorField(err)).Error("DetachTalkFailed")
 
+				continue
+			}
+		} else if closeRequest := vo.ServiceCloseRequestFromUnknown(request); closeRequest != nil {
+			if !validCloseRequest(closeRequest) {
+				if err = servicer.SendMessage(&customertalkpb.ServiceResponse{
+					Response: &customertalkpb.ServiceResponse_Notify{
+						Notify: &customertalkpb.ServiceTalkNotifyResponse{
+							Msg: "invalidDisposition",
+						},
+					},
+				}); err != nil {
+					logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+					break
+				}
+
+				continue
+			}
+
+			err = impl.controller.ServicerCloseTalk(servicer, closeRequest.TalkID, closeRequest.Disposition,
+				closeRequest.Note)
+			if err != nil {
+				logger.WithFields(l.ErrorField(err)).Error("CloseTalkFailed")
+
+				continue
+			}
+		} else if transferRequest := vo.ServiceTransferRequestFromUnknown(request); transferRequest != nil {
+			if !validTransferRequest(transferRequest) {
+				if err = servicer.SendMessage(&customertalkpb.ServiceResponse{
+					Response: &customertalkpb.ServiceResponse_Notify{
+						Notify: &customertalkpb.ServiceTalkNotifyResponse{
+							Msg: "invalidTransfer",
+						},
+					},
+				}); err != nil {
+					logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+					break
+				}
+
+				continue
+			}
+
+			err = impl.controller.ServicerTransferTalk(servicer, transferRequest.TalkID,
+				transferRequest.TargetServicerID, transferRequest.TargetTeam, transferRequest.Reason)
+			if err != nil {
+				logger.WithFields(l.ErrorField(err)).Error("TransferTalkFailed")
+
+				continue
+			}
+		} else if replyRequest := vo.ServiceTransferReplyRequestFromUnknown(request); replyRequest != nil {
+			err = impl.controller.ServicerReplyTalkTransfer(servicer, replyRequest.TalkID, replyRequest.Accept)
+			if err != nil {
+				logger.WithFields(l.ErrorField(err)).Error("ReplyTalkTransferFailed")
+
+				continue
+			}
+		} else if watchRequest := vo.ServiceWatchTalkRequestFromUnknown(request); watchRequest != nil {
+			if watchRequest.TalkID == "" || !watchRequest.Mode.Valid() {
+				if err = servicer.SendMessage(&customertalkpb.ServiceResponse{
+					Response: &customertalkpb.ServiceResponse_Notify{
+						Notify: &customertalkpb.ServiceTalkNotifyResponse{
+							Msg: "invalidWatchMode",
+						},
+					},
+				}); err != nil {
+					logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+					break
+				}
+
+				continue
+			}
+
+			err = impl.controller.ServicerWatchTalk(servicer, watchRequest.TalkID, watchRequest.Mode)
+			if err != nil {
+				logger.WithFields(l.ErrorField(err)).Error("WatchTalkFailed")
+
+				continue
+			}
+		} else if whisperRequest := vo.ServiceWhisperRequestFromUnknown(request); whisperRequest != nil {
+			if whisperRequest.TalkID == "" || whisperRequest.Text == "" || len(whisperRequest.Text) > defMaxMessageTextLength {
+				if err = servicer.SendMessage(&customertalkpb.ServiceResponse{
+					Response: &customertalkpb.ServiceResponse_Notify{
+						Notify: &customertalkpb.ServiceTalkNotifyResponse{
+							Msg: "invalidWhisper",
+						},
+					},
+				}); err != nil {
+					logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+					break
+				}
+
+				continue
+			}
+
+			// whispers are not kept in the talk history
+			err = impl.controller.ServicerWhisper(servicer, whisperRequest.TalkID, &defs.TalkMessageW{
+				At:             time.Now().UnixMilli(),
+				Type:           defs.TalkMessageTypeText,
+				SenderID:       userID,
+				SenderUserName: userName,
+				Text:           whisperRequest.Text,
+			})
+			if err != nil {
+				logger.WithFields(l.ErrorField(err)).Error("WhisperFailed")
+
+				continue
+			}
+		} else if classification := vo.ServiceClassifyTalkRequestFromUnknown(request); classification != nil {
+			invalidKey, valid := impl.classifier.Validate(classification)
+			if classification.TalkID == "" || classification.Empty() || !valid {
+				if err = servicer.SendMessage(&customertalkpb.ServiceResponse{
+					Response: &customertalkpb.ServiceResponse_Notify{
+						Notify: &customertalkpb.ServiceTalkNotifyResponse{
+							Msg: "invalidTalkClassification:" + invalidKey,
+						},
+					},
+				}); err != nil {
+					logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+					break
+				}
+
+				continue
+			}
+
+			err = impl.controller.ServicerClassifyTalk(servicer, classification)
+			if err != nil {
+				logger.WithFields(l.ErrorField(err)).Error("ClassifyTalkFailed")
+
+				continue
+			}
+		} else if presence, ok := vo.ServiceSetPresenceRequestFromUnknown(request); ok {
+			if !presence.Valid() {
+				if err = servicer.SendMessage(&customertalkpb.ServiceResponse{
+					Response: &customertalkpb.ServiceResponse_Notify{
+						Notify: &customertalkpb.ServiceTalkNotifyResponse{
+							Msg: "invalidPresence",
+						},
+					},
+				}); err != nil {
+					logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+
+					break
+				}
+
+				continue
+			}
+
+			err = impl.controller.ServicerSetPresence(servicer, presence)
+			if err != nil {
+				logger.WithFields(l.ErrorField(err)).Error("SetPresenceFailed")
+
+				continue
+			}
+		} else if vo.IsServiceQueryPresencesRequest(request) {
+			err = impl.controller.ServicerQueryPresences(servicer)
+			if err != nil {
+				logger.WithFields(l.ErrorField(err)).Error("QueryPresencesFailed")
+
 				continue
 			}
 		} else {
@@ -207,3 +430,79 @@ func (impl *servicerServerImpl) serverReceiveRoutine(server customertalkpb.Servi
 		}
 	}
 }
+
+// storeMessage returns the reason if the message is rejected.
+// storeMessage dup is true if the message was stored before, by an earlier connection or on another instance,
+// dbMessage is the stored one then.
+func (impl *servicerServerImpl) storeMessage(ctx context.Context, servicer defs.Servicer, talkID string, dbMessage *defs.TalkMessageW,
+	logger l.Wrapper) (dup bool, reason defs.MessageFailedReason) {
+	if messageTooLarge(dbMessage) {
+		reason = defs.MessageFailedReasonTooLarge
+
+		return
+	}
+
+	talkInfo, err := impl.model.GetTalkInfo(ctx, talkID)
+	if err != nil {
+		if errors.Is(err, commerr.ErrNotFound) || errors.Is(err, commerr.ErrInvalidArgument) {
+			reason = defs.MessageFailedReasonNotAttached
+
+			return
+		}
+
+		logger.WithFields(l.ErrorField(err)).Error("GetTalkInfoFailed")
+
+		reason = defs.MessageFailedReasonStorage
+
+		return
+	}
+
+	// supervisors barging in talk along with the attached servicer
+	if talkInfo.ServiceID != servicer.GetUserID() && servicer.GetTalkWatchMode(talkID) != defs.TalkWatchModeBarge {
+		reason = defs.MessageFailedReasonNotAttached
+
+		return
+	}
+
+	if !talkInfo.Status.Opened() {
+		reason = defs.MessageFailedReasonTalkClosed
+
+		return
+	}
+
+	if err = impl.model.AddTalkMessage(ctx, talkID, dbMessage); err != nil {
+		if errors.Is(err, commerr.ErrAlreadyExists) {
+			dup = true
+
+			return
+		}
+
+		logger.WithFields(l.ErrorField(err)).Error("AddTalkMessageFailed")
+
+		reason = defs.MessageFailedReasonStorage
+
+		return
+	}
+
+	// notes are no response to the customer
+	if dbMessage.VisibleToCustomer() && talkInfo.Status != defs.TalkStatusWaitingOnCustomer {
+		if err = impl.model.UpdateTalkStatus(ctx, talkID, defs.TalkStatusWaitingOnCustomer); err != nil {
+			logger.WithFields(l.ErrorField(err)).Warn("UpdateTalkStatusFailed")
+		}
+	}
+
+	return
+}
+
+func (impl *servicerServerImpl) confirmDuplicatedMessage(servicer defs.Servicer, seqID uint64,
+	storedMessage *defs.TalkMessageW, logger l.Wrapper) {
+	if storedMessage == nil {
+		logger.WithFields(l.UInt64Field("seqID", seqID)).Warn("DuplicatedMessageInFlight")
+
+		return
+	}
+
+	if err := servicer.SendMessage(vo.MessageConfirmed4Servicer(seqID, storedMessage)); err != nil {
+		logger.WithFields(l.ErrorField(err)).Error("SendMessageFailed")
+	}
+}
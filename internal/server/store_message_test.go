@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/impls"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/sgostarter/i/l"
+	"github.com/stretchr/testify/assert"
+)
+
+type utStorageFailedModel struct {
+	defs.ModelEx
+}
+
+func (m *utStorageFailedModel) AddTalkMessage(context.Context, string, *defs.TalkMessageW) error {
+	return errors.New("storageDown")
+}
+
+type utStoreCustomer struct {
+	defs.Customer
+
+	talkID string
+}
+
+func (customer *utStoreCustomer) GetTalkID() string {
+	return customer.talkID
+}
+
+type utStoreServicer struct {
+	defs.Servicer
+
+	userID uint64
+}
+
+func (servicer *utStoreServicer) GetUserID() uint64 {
+	return servicer.userID
+}
+
+func (servicer *utStoreServicer) GetTalkWatchMode(string) defs.TalkWatchMode {
+	return defs.TalkWatchModeNone
+}
+
+func TestStoreMessageStorageFailed(t *testing.T) {
+	ctx := context.Background()
+	logger := l.NewNopLoggerWrapper()
+	modelEx := &utStorageFailedModel{ModelEx: impls.NewModelEx(model.NewMemoryModel())}
+
+	talkID, err := modelEx.CreateTalk(ctx, &defs.TalkInfoW{
+		Status:    defs.TalkStatusAssigned,
+		ServiceID: 9,
+	})
+	assert.Nil(t, err)
+
+	customerServer := &customerServerImpl{logger: logger, model: modelEx}
+	_, reason := customerServer.storeMessage(ctx, &utStoreCustomer{talkID: talkID},
+		&defs.TalkMessageW{Type: defs.TalkMessageTypeText, Text: "hello"}, logger)
+	assert.EqualValues(t, defs.MessageFailedReasonStorage, reason)
+	assert.True(t, reason.Retryable())
+
+	servicerServer := &servicerServerImpl{logger: logger, model: modelEx}
+	_, reason = servicerServer.storeMessage(ctx, &utStoreServicer{userID: 9}, talkID,
+		&defs.TalkMessageW{Type: defs.TalkMessageTypeText, Text: "hi"}, logger)
+	assert.EqualValues(t, defs.MessageFailedReasonStorage, reason)
+
+	// the message wasn't kept, so it's not a duplicate when sent again
+	messages, err := modelEx.GetTalkMessages(ctx, talkID, 0, 0)
+	assert.Nil(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestStoreMessageRetried(t *testing.T) {
+	ctx := context.Background()
+	logger := l.NewNopLoggerWrapper()
+	modelEx := impls.NewModelEx(model.NewMemoryModel())
+
+	talkID, err := modelEx.CreateTalk(ctx, &defs.TalkInfoW{
+		Status:    defs.TalkStatusAssigned,
+		ServiceID: 9,
+	})
+	assert.Nil(t, err)
+
+	// the retry reaches another instance, whose fast path hasn't seen the SeqId
+	customerServer := &customerServerImpl{logger: logger, model: modelEx}
+
+	for idx := 0; idx < 2; idx++ {
+		dbMessage := &defs.TalkMessageW{At: int64(idx + 1), CustomerMessage: true, SenderID: 1,
+			Type: defs.TalkMessageTypeText, Text: "hello", ClientSeqID: 3}
+
+		dup, reason := customerServer.storeMessage(ctx, &utStoreCustomer{talkID: talkID}, dbMessage, logger)
+		assert.EqualValues(t, "", reason)
+		assert.EqualValues(t, idx > 0, dup)
+		assert.EqualValues(t, 1, dbMessage.Seq)
+		assert.EqualValues(t, 1, dbMessage.At)
+	}
+
+	servicerServer := &servicerServerImpl{logger: logger, model: modelEx}
+
+	for idx := 0; idx < 2; idx++ {
+		dbMessage := &defs.TalkMessageW{SenderID: 9, Type: defs.TalkMessageTypeText, Text: "hi", ClientSeqID: 3}
+
+		dup, reason := servicerServer.storeMessage(ctx, &utStoreServicer{userID: 9}, talkID, dbMessage, logger)
+		assert.EqualValues(t, "", reason)
+		assert.EqualValues(t, idx > 0, dup)
+		assert.EqualValues(t, 2, dbMessage.Seq)
+	}
+
+	messages, err := modelEx.GetTalkMessages(ctx, talkID, 0, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(messages))
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+)
+
+const (
+	defMaxTagLength         = 32
+	defMaxCategoryLength    = 64
+	defMaxCustomFieldLength = 256
+
+	customFieldDateLayout = "2006-01-02"
+)
+
+// talkClassifier validates the classifications against the configured categories and custom fields.
+type talkClassifier struct {
+	categories   map[string]bool
+	customFields map[string]*config.TalkCustomFieldConfig
+	choices      map[string]map[string]bool
+}
+
+func newTalkClassifier(cfg *config.TalkClassifyConfig) *talkClassifier {
+	classifier := &talkClassifier{
+		categories:   make(map[string]bool),
+		customFields: make(map[string]*config.TalkCustomFieldConfig),
+		choices:      make(map[string]map[string]bool),
+	}
+
+	if cfg == nil {
+		return classifier
+	}
+
+	for _, category := range cfg.Categories {
+		classifier.categories[category] = true
+	}
+
+	for idx := range cfg.CustomFields {
+		fieldCfg := &cfg.CustomFields[idx]
+		if fieldCfg.Key == "" {
+			continue
+		}
+
+		classifier.customFields[fieldCfg.Key] = fieldCfg
+
+		if fieldCfg.Type == config.TalkFieldTypeChoice {
+			choices := make(map[string]bool, len(fieldCfg.Choices))
+			for _, choice := range fieldCfg.Choices {
+				choices[choice] = true
+			}
+
+			classifier.choices[fieldCfg.Key] = choices
+		}
+	}
+
+	return classifier
+}
+
+// Validate trims the tags and turns the custom field values into the canonical form of their types,
+// invalidKey is the tag, category or field key failing the check.
+func (classifier *talkClassifier) Validate(classification *defs.TalkClassification) (invalidKey string, ok bool) {
+	if len(classification.AddTags) > defs.TalkMaxTags || len(classification.RemoveTags) > defs.TalkMaxTags {
+		return "tags", false
+	}
+
+	for _, tags := range [][]string{classification.AddTags, classification.RemoveTags} {
+		for idx, tag := range tags {
+			tags[idx] = strings.TrimSpace(tag)
+
+			if tags[idx] == "" || len(tags[idx]) > defMaxTagLength || !utf8.ValidString(tags[idx]) {
+				return tag, false
+			}
+		}
+	}
+
+	if category := classification.Category; category != nil && *category != "" {
+		if len(*category) > defMaxCategoryLength || !utf8.ValidString(*category) ||
+			len(classifier.categories) > 0 && !classifier.categories[*category] {
+			return *category, false
+		}
+	}
+
+	for key, value := range classification.CustomFields {
+		if value == "" {
+			if _, exists := classifier.customFields[key]; !exists {
+				return key, false
+			}
+
+			continue
+		}
+
+		canonicalValue, valid := classifier.CanonicalValue(key, value)
+		if !valid {
+			return key, false
+		}
+
+		classification.CustomFields[key] = canonicalValue
+	}
+
+	ok = true
+
+	return
+}
+
+// CanonicalValue returns value in the canonical form of the type of the custom field key, for storing and
+// filtering alike.
+func (classifier *talkClassifier) CanonicalValue(key, value string) (canonicalValue string, ok bool) {
+	fieldCfg, exists := classifier.customFields[key]
+	if !exists || len(value) > defMaxCustomFieldLength || !utf8.ValidString(value) {
+		return
+	}
+
+	switch fieldCfg.Type {
+	case config.TalkFieldTypeNumber:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return
+		}
+
+		canonicalValue = strconv.FormatFloat(f, 'f', -1, 64)
+	case config.TalkFieldTypeBool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return
+		}
+
+		canonicalValue = strconv.FormatBool(b)
+	case config.TalkFieldTypeDate:
+		t, err := time.Parse(customFieldDateLayout, strings.TrimSpace(value))
+		if err != nil {
+			return
+		}
+
+		canonicalValue = t.Format(customFieldDateLayout)
+	case config.TalkFieldTypeChoice:
+		if !classifier.choices[key][value] {
+			return
+		}
+
+		canonicalValue = value
+	default:
+		canonicalValue = value
+	}
+
+	ok = true
+
+	return
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTalkClassifier(t *testing.T) {
+	classifier := newTalkClassifier(&config.TalkClassifyConfig{
+		Categories: []string{"售后", "物流"},
+		CustomFields: []config.TalkCustomFieldConfig{
+			{Key: "amount", Type: config.TalkFieldTypeNumber},
+			{Key: "urgent", Type: config.TalkFieldTypeBool},
+			{Key: "due", Type: config.TalkFieldTypeDate},
+			{Key: "region", Type: config.TalkFieldTypeChoice, Choices: []string{"华东", "华北"}},
+			{Key: "memo"},
+		},
+	})
+
+	check := func(classification *defs.TalkClassification) string {
+		invalidKey, ok := classifier.Validate(classification)
+		assert.Equal(t, ok, invalidKey == "")
+
+		return invalidKey
+	}
+
+	category := "售后"
+	classification := &defs.TalkClassification{
+		TalkID:   "talk1",
+		AddTags:  []string{" vip "},
+		Category: &category,
+		CustomFields: map[string]string{"amount": " 012.50", "urgent": "1", "due": "2026-10-01",
+			"region": "华东", "memo": "x", "old": ""},
+	}
+	assert.EqualValues(t, "old", check(classification))
+
+	delete(classification.CustomFields, "old")
+	classification.CustomFields["memo"] = ""
+	assert.EqualValues(t, "", check(classification))
+	assert.EqualValues(t, []string{"vip"}, classification.AddTags)
+	assert.EqualValues(t, map[string]string{"amount": "12.5", "urgent": "true", "due": "2026-10-01",
+		"region": "华东", "memo": ""}, classification.CustomFields)
+
+	other := "其他"
+	assert.EqualValues(t, "其他", check(&defs.TalkClassification{Category: &other}))
+	assert.EqualValues(t, "", check(&defs.TalkClassification{Category: new(string)}))
+	assert.EqualValues(t, " ", check(&defs.TalkClassification{AddTags: []string{" "}}))
+	assert.EqualValues(t, "tags", check(&defs.TalkClassification{RemoveTags: make([]string, defs.TalkMaxTags+1)}))
+
+	long := strings.Repeat("x", defMaxTagLength+1)
+	assert.EqualValues(t, long, check(&defs.TalkClassification{AddTags: []string{long}}))
+
+	for key, value := range map[string]string{"amount": "NaN", "urgent": "maybe", "due": "2026-13-01",
+		"region": "华南", "unknown": "1"} {
+		assert.EqualValues(t, key, check(&defs.TalkClassification{CustomFields: map[string]string{key: value}}))
+	}
+
+	_, ok := newTalkClassifier(nil).CanonicalValue("memo", "x")
+	assert.False(t, ok)
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/gens/talksearchpb"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/vo"
+	"github.com/sgostarter/i/l"
+	"github.com/sgostarter/libeasygo/commerr"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	defSearchPageSize       = 20
+	defMaxSearchPageSize    = 100
+	defMaxSearchTitleLength = 64
+)
+
+// NewTalkSearchServer classifyCfg is the one the talks are classified with, the custom fields are searched by
+// their canonical values.
+func NewTalkSearchServer(m defs.ModelEx, userTokenHelper defs.UserTokenHelper, classifyCfg *config.TalkClassifyConfig,
+	logger l.Wrapper) talksearchpb.TalkSearchServiceServer {
+	if logger == nil {
+		logger = l.NewNopLoggerWrapper()
+	}
+
+	return &talkSearchServerImpl{
+		logger:          logger.WithFields(l.StringField(l.ClsKey, "talkSearchServerImpl")),
+		model:           m,
+		userTokenHelper: userTokenHelper,
+		classifier:      newTalkClassifier(classifyCfg),
+	}
+}
+
+type talkSearchServerImpl struct {
+	talksearchpb.UnimplementedTalkSearchServiceServer
+
+	logger          l.Wrapper
+	model           defs.ModelEx
+	userTokenHelper defs.UserTokenHelper
+	classifier      *talkClassifier
+}
+
+func (impl *talkSearchServerImpl) SearchTalks(ctx context.Context,
+	request *talksearchpb.SearchTalksRequest) (*talksearchpb.SearchTalksResponse, error) {
+	if _, _, _, err := impl.userTokenHelper.ExtractUserFromGRPCContext(ctx, false); err != nil {
+		return nil, gRpcError(codes.Unauthenticated, err)
+	}
+
+	search, invalidArg, ok := impl.talkSearchFromRequest(request)
+	if !ok {
+		return nil, gRpcMessageError(codes.InvalidArgument, invalidArg)
+	}
+
+	pageSize := search.Limit
+
+	// the one more talk tells if there is a next page
+	search.Limit++
+
+	talks, err := impl.model.SearchTalks(ctx, search)
+	if err != nil {
+		if errors.Is(err, commerr.ErrInvalidArgument) {
+			return nil, gRpcError(codes.InvalidArgument, err)
+		}
+
+		impl.logger.WithFields(l.ErrorField(err)).Error("SearchTalksFailed")
+
+		return nil, gRpcError(codes.Internal, err)
+	}
+
+	resp := &talksearchpb.SearchTalksResponse{}
+
+	if len(talks) > pageSize {
+		talks = talks[:pageSize]
+		resp.NextCursor = vo.TalkCursorDB2Pb(talks[pageSize-1].Cursor())
+	}
+
+	resp.Talks = vo.TalkSearchResultsDB2Pb(talks)
+
+	return resp, nil
+}
+
+//
+//
+//
+
+// talkSearchFromRequest invalidArg is the request field failing the check, or the custom field key.
+func (impl *talkSearchServerImpl) talkSearchFromRequest(request *talksearchpb.SearchTalksRequest) (
+	search *defs.TalkSearch, invalidArg string, ok bool) {
+	statuses, valid := vo.TalkSearchStatusesPb2Db(request.GetStatuses())
+	if !valid {
+		invalidArg = "statuses"
+
+		return
+	}
+
+	after, valid := vo.TalkCursorPb2Db(request.GetCursor())
+	if !valid {
+		invalidArg = "cursor"
+
+		return
+	}
+
+	if request.GetStartAtFrom() > 0 && request.GetStartAtTo() > 0 && request.GetStartAtFrom() >= request.GetStartAtTo() {
+		invalidArg = "startAt"
+
+		return
+	}
+
+	filter := &defs.TalkFilter{
+		Title:       strings.TrimSpace(request.GetTitle()),
+		Category:    request.GetCategory(),
+		StartAtFrom: request.GetStartAtFrom(),
+		StartAtTo:   request.GetStartAtTo(),
+	}
+
+	if len(filter.Title) > defMaxSearchTitleLength || !utf8.ValidString(filter.Title) {
+		invalidArg = "title"
+
+		return
+	}
+
+	if len(request.GetTags()) > defs.TalkMaxTags {
+		invalidArg = "tags"
+
+		return
+	}
+
+	for _, tag := range request.GetTags() {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || len(tag) > defMaxTagLength {
+			invalidArg = "tags"
+
+			return
+		}
+
+		filter.Tags = append(filter.Tags, tag)
+	}
+
+	for _, field := range request.GetCustomFields() {
+		value, valid := impl.classifier.CanonicalValue(field.GetKey(), field.GetValue())
+		if !valid || value == "" {
+			invalidArg = field.GetKey()
+
+			return
+		}
+
+		if filter.CustomFields == nil {
+			filter.CustomFields = make(map[string]string)
+		}
+
+		filter.CustomFields[field.GetKey()] = value
+	}
+
+	pageSize := int(request.GetPageSize())
+
+	switch {
+	case pageSize == 0:
+		pageSize = defSearchPageSize
+	case pageSize > defMaxSearchPageSize:
+		pageSize = defMaxSearchPageSize
+	}
+
+	search = &defs.TalkSearch{
+		CreatorID: request.GetCreatorId(),
+		ServiceID: request.GetServicerId(),
+		Statuses:  statuses,
+		Filter:    filter,
+		Sort:      vo.TalkSortPb2Db(request.GetSort()),
+		After:     after,
+		Limit:     pageSize,
+	}
+
+	ok = true
+
+	return
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sbasestarter/customer-service-be/config"
+	"github.com/sbasestarter/customer-service-be/gens/talksearchpb"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-be/internal/impls"
+	"github.com/sbasestarter/customer-service-be/internal/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTalkSearchServer(t *testing.T) {
+	ctx := context.Background()
+	modelEx := impls.NewModelEx(model.NewMemoryModel())
+
+	s := NewTalkSearchServer(modelEx, utUserTokenHelper{}, &config.TalkClassifyConfig{
+		CustomFields: []config.TalkCustomFieldConfig{{Key: "amount", Type: config.TalkFieldTypeNumber}},
+	}, nil)
+
+	now := time.Now().Unix()
+
+	var talkIDs []string
+
+	for idx := 0; idx < 5; idx++ {
+		talkID, err := modelEx.CreateTalk(ctx, &defs.TalkInfoW{
+			Status:    defs.TalkStatusQueued,
+			Title:     "Refund " + strconv.Itoa(idx),
+			StartAt:   now + int64(idx),
+			CreatorID: uint64(1 + idx%2),
+		})
+		assert.Nil(t, err)
+
+		talkIDs = append(talkIDs, talkID)
+	}
+
+	assert.Nil(t, modelEx.CloseTalk(ctx, talkIDs[0]))
+
+	_, err := modelEx.ClassifyTalk(ctx, &defs.TalkClassification{
+		TalkID:       talkIDs[3],
+		AddTags:      []string{"vip"},
+		CustomFields: map[string]string{"amount": "12.5"},
+	})
+	assert.Nil(t, err)
+
+	servicerCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("token", "9"))
+
+	search := func(request *talksearchpb.SearchTalksRequest) (talkIDs []string, nextCursor string) {
+		resp, err := s.SearchTalks(servicerCtx, request)
+		assert.Nil(t, err)
+
+		for _, talk := range resp.GetTalks() {
+			talkIDs = append(talkIDs, talk.GetTalkId())
+		}
+
+		return talkIDs, resp.GetNextCursor()
+	}
+
+	ids, cursor := search(&talksearchpb.SearchTalksRequest{PageSize: 2})
+	assert.EqualValues(t, []string{talkIDs[4], talkIDs[3]}, ids)
+	assert.NotEmpty(t, cursor)
+
+	ids, cursor = search(&talksearchpb.SearchTalksRequest{PageSize: 2, Cursor: cursor})
+	assert.EqualValues(t, []string{talkIDs[2], talkIDs[1]}, ids)
+	assert.NotEmpty(t, cursor)
+
+	ids, cursor = search(&talksearchpb.SearchTalksRequest{PageSize: 2, Cursor: cursor})
+	assert.EqualValues(t, []string{talkIDs[0]}, ids)
+	assert.Empty(t, cursor)
+
+	ids, cursor = search(&talksearchpb.SearchTalksRequest{CreatorId: 1, Sort: talksearchpb.TalkSearchSort_TALK_SEARCH_SORT_START_AT_ASC})
+	assert.EqualValues(t, []string{talkIDs[0], talkIDs[2], talkIDs[4]}, ids)
+	assert.Empty(t, cursor)
+
+	ids, _ = search(&talksearchpb.SearchTalksRequest{
+		Statuses: []talksearchpb.TalkSearchStatus{talksearchpb.TalkSearchStatus_TALK_SEARCH_STATUS_CLOSED},
+	})
+	assert.EqualValues(t, []string{talkIDs[0]}, ids)
+
+	ids, _ = search(&talksearchpb.SearchTalksRequest{Title: " refund 3 "})
+	assert.EqualValues(t, []string{talkIDs[3]}, ids)
+
+	ids, _ = search(&talksearchpb.SearchTalksRequest{
+		Tags:         []string{"vip"},
+		CustomFields: []*talksearchpb.TalkSearchCustomField{{Key: "amount", Value: "12.50"}},
+	})
+	assert.EqualValues(t, []string{talkIDs[3]}, ids)
+
+	resp, err := s.SearchTalks(servicerCtx, &talksearchpb.SearchTalksRequest{Title: "refund 3"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(resp.GetTalks()))
+	assert.EqualValues(t, []string{"vip"}, resp.GetTalks()[0].GetTags())
+	assert.EqualValues(t, "amount", resp.GetTalks()[0].GetCustomFields()[0].GetKey())
+
+	for _, request := range []*talksearchpb.SearchTalksRequest{
+		{Cursor: "x"},
+		{Statuses: []talksearchpb.TalkSearchStatus{talksearchpb.TalkSearchStatus_TALK_SEARCH_STATUS_UNSPECIFIED}},
+		{StartAtFrom: now, StartAtTo: now},
+		{Tags: []string{" "}},
+		{CustomFields: []*talksearchpb.TalkSearchCustomField{{Key: "amount", Value: "x"}}},
+		{CustomFields: []*talksearchpb.TalkSearchCustomField{{Key: "unknown", Value: "1"}}},
+	} {
+		_, err = s.SearchTalks(servicerCtx, request)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+
+	_, err = s.SearchTalks(ctx, &talksearchpb.SearchTalksRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
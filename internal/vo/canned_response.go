@@ -0,0 +1,48 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-be/gens/cannedresponsepb"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+)
+
+func CannedResponseDB2Pb(response *defs.CannedResponse) *cannedresponsepb.CannedResponse {
+	if response == nil {
+		return nil
+	}
+
+	return &cannedresponsepb.CannedResponse{
+		Id:        response.ID,
+		OwnerId:   response.OwnerID,
+		Team:      response.Team,
+		Shortcut:  response.Shortcut,
+		Title:     response.Title,
+		Text:      response.Text,
+		UpdatedAt: response.UpdatedAt,
+	}
+}
+
+func CannedResponsesDB2Pb(responses []*defs.CannedResponse) []*cannedresponsepb.CannedResponse {
+	pbResponses := make([]*cannedresponsepb.CannedResponse, 0, len(responses))
+
+	for _, response := range responses {
+		pbResponses = append(pbResponses, CannedResponseDB2Pb(response))
+	}
+
+	return pbResponses
+}
+
+func CannedResponsePb2Db(response *cannedresponsepb.CannedResponse) *defs.CannedResponse {
+	if response == nil {
+		return nil
+	}
+
+	return &defs.CannedResponse{
+		ID:        response.GetId(),
+		OwnerID:   response.GetOwnerId(),
+		Team:      response.GetTeam(),
+		Shortcut:  response.GetShortcut(),
+		Title:     response.GetTitle(),
+		Text:      response.GetText(),
+		UpdatedAt: response.GetUpdatedAt(),
+	}
+}
@@ -0,0 +1,60 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the TalkResponse and ServiceResponse oneof fields the rejected messages are reported in,
+// they're carried as unknown fields until a proto release has them.
+const (
+	talkResponseMessageFailedField    protowire.Number = 10
+	serviceResponseMessageFailedField protowire.Number = 14
+)
+
+// pbMessageFailedReasons mirrors
+//
+//	enum MessageFailedReason {
+//	  MESSAGE_FAILED_REASON_UNSPECIFIED = 0;
+//	  MESSAGE_FAILED_REASON_STORAGE = 1;
+//	  MESSAGE_FAILED_REASON_NOT_ATTACHED = 2;
+//	  MESSAGE_FAILED_REASON_TOO_LARGE = 3;
+//	  MESSAGE_FAILED_REASON_TALK_CLOSED = 4;
+//	  MESSAGE_FAILED_REASON_RATE_LIMITED = 5;
+//	}
+var pbMessageFailedReasons = map[defs.MessageFailedReason]uint64{
+	defs.MessageFailedReasonStorage:     1,
+	defs.MessageFailedReasonNotAttached: 2,
+	defs.MessageFailedReasonTooLarge:    3,
+	defs.MessageFailedReasonTalkClosed:  4,
+	defs.MessageFailedReasonRateLimited: 5,
+}
+
+// MessageFailed4Customer rejects the message sent with seqID by a TalkResponse carrying MessageFailed.
+func MessageFailed4Customer(seqID uint64, reason defs.MessageFailedReason) *customertalkpb.TalkResponse {
+	return talkResponseWithUnknown(talkResponseMessageFailedField, messageFailed(seqID, reason))
+}
+
+// MessageFailed4Servicer rejects the message sent with seqID by a ServiceResponse carrying MessageFailed.
+func MessageFailed4Servicer(seqID uint64, reason defs.MessageFailedReason) *customertalkpb.ServiceResponse {
+	return serviceResponseWithUnknown(serviceResponseMessageFailedField, messageFailed(seqID, reason))
+}
+
+// messageFailed encodes
+//
+//	message MessageFailed {
+//	  uint64 seq_id = 1;
+//	  MessageFailedReason reason = 2;
+//	  bool retryable = 3; // sending the same message again may succeed
+//	}
+func messageFailed(seqID uint64, reason defs.MessageFailedReason) (d []byte) {
+	d = protowire.AppendTag(d, 1, protowire.VarintType)
+	d = protowire.AppendVarint(d, seqID)
+	d = protowire.AppendTag(d, 2, protowire.VarintType)
+	d = protowire.AppendVarint(d, pbMessageFailedReasons[reason])
+	d = protowire.AppendTag(d, 3, protowire.VarintType)
+	d = protowire.AppendVarint(d, protowire.EncodeBool(reason.Retryable()))
+
+	return
+}
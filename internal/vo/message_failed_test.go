@@ -0,0 +1,33 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestMessageFailed(t *testing.T) {
+	check := func(unknown []byte, num protowire.Number, seqID uint64, reason uint64, retryable bool) {
+		value, ok := consumeBytesField(unknown, num)
+		assert.True(t, ok)
+
+		fields := make(map[protowire.Number]uint64)
+
+		assert.True(t, consumeFields(value, func(num protowire.Number, v uint64, _ []byte) {
+			fields[num] = v
+		}))
+		assert.EqualValues(t, map[protowire.Number]uint64{1: seqID, 2: reason, 3: protowire.EncodeBool(retryable)},
+			fields)
+	}
+
+	check(MessageFailed4Customer(3, defs.MessageFailedReasonStorage).ProtoReflect().GetUnknown(),
+		talkResponseMessageFailedField, 3, 1, true)
+	check(MessageFailed4Servicer(4, defs.MessageFailedReasonNotAttached).ProtoReflect().GetUnknown(),
+		serviceResponseMessageFailedField, 4, 2, false)
+	check(MessageFailed4Customer(5, defs.MessageFailedReasonTalkClosed).ProtoReflect().GetUnknown(),
+		talkResponseMessageFailedField, 5, 4, false)
+	check(MessageFailed4Servicer(6, defs.MessageFailedReasonRateLimited).ProtoReflect().GetUnknown(),
+		serviceResponseMessageFailedField, 6, 5, true)
+}
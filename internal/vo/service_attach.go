@@ -0,0 +1,28 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the ServiceResponse oneof field servicers losing the race for a talk are told in,
+// it's carried as an unknown field until a proto release has it.
+const serviceResponseTalkAlreadyAttachedField protowire.Number = 15
+
+// ServiceTalkAlreadyAttachedResponse encodes
+//
+//	message ServiceTalkAlreadyAttached {
+//	  string talk_id = 1;
+//	  uint64 servicer_id = 2; // who the talk is attached to
+//	}
+//
+// into a ServiceResponse.
+func ServiceTalkAlreadyAttachedResponse(talkID string, servicerID uint64) *customertalkpb.ServiceResponse {
+	var attached []byte
+	attached = protowire.AppendTag(attached, 1, protowire.BytesType)
+	attached = protowire.AppendString(attached, talkID)
+	attached = protowire.AppendTag(attached, 2, protowire.VarintType)
+	attached = protowire.AppendVarint(attached, servicerID)
+
+	return serviceResponseWithUnknown(serviceResponseTalkAlreadyAttachedField, attached)
+}
@@ -0,0 +1,29 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestServiceTalkAlreadyAttachedResponse(t *testing.T) {
+	value, ok := consumeBytesField(ServiceTalkAlreadyAttachedResponse("talk1", 3).ProtoReflect().GetUnknown(),
+		serviceResponseTalkAlreadyAttachedField)
+	assert.True(t, ok)
+
+	var talkID string
+
+	var servicerID uint64
+
+	assert.True(t, consumeFields(value, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			talkID = string(b)
+		case 2:
+			servicerID = v
+		}
+	}))
+	assert.EqualValues(t, "talk1", talkID)
+	assert.EqualValues(t, 3, servicerID)
+}
@@ -0,0 +1,28 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the ServiceAttachedTalksResponse fields the servicer load is reported in,
+// they're carried as unknown fields until a proto release has them.
+const (
+	attachedTalksResponseActiveTalksField protowire.Number = 3
+	attachedTalksResponseMaxTalksField    protowire.Number = 4
+)
+
+// SetAttachedTalksLoad adds
+//
+//	uint32 active_talks = 3;
+//	uint32 max_talks = 4; // 0 for no limit
+//
+// to resp.
+func SetAttachedTalksLoad(resp *customertalkpb.ServiceAttachedTalksResponse, activeTalks, maxTalks int) {
+	if resp == nil {
+		return
+	}
+
+	appendUnknownVarint(resp.ProtoReflect(), attachedTalksResponseActiveTalksField, uint64(activeTalks))
+	appendUnknownVarint(resp.ProtoReflect(), attachedTalksResponseMaxTalksField, uint64(maxTalks))
+}
@@ -0,0 +1,120 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the ServiceRequest and ServiceResponse oneof fields the classification messages travel in,
+// they're carried as unknown fields until a proto release has them.
+const (
+	serviceRequestClassifyTalkField    protowire.Number = 14
+	serviceResponseTalkClassifiedField protowire.Number = 13
+)
+
+// ServiceClassifyTalkRequestFromUnknown decodes
+//
+//	message ServiceClassifyTalkRequest {
+//	  string talk_id = 1;
+//	  repeated string add_tags = 2;
+//	  repeated string remove_tags = 3;
+//	  optional string category = 4; // empty clears the category
+//	  map<string, string> custom_fields = 5; // empty values remove the fields
+//	}
+//
+// it returns nil if request carries no well-formed classify request.
+func ServiceClassifyTalkRequestFromUnknown(request *customertalkpb.ServiceRequest) *defs.TalkClassification {
+	if request == nil {
+		return nil
+	}
+
+	value, ok := consumeBytesField(request.ProtoReflect().GetUnknown(), serviceRequestClassifyTalkField)
+	if !ok {
+		return nil
+	}
+
+	classification := &defs.TalkClassification{}
+
+	entryOk := true
+
+	if !consumeFields(value, func(num protowire.Number, _ uint64, b []byte) {
+		switch num {
+		case 1:
+			classification.TalkID = string(b)
+		case 2:
+			classification.AddTags = append(classification.AddTags, string(b))
+		case 3:
+			classification.RemoveTags = append(classification.RemoveTags, string(b))
+		case 4:
+			category := string(b)
+			classification.Category = &category
+		case 5:
+			var key, v string
+
+			if !consumeFields(b, func(num protowire.Number, _ uint64, b []byte) {
+				switch num {
+				case mapEntryKeyField:
+					key = string(b)
+				case mapEntryValueField:
+					v = string(b)
+				}
+			}) {
+				entryOk = false
+
+				return
+			}
+
+			if classification.CustomFields == nil {
+				classification.CustomFields = make(map[string]string)
+			}
+
+			classification.CustomFields[key] = v
+		}
+	}) || !entryOk {
+		return nil
+	}
+
+	return classification
+}
+
+// ServiceTalkClassifiedResponse encodes
+//
+//	message ServiceTalkClassified {
+//	  string talk_id = 1;
+//	  repeated string tags = 2;
+//	  string category = 3;
+//	  map<string, string> custom_fields = 4;
+//	  uint64 servicer_id = 5; // who classified the talk
+//	}
+//
+// into a ServiceResponse.
+func ServiceTalkClassifiedResponse(classified *defs.TalkClassified) *customertalkpb.ServiceResponse {
+	var d []byte
+	d = protowire.AppendTag(d, 1, protowire.BytesType)
+	d = protowire.AppendString(d, classified.TalkID)
+	d = appendTalkClassification(d, 2, 3, 4, classified.Tags, classified.Category, classified.CustomFields)
+	d = protowire.AppendTag(d, 5, protowire.VarintType)
+	d = protowire.AppendVarint(d, classified.ServicerID)
+
+	return serviceResponseWithUnknown(serviceResponseTalkClassifiedField, d)
+}
+
+//
+//
+//
+
+func appendTalkClassification(b []byte, tagsNum, categoryNum, customFieldsNum protowire.Number, tags []string,
+	category string, customFields map[string]string) []byte {
+	for _, tag := range tags {
+		b = protowire.AppendTag(b, tagsNum, protowire.BytesType)
+		b = protowire.AppendString(b, tag)
+	}
+
+	if category != "" {
+		b = protowire.AppendTag(b, categoryNum, protowire.BytesType)
+		b = protowire.AppendString(b, category)
+	}
+
+	return appendStringMap(b, customFieldsNum, customFields)
+}
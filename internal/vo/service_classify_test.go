@@ -0,0 +1,100 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestServiceClassifyTalkRequestFromUnknown(t *testing.T) {
+	var d []byte
+	d = protowire.AppendTag(d, 1, protowire.BytesType)
+	d = protowire.AppendString(d, "talk1")
+	d = protowire.AppendTag(d, 2, protowire.BytesType)
+	d = protowire.AppendString(d, "vip")
+	d = protowire.AppendTag(d, 2, protowire.BytesType)
+	d = protowire.AppendString(d, "退款")
+	d = protowire.AppendTag(d, 3, protowire.BytesType)
+	d = protowire.AppendString(d, "new")
+	d = protowire.AppendTag(d, 4, protowire.BytesType)
+	d = protowire.AppendString(d, "")
+	d = appendStringMap(d, 5, map[string]string{"amount": "12.5", "region": ""})
+
+	b := protowire.AppendTag(nil, serviceRequestClassifyTalkField, protowire.BytesType)
+	b = protowire.AppendBytes(b, d)
+
+	request := &customertalkpb.ServiceRequest{}
+	request.ProtoReflect().SetUnknown(b)
+
+	b, err := proto.Marshal(request)
+	assert.Nil(t, err)
+
+	request = &customertalkpb.ServiceRequest{}
+	assert.Nil(t, proto.Unmarshal(b, request))
+
+	classification := ServiceClassifyTalkRequestFromUnknown(request)
+	assert.NotNil(t, classification)
+	assert.EqualValues(t, "talk1", classification.TalkID)
+	assert.EqualValues(t, []string{"vip", "退款"}, classification.AddTags)
+	assert.EqualValues(t, []string{"new"}, classification.RemoveTags)
+	assert.NotNil(t, classification.Category)
+	assert.EqualValues(t, "", *classification.Category)
+	assert.EqualValues(t, map[string]string{"amount": "12.5", "region": ""}, classification.CustomFields)
+
+	assert.Nil(t, ServiceClassifyTalkRequestFromUnknown(&customertalkpb.ServiceRequest{}))
+	assert.Nil(t, ServiceClassifyTalkRequestFromUnknown(nil))
+}
+
+func TestServiceTalkClassifiedResponse(t *testing.T) {
+	resp := ServiceTalkClassifiedResponse(&defs.TalkClassified{
+		TalkID:       "talk1",
+		ServicerID:   3,
+		Tags:         []string{"vip", "退款"},
+		Category:     "售后",
+		CustomFields: map[string]string{"amount": "12.5"},
+	})
+
+	value, ok := consumeBytesField(resp.ProtoReflect().GetUnknown(), serviceResponseTalkClassifiedField)
+	assert.True(t, ok)
+
+	var classified defs.TalkClassified
+
+	assert.True(t, consumeFields(value, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			classified.TalkID = string(b)
+		case 2:
+			classified.Tags = append(classified.Tags, string(b))
+		case 3:
+			classified.Category = string(b)
+		case 4:
+			var key, fieldValue string
+
+			consumeFields(b, func(num protowire.Number, _ uint64, b []byte) {
+				if num == mapEntryKeyField {
+					key = string(b)
+				} else {
+					fieldValue = string(b)
+				}
+			})
+
+			if classified.CustomFields == nil {
+				classified.CustomFields = make(map[string]string)
+			}
+
+			classified.CustomFields[key] = fieldValue
+		case 5:
+			classified.ServicerID = v
+		}
+	}))
+
+	assert.EqualValues(t, "talk1", classified.TalkID)
+	assert.EqualValues(t, 3, classified.ServicerID)
+	assert.EqualValues(t, []string{"vip", "退款"}, classified.Tags)
+	assert.EqualValues(t, "售后", classified.Category)
+	assert.EqualValues(t, map[string]string{"amount": "12.5"}, classified.CustomFields)
+}
@@ -0,0 +1,52 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// serviceRequestCloseField is the ServiceRequest oneof field newer clients send the close request in,
+// it's read from the unknown fields until a proto release carries it.
+const serviceRequestCloseField protowire.Number = 7
+
+// ServiceCloseRequest mirrors
+//
+//	message ServiceCloseRequest {
+//	  string talk_id = 1;
+//	  string disposition = 2;
+//	  string note = 3;
+//	}
+type ServiceCloseRequest struct {
+	TalkID      string
+	Disposition string
+	Note        string
+}
+
+// ServiceCloseRequestFromUnknown returns nil if request carries no well-formed close request.
+func ServiceCloseRequestFromUnknown(request *customertalkpb.ServiceRequest) *ServiceCloseRequest {
+	if request == nil {
+		return nil
+	}
+
+	value, ok := consumeBytesField(request.ProtoReflect().GetUnknown(), serviceRequestCloseField)
+	if !ok {
+		return nil
+	}
+
+	closeRequest := &ServiceCloseRequest{}
+
+	if !consumeFields(value, func(num protowire.Number, _ uint64, b []byte) {
+		switch num {
+		case 1:
+			closeRequest.TalkID = string(b)
+		case 2:
+			closeRequest.Disposition = string(b)
+		case 3:
+			closeRequest.Note = string(b)
+		}
+	}) {
+		return nil
+	}
+
+	return closeRequest
+}
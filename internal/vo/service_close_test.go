@@ -0,0 +1,46 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestServiceCloseRequestFromUnknown(t *testing.T) {
+	var closeRequest []byte
+	closeRequest = protowire.AppendTag(closeRequest, 1, protowire.BytesType)
+	closeRequest = protowire.AppendString(closeRequest, "talk1")
+	closeRequest = protowire.AppendTag(closeRequest, 2, protowire.BytesType)
+	closeRequest = protowire.AppendString(closeRequest, "solved")
+	closeRequest = protowire.AppendTag(closeRequest, 9, protowire.VarintType)
+	closeRequest = protowire.AppendVarint(closeRequest, 1)
+	closeRequest = protowire.AppendTag(closeRequest, 3, protowire.BytesType)
+	closeRequest = protowire.AppendString(closeRequest, "refunded")
+
+	d := protowire.AppendTag(nil, serviceRequestCloseField, protowire.BytesType)
+	d = protowire.AppendBytes(d, closeRequest)
+
+	// as received from a newer client
+	var request customertalkpb.ServiceRequest
+
+	assert.Nil(t, proto.Unmarshal(d, &request))
+	assert.Nil(t, request.GetRequest())
+
+	r := ServiceCloseRequestFromUnknown(&request)
+	assert.NotNil(t, r)
+	assert.EqualValues(t, "talk1", r.TalkID)
+	assert.EqualValues(t, "solved", r.Disposition)
+	assert.EqualValues(t, "refunded", r.Note)
+
+	assert.Nil(t, ServiceCloseRequestFromUnknown(&customertalkpb.ServiceRequest{
+		Request: &customertalkpb.ServiceRequest_Detach{
+			Detach: &customertalkpb.ServiceDetachRequest{TalkId: "talk1"},
+		},
+	}))
+
+	request.ProtoReflect().SetUnknown(d[:len(d)-2])
+	assert.Nil(t, ServiceCloseRequestFromUnknown(&request))
+}
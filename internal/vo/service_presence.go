@@ -0,0 +1,133 @@
+package vo
+
+import (
+	"sort"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the ServiceRequest and ServiceResponse oneof fields the presence messages travel in,
+// they're carried as unknown fields until a proto release has them.
+const (
+	serviceRequestSetPresenceField     protowire.Number = 8
+	serviceRequestQueryPresencesField  protowire.Number = 9
+	serviceResponsePresencesField      protowire.Number = 11
+	servicePresencesResponseInfosField protowire.Number = 1
+)
+
+// pbServicerPresence mirrors
+//
+//	enum ServicerPresence {
+//	  SERVICER_PRESENCE_UNSPECIFIED = 0;
+//	  SERVICER_PRESENCE_ONLINE = 1;
+//	  SERVICER_PRESENCE_AWAY = 2;
+//	  SERVICER_PRESENCE_BUSY = 3;
+//	  SERVICER_PRESENCE_OFFLINE = 4;
+//	}
+var pbServicerPresences = map[uint64]defs.ServicerPresence{
+	1: defs.ServicerPresenceOnline,
+	2: defs.ServicerPresenceAway,
+	3: defs.ServicerPresenceBusy,
+	4: defs.ServicerPresenceOffline,
+}
+
+// ServiceSetPresenceRequestFromUnknown decodes
+//
+//	message ServiceSetPresenceRequest {
+//	  ServicerPresence presence = 1;
+//	}
+//
+// ok is false if request carries no set presence request, presence is not valid for an unknown value.
+func ServiceSetPresenceRequestFromUnknown(request *customertalkpb.ServiceRequest) (presence defs.ServicerPresence, ok bool) {
+	presence = -1
+
+	if request == nil {
+		return
+	}
+
+	value, ok := consumeBytesField(request.ProtoReflect().GetUnknown(), serviceRequestSetPresenceField)
+	if !ok {
+		return
+	}
+
+	var v uint64
+
+	if !consumeFields(value, func(num protowire.Number, fieldV uint64, b []byte) {
+		if num == 1 && b == nil {
+			v = fieldV
+		}
+	}) {
+		return
+	}
+
+	if p, exists := pbServicerPresences[v]; exists {
+		presence = p
+	}
+
+	return
+}
+
+// IsServiceQueryPresencesRequest checks for an empty ServiceQueryPresencesRequest.
+func IsServiceQueryPresencesRequest(request *customertalkpb.ServiceRequest) bool {
+	if request == nil {
+		return false
+	}
+
+	_, ok := consumeBytesField(request.ProtoReflect().GetUnknown(), serviceRequestQueryPresencesField)
+
+	return ok
+}
+
+// ServicePresencesResponse encodes
+//
+//	message ServicerPresenceInfo {
+//	  uint64 servicer_id = 1;
+//	  ServicerPresence presence = 2;
+//	}
+//
+//	message ServicePresencesResponse {
+//	  repeated ServicerPresenceInfo presences = 1;
+//	}
+//
+// into a ServiceResponse, ordered by servicer ID.
+func ServicePresencesResponse(presences map[uint64]defs.ServicerPresence) *customertalkpb.ServiceResponse {
+	servicerIDs := make([]uint64, 0, len(presences))
+	for servicerID := range presences {
+		servicerIDs = append(servicerIDs, servicerID)
+	}
+
+	sort.Slice(servicerIDs, func(i, j int) bool {
+		return servicerIDs[i] < servicerIDs[j]
+	})
+
+	var infos []byte
+
+	for _, servicerID := range servicerIDs {
+		var info []byte
+		info = protowire.AppendTag(info, 1, protowire.VarintType)
+		info = protowire.AppendVarint(info, servicerID)
+		info = protowire.AppendTag(info, 2, protowire.VarintType)
+		info = protowire.AppendVarint(info, servicerPresenceDB2Pb(presences[servicerID]))
+
+		infos = protowire.AppendTag(infos, servicePresencesResponseInfosField, protowire.BytesType)
+		infos = protowire.AppendBytes(infos, info)
+	}
+
+	return serviceResponseWithUnknown(serviceResponsePresencesField, infos)
+}
+
+//
+//
+//
+
+func servicerPresenceDB2Pb(presence defs.ServicerPresence) uint64 {
+	for v, p := range pbServicerPresences {
+		if p == presence {
+			return v
+		}
+	}
+
+	return 0
+}
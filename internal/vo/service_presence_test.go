@@ -0,0 +1,92 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestServiceSetPresenceRequestFromUnknown(t *testing.T) {
+	setPresenceRequest := func(presence uint64) *customertalkpb.ServiceRequest {
+		var setRequest []byte
+		setRequest = protowire.AppendTag(setRequest, 1, protowire.VarintType)
+		setRequest = protowire.AppendVarint(setRequest, presence)
+
+		d := protowire.AppendTag(nil, serviceRequestSetPresenceField, protowire.BytesType)
+		d = protowire.AppendBytes(d, setRequest)
+
+		var request customertalkpb.ServiceRequest
+
+		assert.Nil(t, proto.Unmarshal(d, &request))
+
+		return &request
+	}
+
+	presence, ok := ServiceSetPresenceRequestFromUnknown(setPresenceRequest(2))
+	assert.True(t, ok)
+	assert.EqualValues(t, defs.ServicerPresenceAway, presence)
+
+	presence, ok = ServiceSetPresenceRequestFromUnknown(setPresenceRequest(4))
+	assert.True(t, ok)
+	assert.EqualValues(t, defs.ServicerPresenceOffline, presence)
+
+	presence, ok = ServiceSetPresenceRequestFromUnknown(setPresenceRequest(9))
+	assert.True(t, ok)
+	assert.False(t, presence.Valid())
+
+	_, ok = ServiceSetPresenceRequestFromUnknown(&customertalkpb.ServiceRequest{})
+	assert.False(t, ok)
+	assert.False(t, IsServiceQueryPresencesRequest(&customertalkpb.ServiceRequest{}))
+
+	d := protowire.AppendTag(nil, serviceRequestQueryPresencesField, protowire.BytesType)
+	d = protowire.AppendBytes(d, nil)
+
+	var request customertalkpb.ServiceRequest
+
+	assert.Nil(t, proto.Unmarshal(d, &request))
+	assert.True(t, IsServiceQueryPresencesRequest(&request))
+}
+
+func TestServicePresencesResponse(t *testing.T) {
+	resp := ServicePresencesResponse(map[uint64]defs.ServicerPresence{
+		2: defs.ServicerPresenceBusy,
+		1: defs.ServicerPresenceOnline,
+	})
+
+	// as sent to a newer client
+	d, err := proto.Marshal(resp)
+	assert.Nil(t, err)
+
+	infos, ok := consumeBytesField(d, serviceResponsePresencesField)
+	assert.True(t, ok)
+
+	var got [][2]uint64
+
+	for len(infos) > 0 {
+		var info []byte
+
+		info, ok = consumeBytesField(infos, servicePresencesResponseInfosField)
+		assert.True(t, ok)
+
+		_, _, n := protowire.ConsumeTag(infos)
+		infos = infos[n:]
+		infos = infos[protowire.ConsumeFieldValue(servicePresencesResponseInfosField, protowire.BytesType, infos):]
+
+		var item [2]uint64
+
+		for i := range item {
+			_, _, n = protowire.ConsumeTag(info)
+			info = info[n:]
+			item[i], n = protowire.ConsumeVarint(info)
+			info = info[n:]
+		}
+
+		got = append(got, item)
+	}
+
+	assert.EqualValues(t, [][2]uint64{{1, 1}, {2, 3}}, got)
+}
@@ -0,0 +1,46 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the ServiceReloadTalkRequest fields the last seen message is carried in,
+// they're read from the unknown fields until a proto release has them.
+const (
+	serviceReloadLastMessageIDField protowire.Number = 2
+	serviceReloadLastMessageAtField protowire.Number = 3
+)
+
+// ServiceReloadResumePoint reads
+//
+//	string last_message_id = 2;
+//	int64 last_message_at = 3; // unix milliseconds
+//
+// of ServiceReloadTalkRequest, the same as the customers pass on reopening a talk.
+// It returns nil if the servicer needs a full reload.
+func ServiceReloadResumePoint(reload *customertalkpb.ServiceReloadTalkRequest) *defs.ResumePoint {
+	if reload == nil {
+		return nil
+	}
+
+	resumePoint := &defs.ResumePoint{}
+
+	if !consumeFields(reload.ProtoReflect().GetUnknown(), func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case serviceReloadLastMessageIDField:
+			resumePoint.MessageID = string(b)
+		case serviceReloadLastMessageAtField:
+			resumePoint.At = int64(v)
+		}
+	}) {
+		return nil
+	}
+
+	if resumePoint.MessageID == "" && resumePoint.At <= 0 {
+		return nil
+	}
+
+	return resumePoint
+}
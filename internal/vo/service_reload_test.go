@@ -0,0 +1,35 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestServiceReloadResumePoint(t *testing.T) {
+	var d []byte
+	d = protowire.AppendTag(d, serviceReloadLastMessageIDField, protowire.BytesType)
+	d = protowire.AppendString(d, "message1")
+	d = protowire.AppendTag(d, serviceReloadLastMessageAtField, protowire.VarintType)
+	d = protowire.AppendVarint(d, 1700000000123)
+
+	reload := &customertalkpb.ServiceReloadTalkRequest{TalkId: "talk1"}
+	reload.ProtoReflect().SetUnknown(d)
+
+	b, err := proto.Marshal(&customertalkpb.ServiceRequest{
+		Request: &customertalkpb.ServiceRequest_Reload{Reload: reload},
+	})
+	assert.Nil(t, err)
+
+	request := &customertalkpb.ServiceRequest{}
+	assert.Nil(t, proto.Unmarshal(b, request))
+
+	assert.EqualValues(t, &defs.ResumePoint{MessageID: "message1", At: 1700000000123},
+		ServiceReloadResumePoint(request.GetReload()))
+	assert.Nil(t, ServiceReloadResumePoint(&customertalkpb.ServiceReloadTalkRequest{TalkId: "talk1"}))
+	assert.Nil(t, ServiceReloadResumePoint(nil))
+}
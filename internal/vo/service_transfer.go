@@ -0,0 +1,133 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the ServiceRequest and ServiceResponse oneof fields the transfer messages travel in,
+// they're carried as unknown fields until a proto release has them.
+const (
+	serviceRequestTransferField       protowire.Number = 10
+	serviceRequestTransferReplyField  protowire.Number = 11
+	serviceResponseTransferOfferField protowire.Number = 12
+)
+
+// ServiceTransferRequest mirrors
+//
+//	message ServiceTransferRequest {
+//	  string talk_id = 1;
+//	  uint64 target_servicer_id = 2;
+//	  string target_team = 3;
+//	  string reason = 4;
+//	}
+type ServiceTransferRequest struct {
+	TalkID           string
+	TargetServicerID uint64
+	TargetTeam       string
+	Reason           string
+}
+
+// ServiceTransferRequestFromUnknown returns nil if request carries no well-formed transfer request.
+func ServiceTransferRequestFromUnknown(request *customertalkpb.ServiceRequest) *ServiceTransferRequest {
+	if request == nil {
+		return nil
+	}
+
+	value, ok := consumeBytesField(request.ProtoReflect().GetUnknown(), serviceRequestTransferField)
+	if !ok {
+		return nil
+	}
+
+	transferRequest := &ServiceTransferRequest{}
+
+	if !consumeFields(value, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			transferRequest.TalkID = string(b)
+		case 2:
+			transferRequest.TargetServicerID = v
+		case 3:
+			transferRequest.TargetTeam = string(b)
+		case 4:
+			transferRequest.Reason = string(b)
+		}
+	}) {
+		return nil
+	}
+
+	return transferRequest
+}
+
+// ServiceTransferReplyRequest mirrors
+//
+//	message ServiceTransferReplyRequest {
+//	  string talk_id = 1;
+//	  bool accept = 2;
+//	}
+type ServiceTransferReplyRequest struct {
+	TalkID string
+	Accept bool
+}
+
+// ServiceTransferReplyRequestFromUnknown returns nil if request carries no well-formed transfer reply.
+func ServiceTransferReplyRequestFromUnknown(request *customertalkpb.ServiceRequest) *ServiceTransferReplyRequest {
+	if request == nil {
+		return nil
+	}
+
+	value, ok := consumeBytesField(request.ProtoReflect().GetUnknown(), serviceRequestTransferReplyField)
+	if !ok {
+		return nil
+	}
+
+	replyRequest := &ServiceTransferReplyRequest{}
+
+	if !consumeFields(value, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			replyRequest.TalkID = string(b)
+		case 2:
+			replyRequest.Accept = v != 0
+		}
+	}) {
+		return nil
+	}
+
+	return replyRequest
+}
+
+// ServiceTransferOfferResponse encodes
+//
+//	message ServiceTransferOffer {
+//	  string talk_id = 1;
+//	  uint64 from_servicer_id = 2;
+//	  string target_team = 3;
+//	  string reason = 4;
+//	  uint64 expire_at = 5;
+//	}
+//
+// into a ServiceResponse.
+func ServiceTransferOfferResponse(transfer *defs.TalkTransfer) *customertalkpb.ServiceResponse {
+	var offer []byte
+	offer = protowire.AppendTag(offer, 1, protowire.BytesType)
+	offer = protowire.AppendString(offer, transfer.TalkID)
+	offer = protowire.AppendTag(offer, 2, protowire.VarintType)
+	offer = protowire.AppendVarint(offer, transfer.FromServicerID)
+
+	if transfer.ToTeam != "" {
+		offer = protowire.AppendTag(offer, 3, protowire.BytesType)
+		offer = protowire.AppendString(offer, transfer.ToTeam)
+	}
+
+	if transfer.Reason != "" {
+		offer = protowire.AppendTag(offer, 4, protowire.BytesType)
+		offer = protowire.AppendString(offer, transfer.Reason)
+	}
+
+	offer = protowire.AppendTag(offer, 5, protowire.VarintType)
+	offer = protowire.AppendVarint(offer, uint64(transfer.ExpireAt))
+
+	return serviceResponseWithUnknown(serviceResponseTransferOfferField, offer)
+}
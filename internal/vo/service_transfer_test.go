@@ -0,0 +1,76 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestServiceTransferRequestFromUnknown(t *testing.T) {
+	var transferRequest []byte
+	transferRequest = protowire.AppendTag(transferRequest, 1, protowire.BytesType)
+	transferRequest = protowire.AppendString(transferRequest, "talk1")
+	transferRequest = protowire.AppendTag(transferRequest, 3, protowire.BytesType)
+	transferRequest = protowire.AppendString(transferRequest, "billing")
+	transferRequest = protowire.AppendTag(transferRequest, 4, protowire.BytesType)
+	transferRequest = protowire.AppendString(transferRequest, "refund")
+
+	d := protowire.AppendTag(nil, serviceRequestTransferField, protowire.BytesType)
+	d = protowire.AppendBytes(d, transferRequest)
+
+	var request customertalkpb.ServiceRequest
+
+	assert.Nil(t, proto.Unmarshal(d, &request))
+
+	r := ServiceTransferRequestFromUnknown(&request)
+	assert.NotNil(t, r)
+	assert.EqualValues(t, &ServiceTransferRequest{TalkID: "talk1", TargetTeam: "billing", Reason: "refund"}, r)
+	assert.Nil(t, ServiceTransferReplyRequestFromUnknown(&request))
+
+	request.ProtoReflect().SetUnknown(d[:len(d)-2])
+	assert.Nil(t, ServiceTransferRequestFromUnknown(&request))
+
+	var replyRequest []byte
+	replyRequest = protowire.AppendTag(replyRequest, 1, protowire.BytesType)
+	replyRequest = protowire.AppendString(replyRequest, "talk1")
+	replyRequest = protowire.AppendTag(replyRequest, 2, protowire.VarintType)
+	replyRequest = protowire.AppendVarint(replyRequest, 1)
+
+	d = protowire.AppendTag(nil, serviceRequestTransferReplyField, protowire.BytesType)
+	d = protowire.AppendBytes(d, replyRequest)
+
+	request.Reset()
+	assert.Nil(t, proto.Unmarshal(d, &request))
+	assert.EqualValues(t, &ServiceTransferReplyRequest{TalkID: "talk1", Accept: true},
+		ServiceTransferReplyRequestFromUnknown(&request))
+}
+
+func TestServiceTransferOfferResponse(t *testing.T) {
+	resp := ServiceTransferOfferResponse(&defs.TalkTransfer{
+		TalkID:         "talk1",
+		FromServicerID: 2,
+		ToServicerID:   3,
+		ExpireAt:       1000,
+	})
+
+	d, err := proto.Marshal(resp)
+	assert.Nil(t, err)
+
+	offer, ok := consumeBytesField(d, serviceResponseTransferOfferField)
+	assert.True(t, ok)
+
+	fields := make(map[protowire.Number]interface{})
+
+	assert.True(t, consumeFields(offer, func(num protowire.Number, v uint64, b []byte) {
+		if b != nil {
+			fields[num] = string(b)
+		} else {
+			fields[num] = v
+		}
+	}))
+	assert.EqualValues(t, map[protowire.Number]interface{}{1: "talk1", 2: uint64(2), 5: uint64(1000)}, fields)
+}
@@ -0,0 +1,126 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the ServiceRequest oneof fields supervisors watch and whisper in, and the TalkMessage field marking whispers,
+// they're carried as unknown fields until a proto release has them.
+const (
+	serviceRequestWatchTalkField protowire.Number = 12
+	serviceRequestWhisperField   protowire.Number = 13
+	talkMessageWhisperField      protowire.Number = 6
+)
+
+// pbTalkWatchModes mirrors
+//
+//	enum TalkWatchMode {
+//	  TALK_WATCH_MODE_NONE = 0;
+//	  TALK_WATCH_MODE_MONITOR = 1;
+//	  TALK_WATCH_MODE_BARGE = 2;
+//	}
+var pbTalkWatchModes = map[uint64]defs.TalkWatchMode{
+	0: defs.TalkWatchModeNone,
+	1: defs.TalkWatchModeMonitor,
+	2: defs.TalkWatchModeBarge,
+}
+
+// ServiceWatchTalkRequest mirrors
+//
+//	message ServiceWatchTalkRequest {
+//	  string talk_id = 1;
+//	  TalkWatchMode mode = 2;
+//	}
+//
+// Mode is not valid for an unknown value.
+type ServiceWatchTalkRequest struct {
+	TalkID string
+	Mode   defs.TalkWatchMode
+}
+
+// ServiceWatchTalkRequestFromUnknown returns nil if request carries no well-formed watch request.
+func ServiceWatchTalkRequestFromUnknown(request *customertalkpb.ServiceRequest) *ServiceWatchTalkRequest {
+	if request == nil {
+		return nil
+	}
+
+	value, ok := consumeBytesField(request.ProtoReflect().GetUnknown(), serviceRequestWatchTalkField)
+	if !ok {
+		return nil
+	}
+
+	watchRequest := &ServiceWatchTalkRequest{}
+
+	if !consumeFields(value, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			watchRequest.TalkID = string(b)
+		case 2:
+			if mode, exists := pbTalkWatchModes[v]; exists {
+				watchRequest.Mode = mode
+			} else {
+				watchRequest.Mode = -1
+			}
+		}
+	}) {
+		return nil
+	}
+
+	return watchRequest
+}
+
+// ServiceWhisperRequest mirrors
+//
+//	message ServiceWhisperRequest {
+//	  string talk_id = 1;
+//	  string text = 2;
+//	}
+type ServiceWhisperRequest struct {
+	TalkID string
+	Text   string
+}
+
+// ServiceWhisperRequestFromUnknown returns nil if request carries no well-formed whisper request.
+func ServiceWhisperRequestFromUnknown(request *customertalkpb.ServiceRequest) *ServiceWhisperRequest {
+	if request == nil {
+		return nil
+	}
+
+	value, ok := consumeBytesField(request.ProtoReflect().GetUnknown(), serviceRequestWhisperField)
+	if !ok {
+		return nil
+	}
+
+	whisperRequest := &ServiceWhisperRequest{}
+
+	if !consumeFields(value, func(num protowire.Number, _ uint64, b []byte) {
+		switch num {
+		case 1:
+			whisperRequest.TalkID = string(b)
+		case 2:
+			whisperRequest.Text = string(b)
+		}
+	}) {
+		return nil
+	}
+
+	return whisperRequest
+}
+
+// WhisperMessageDB2Pb4Servicer renders the whisper like a message, marked by
+//
+//	bool whisper = 6;
+//
+// in TalkMessage.
+func WhisperMessageDB2Pb4Servicer(message *defs.TalkMessageW) *customertalkpb.TalkMessage {
+	pbMessage := TalkMessageDB2Pb4Servicer(message)
+	if pbMessage == nil {
+		return nil
+	}
+
+	appendUnknownVarint(pbMessage.ProtoReflect(), talkMessageWhisperField, protowire.EncodeBool(true))
+
+	return pbMessage
+}
@@ -0,0 +1,62 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestServiceWatchTalkRequestFromUnknown(t *testing.T) {
+	watchRequest := func(mode uint64) *customertalkpb.ServiceRequest {
+		var value []byte
+		value = protowire.AppendTag(value, 1, protowire.BytesType)
+		value = protowire.AppendString(value, "talk1")
+		value = protowire.AppendTag(value, 2, protowire.VarintType)
+		value = protowire.AppendVarint(value, mode)
+
+		d := protowire.AppendTag(nil, serviceRequestWatchTalkField, protowire.BytesType)
+		d = protowire.AppendBytes(d, value)
+
+		var request customertalkpb.ServiceRequest
+
+		assert.Nil(t, proto.Unmarshal(d, &request))
+
+		return &request
+	}
+
+	assert.EqualValues(t, &ServiceWatchTalkRequest{TalkID: "talk1", Mode: defs.TalkWatchModeBarge},
+		ServiceWatchTalkRequestFromUnknown(watchRequest(2)))
+	assert.False(t, ServiceWatchTalkRequestFromUnknown(watchRequest(7)).Mode.Valid())
+	assert.Nil(t, ServiceWhisperRequestFromUnknown(watchRequest(1)))
+
+	var value []byte
+	value = protowire.AppendTag(value, 1, protowire.BytesType)
+	value = protowire.AppendString(value, "talk1")
+	value = protowire.AppendTag(value, 2, protowire.BytesType)
+	value = protowire.AppendString(value, "offer the coupon")
+
+	d := protowire.AppendTag(nil, serviceRequestWhisperField, protowire.BytesType)
+	d = protowire.AppendBytes(d, value)
+
+	var request customertalkpb.ServiceRequest
+
+	assert.Nil(t, proto.Unmarshal(d, &request))
+	assert.EqualValues(t, &ServiceWhisperRequest{TalkID: "talk1", Text: "offer the coupon"},
+		ServiceWhisperRequestFromUnknown(&request))
+}
+
+func TestWhisperMessageDB2Pb4Servicer(t *testing.T) {
+	pbMessage := WhisperMessageDB2Pb4Servicer(&defs.TalkMessageW{
+		Type:           defs.TalkMessageTypeText,
+		SenderID:       1,
+		SenderUserName: "lead",
+		Text:           "offer the coupon",
+	})
+	assert.EqualValues(t, "lead[1]", pbMessage.GetUser())
+	assert.EqualValues(t, "offer the coupon", pbMessage.GetText())
+	assert.EqualValues(t, []byte{6 << 3, 1}, pbMessage.ProtoReflect().GetUnknown())
+}
@@ -0,0 +1,51 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the TalkMessageW field servicers mark notes with and the TalkMessage field they're shown with,
+// they're carried as unknown fields until a proto release has them.
+const (
+	talkMessageWNoteField protowire.Number = 2
+	talkMessageNoteField  protowire.Number = 7
+)
+
+// TalkMessageWPb2Db4Servicer reads
+//
+//	bool note = 2;
+//
+// of TalkMessageW too, a marked text message is an internal note.
+func TalkMessageWPb2Db4Servicer(message *customertalkpb.TalkMessageW) *defs.TalkMessageW {
+	dbMessage := TalkMessageWPb2Db(message)
+	if dbMessage == nil || dbMessage.Type != defs.TalkMessageTypeText {
+		return dbMessage
+	}
+
+	var note bool
+
+	if !consumeFields(message.ProtoReflect().GetUnknown(), func(num protowire.Number, v uint64, b []byte) {
+		if num == talkMessageWNoteField && b == nil {
+			note = protowire.DecodeBool(v)
+		}
+	}) {
+		return dbMessage
+	}
+
+	if note {
+		dbMessage.Type = defs.TalkMessageTypeNote
+	}
+
+	return dbMessage
+}
+
+// setTalkMessageNote marks the note by
+//
+//	bool note = 7;
+//
+// in TalkMessage.
+func setTalkMessageNote(pbMessage *customertalkpb.TalkMessage) {
+	appendUnknownVarint(pbMessage.ProtoReflect(), talkMessageNoteField, protowire.EncodeBool(true))
+}
@@ -0,0 +1,39 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestTalkMessageWPb2Db4Servicer(t *testing.T) {
+	d, err := proto.Marshal(&customertalkpb.TalkMessageW{
+		SeqId:   1,
+		Message: &customertalkpb.TalkMessageW_Text{Text: "customer verified"},
+	})
+	assert.Nil(t, err)
+
+	d = protowire.AppendTag(d, talkMessageWNoteField, protowire.VarintType)
+	d = protowire.AppendVarint(d, 1)
+
+	// as received from a newer client
+	var message customertalkpb.TalkMessageW
+
+	assert.Nil(t, proto.Unmarshal(d, &message))
+
+	dbMessage := TalkMessageWPb2Db4Servicer(&message)
+	assert.EqualValues(t, defs.TalkMessageTypeNote, dbMessage.Type)
+	assert.EqualValues(t, "customer verified", dbMessage.Text)
+	assert.False(t, dbMessage.VisibleToCustomer())
+
+	// customers can't write notes
+	assert.EqualValues(t, defs.TalkMessageTypeText, TalkMessageWPb2Db(&message).Type)
+
+	pbMessage := TalkMessageDB2Pb4Servicer(dbMessage)
+	assert.EqualValues(t, "customer verified", pbMessage.GetText())
+	assert.EqualValues(t, []byte{7 << 3, 1}, pbMessage.ProtoReflect().GetUnknown())
+}
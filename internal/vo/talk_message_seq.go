@@ -0,0 +1,72 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// the TalkMessage and the TalkMessageConfirmed/ServiceMessageConfirmed fields clients read the seq of
+// the message in its talk from, they're carried as unknown fields until a proto release has them.
+const (
+	talkMessageSeqField      protowire.Number = 8
+	messageConfirmedSeqField protowire.Number = 3
+)
+
+// MessageConfirmed4Customer confirms the message sent with seqID, with
+//
+//	uint64 seq = 3;
+//
+// of TalkMessageConfirmed set to the seq the message got in the talk.
+func MessageConfirmed4Customer(seqID uint64, message *defs.TalkMessageW) *customertalkpb.TalkResponse {
+	confirmed := &customertalkpb.TalkMessageConfirmed{
+		SeqId: seqID,
+		At:    uint64(message.At),
+	}
+
+	appendSeq(confirmed.ProtoReflect(), messageConfirmedSeqField, message.Seq)
+
+	return &customertalkpb.TalkResponse{
+		Talk: &customertalkpb.TalkResponse_MessageConfirmed{
+			MessageConfirmed: confirmed,
+		},
+	}
+}
+
+// MessageConfirmed4Servicer confirms the message sent with seqID, with
+//
+//	uint64 seq = 3;
+//
+// of ServiceMessageConfirmed set to the seq the message got in the talk.
+func MessageConfirmed4Servicer(seqID uint64, message *defs.TalkMessageW) *customertalkpb.ServiceResponse {
+	confirmed := &customertalkpb.ServiceMessageConfirmed{
+		SeqId: seqID,
+		At:    uint64(message.At),
+	}
+
+	appendSeq(confirmed.ProtoReflect(), messageConfirmedSeqField, message.Seq)
+
+	return &customertalkpb.ServiceResponse{
+		Response: &customertalkpb.ServiceResponse_MessageConfirmed{
+			MessageConfirmed: confirmed,
+		},
+	}
+}
+
+// setTalkMessageSeq sets
+//
+//	uint64 seq = 8;
+//
+// of TalkMessage.
+func setTalkMessageSeq(pbMessage *customertalkpb.TalkMessage, seq uint64) {
+	appendSeq(pbMessage.ProtoReflect(), talkMessageSeqField, seq)
+}
+
+func appendSeq(m protoreflect.Message, num protowire.Number, seq uint64) {
+	if seq == 0 {
+		return
+	}
+
+	appendUnknownVarint(m, num, seq)
+}
@@ -0,0 +1,46 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func seqFromUnknown(t *testing.T, b []byte, num protowire.Number) (seq uint64) {
+	assert.True(t, consumeFields(b, func(n protowire.Number, v uint64, b []byte) {
+		if n == num && b == nil {
+			seq = v
+		}
+	}))
+
+	return
+}
+
+func TestTalkMessageSeq(t *testing.T) {
+	message := &defs.TalkMessageW{
+		Seq:  42,
+		At:   1000,
+		Type: defs.TalkMessageTypeText,
+		Text: "hello",
+	}
+
+	pbMessage := TalkMessageDB2Pb4Servicer(message)
+	assert.EqualValues(t, 42, seqFromUnknown(t, pbMessage.ProtoReflect().GetUnknown(), talkMessageSeqField))
+
+	customerConfirmed := MessageConfirmed4Customer(7, message).GetMessageConfirmed()
+	assert.EqualValues(t, 7, customerConfirmed.GetSeqId())
+	assert.EqualValues(t, 1000, customerConfirmed.GetAt())
+	assert.EqualValues(t, 42, seqFromUnknown(t, customerConfirmed.ProtoReflect().GetUnknown(),
+		messageConfirmedSeqField))
+
+	servicerConfirmed := MessageConfirmed4Servicer(7, message).GetMessageConfirmed()
+	assert.EqualValues(t, 7, servicerConfirmed.GetSeqId())
+	assert.EqualValues(t, 42, seqFromUnknown(t, servicerConfirmed.ProtoReflect().GetUnknown(),
+		messageConfirmedSeqField))
+
+	// messages without a seq don't carry one
+	message.Seq = 0
+	assert.Empty(t, TalkMessageDB2Pb4Servicer(message).ProtoReflect().GetUnknown())
+}
@@ -0,0 +1,117 @@
+package vo
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the TalkMessage fields newer clients read the avatar and the system event from,
+// they're carried as unknown fields until a proto release has them.
+const (
+	talkMessageAvatarField      protowire.Number = 4
+	talkMessageSystemEventField protowire.Number = 5
+)
+
+const systemUserName = "系统"
+
+// pbTalkSystemEvents mirrors
+//
+//	enum TalkSystemEvent {
+//	  TALK_SYSTEM_EVENT_UNSPECIFIED = 0;
+//	  TALK_SYSTEM_EVENT_SERVICER_JOINED = 1;
+//	  TALK_SYSTEM_EVENT_SERVICER_LEFT = 2;
+//	  TALK_SYSTEM_EVENT_TALK_TRANSFERRED = 3;
+//	  TALK_SYSTEM_EVENT_TRANSFER_DECLINED = 4;
+//	  TALK_SYSTEM_EVENT_TRANSFER_EXPIRED = 5;
+//	  TALK_SYSTEM_EVENT_TRANSFER_CANCELED = 6;
+//	}
+var pbTalkSystemEvents = map[defs.TalkSystemEvent]uint64{
+	defs.TalkSystemEventServicerJoined:   1,
+	defs.TalkSystemEventServicerLeft:     2,
+	defs.TalkSystemEventTalkTransferred:  3,
+	defs.TalkSystemEventTransferDeclined: 4,
+	defs.TalkSystemEventTransferExpired:  5,
+	defs.TalkSystemEventTransferCanceled: 6,
+}
+
+var talkSystemEventTexts = map[defs.TalkSystemEvent]string{
+	defs.TalkSystemEventServicerJoined:   "%s 加入了会话",
+	defs.TalkSystemEventServicerLeft:     "%s 离开了会话",
+	defs.TalkSystemEventTalkTransferred:  "会话已转接给 %s",
+	defs.TalkSystemEventTransferDeclined: "%s 拒绝了会话转接",
+	defs.TalkSystemEventTransferExpired:  "%s 发起的会话转接已超时",
+	defs.TalkSystemEventTransferCanceled: "%s 接受的会话转接已取消",
+}
+
+// setSystemMessage renders the system message for the servicer named servicerName.
+func setSystemMessage(pbMessage *customertalkpb.TalkMessage, message *defs.TalkMessageW, servicerName string) {
+	event := defs.TalkSystemEvent(message.Text)
+
+	text := message.Text
+	if format, ok := talkSystemEventTexts[event]; ok {
+		text = fmt.Sprintf(format, servicerName)
+	}
+
+	pbMessage.User = systemUserName
+	pbMessage.Message = &customertalkpb.TalkMessage_Text{
+		Text: text,
+	}
+
+	if v, ok := pbTalkSystemEvents[event]; ok {
+		appendUnknownVarint(pbMessage.ProtoReflect(), talkMessageSystemEventField, v)
+	}
+}
+
+// setTalkTransferDetail tells the servicers who transferred the talk to whom and why, the customers see the plain
+// event.
+func setTalkTransferDetail(pbMessage *customertalkpb.TalkMessage, message *defs.TalkMessageW, servicerName string) {
+	if len(message.Data) == 0 {
+		return
+	}
+
+	var detail defs.TalkTransferDetail
+
+	if err := json.Unmarshal(message.Data, &detail); err != nil || detail.FromServicerID == 0 {
+		return
+	}
+
+	target := fmt.Sprintf("[%d]", detail.ToServicerID)
+	if detail.ToTeam != "" {
+		target = detail.ToTeam
+	}
+
+	var text string
+
+	switch defs.TalkSystemEvent(message.Text) {
+	case defs.TalkSystemEventTalkTransferred:
+		text = fmt.Sprintf("[%d] 将会话转接给 %s", detail.FromServicerID, servicerName)
+	case defs.TalkSystemEventTransferDeclined:
+		text = fmt.Sprintf("%s 拒绝了 [%d] 转给 %s 的会话", servicerName, detail.FromServicerID, target)
+	case defs.TalkSystemEventTransferExpired:
+		text = fmt.Sprintf("%s 转给 %s 的会话无人接受，已超时", servicerName, target)
+	case defs.TalkSystemEventTransferCanceled:
+		text = fmt.Sprintf("%s 接受 [%d] 转给 %s 的会话时会话已变更，转接已取消", servicerName, detail.FromServicerID, target)
+	default:
+		return
+	}
+
+	if detail.Reason != "" {
+		text += "：" + detail.Reason
+	}
+
+	pbMessage.Message = &customertalkpb.TalkMessage_Text{
+		Text: text,
+	}
+}
+
+func setTalkMessageAvatar(pbMessage *customertalkpb.TalkMessage, avatar string) {
+	if avatar == "" {
+		return
+	}
+
+	appendUnknownBytes(pbMessage.ProtoReflect(), talkMessageAvatarField, []byte(avatar))
+}
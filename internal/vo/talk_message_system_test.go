@@ -0,0 +1,94 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type utServicerProfiles map[uint64]defs.ServicerProfile
+
+func (profiles utServicerProfiles) GetServicerProfile(servicerID uint64) defs.ServicerProfile {
+	return profiles[servicerID]
+}
+
+func TestTalkMessageDB2Pb4Customer(t *testing.T) {
+	profiles := utServicerProfiles{
+		1: {Name: "Alice", Avatar: "https://avatars/alice.png"},
+	}
+
+	pbMessage := TalkMessageDB2Pb4Customer(&defs.TalkMessageW{
+		Type:     defs.TalkMessageTypeText,
+		SenderID: 1,
+		Text:     "hi",
+	}, profiles)
+	assert.EqualValues(t, "Alice", pbMessage.GetUser())
+	assert.EqualValues(t, "hi", pbMessage.GetText())
+
+	d := protowire.AppendTag(nil, talkMessageAvatarField, protowire.BytesType)
+	d = protowire.AppendString(d, "https://avatars/alice.png")
+	assert.EqualValues(t, d, pbMessage.ProtoReflect().GetUnknown())
+
+	pbMessage = TalkMessageDB2Pb4Customer(&defs.TalkMessageW{
+		CustomerMessage: true,
+		Type:            defs.TalkMessageTypeText,
+		Text:            "hello",
+	}, profiles)
+	assert.EqualValues(t, "您", pbMessage.GetUser())
+	assert.Nil(t, pbMessage.ProtoReflect().GetUnknown())
+
+	pbMessage = TalkMessageDB2Pb4Customer(&defs.TalkMessageW{
+		Type:     defs.TalkMessageTypeSystem,
+		SenderID: 1,
+		Text:     string(defs.TalkSystemEventTalkTransferred),
+	}, profiles)
+	assert.EqualValues(t, systemUserName, pbMessage.GetUser())
+	assert.EqualValues(t, "会话已转接给 Alice", pbMessage.GetText())
+
+	d = protowire.AppendTag(nil, talkMessageSystemEventField, protowire.VarintType)
+	d = protowire.AppendVarint(d, 3)
+	assert.EqualValues(t, d, pbMessage.ProtoReflect().GetUnknown())
+
+	pbMessage = TalkMessageDB2Pb4Servicer(&defs.TalkMessageW{
+		Type:     defs.TalkMessageTypeSystem,
+		SenderID: 2,
+		Text:     string(defs.TalkSystemEventServicerLeft),
+	})
+	assert.EqualValues(t, "[2] 离开了会话", pbMessage.GetText())
+
+	transferMessage := &defs.TalkMessageW{
+		Type:     defs.TalkMessageTypeSystem,
+		SenderID: 1,
+		Text:     string(defs.TalkSystemEventTalkTransferred),
+		Data:     []byte(`{"FromServicerID":2,"Reason":"billing question"}`),
+	}
+
+	pbMessage = TalkMessageDB2Pb4Servicer(transferMessage)
+	assert.EqualValues(t, "[2] 将会话转接给 [1]：billing question", pbMessage.GetText())
+
+	// the reason is kept from the customer
+	pbMessage = TalkMessageDB2Pb4Customer(transferMessage, profiles)
+	assert.EqualValues(t, "会话已转接给 Alice", pbMessage.GetText())
+
+	pbMessage = TalkMessageDB2Pb4Servicer(&defs.TalkMessageW{
+		Type:     defs.TalkMessageTypeSystem,
+		SenderID: 3,
+		Text:     string(defs.TalkSystemEventTransferDeclined),
+		Data:     []byte(`{"FromServicerID":2,"ToTeam":"billing","Reason":"refund asked"}`),
+	})
+	assert.EqualValues(t, "[3] 拒绝了 [2] 转给 billing 的会话：refund asked", pbMessage.GetText())
+
+	pbMessage = TalkMessageDB2Pb4Servicer(&defs.TalkMessageW{
+		Type:     defs.TalkMessageTypeSystem,
+		SenderID: 2,
+		Text:     string(defs.TalkSystemEventTransferExpired),
+		Data:     []byte(`{"FromServicerID":2,"ToServicerID":3}`),
+	})
+	assert.EqualValues(t, "[2] 转给 [3] 的会话无人接受，已超时", pbMessage.GetText())
+
+	d = protowire.AppendTag(nil, talkMessageSystemEventField, protowire.VarintType)
+	d = protowire.AppendVarint(d, 5)
+	assert.EqualValues(t, d, pbMessage.ProtoReflect().GetUnknown())
+}
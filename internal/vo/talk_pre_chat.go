@@ -0,0 +1,168 @@
+package vo
+
+import (
+	"sort"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the TalkCreateRequest and TalkInfo fields the pre-chat form, the client context and the classification travel in,
+// they're carried as unknown fields until a proto release has them.
+const (
+	talkCreateRequestFormField    protowire.Number = 2
+	talkInfoFormField             protowire.Number = 7
+	talkInfoClientContextField    protowire.Number = 8
+	talkInfoTagsField             protowire.Number = 9
+	talkInfoCategoryField         protowire.Number = 10
+	talkInfoCustomFieldsField     protowire.Number = 11
+	mapEntryKeyField              protowire.Number = 1
+	mapEntryValueField            protowire.Number = 2
+	talkClientContextPageURLField protowire.Number = 1
+)
+
+// TalkCreateFormFromUnknown decodes
+//
+//	message TalkCreateRequest {
+//	  string title = 1;
+//	  map<string, string> form = 2;
+//	}
+//
+// ok is false if the form is malformed, a nil form means the customer filled in nothing.
+func TalkCreateFormFromUnknown(request *customertalkpb.TalkCreateRequest) (form map[string]string, ok bool) {
+	if request == nil {
+		ok = true
+
+		return
+	}
+
+	entryOk := true
+
+	ok = consumeFields(request.ProtoReflect().GetUnknown(), func(num protowire.Number, _ uint64, value []byte) {
+		if num != talkCreateRequestFormField || value == nil {
+			return
+		}
+
+		var key, v string
+
+		if !consumeFields(value, func(num protowire.Number, _ uint64, b []byte) {
+			switch num {
+			case mapEntryKeyField:
+				key = string(b)
+			case mapEntryValueField:
+				v = string(b)
+			}
+		}) {
+			entryOk = false
+
+			return
+		}
+
+		if form == nil {
+			form = make(map[string]string)
+		}
+
+		form[key] = v
+	}) && entryOk
+
+	if !ok {
+		form = nil
+	}
+
+	return
+}
+
+// TalkInfoRDb2Pb4Servicer adds to the talk info what only the servicers see,
+//
+//	message TalkInfo {
+//	  ...
+//	  map<string, string> form = 7;
+//	  TalkClientContext client_context = 8;
+//	  repeated string tags = 9;
+//	  string category = 10;
+//	  map<string, string> custom_fields = 11;
+//	}
+//
+//	message TalkClientContext {
+//	  string page_url = 1;
+//	  string user_agent = 2;
+//	  string locale = 3;
+//	  string referrer = 4;
+//	}
+func TalkInfoRDb2Pb4Servicer(talkInfo *defs.TalkInfoR) *customertalkpb.TalkInfo {
+	pbTalkInfo := TalkInfoRDb2Pb(talkInfo)
+	if pbTalkInfo == nil {
+		return nil
+	}
+
+	d := appendStringMap(pbTalkInfo.ProtoReflect().GetUnknown(), talkInfoFormField, talkInfo.FormFields)
+
+	if !talkInfo.ClientContext.IsZero() {
+		var clientContext []byte
+
+		for idx, value := range []string{talkInfo.ClientContext.PageURL, talkInfo.ClientContext.UserAgent,
+			talkInfo.ClientContext.Locale, talkInfo.ClientContext.Referrer} {
+			if value == "" {
+				continue
+			}
+
+			clientContext = protowire.AppendTag(clientContext, talkClientContextPageURLField+protowire.Number(idx),
+				protowire.BytesType)
+			clientContext = protowire.AppendString(clientContext, value)
+		}
+
+		d = protowire.AppendTag(d, talkInfoClientContextField, protowire.BytesType)
+		d = protowire.AppendBytes(d, clientContext)
+	}
+
+	d = appendTalkClassification(d, talkInfoTagsField, talkInfoCategoryField, talkInfoCustomFieldsField,
+		talkInfo.Tags, talkInfo.Category, talkInfo.CustomFields)
+
+	if len(d) > 0 {
+		pbTalkInfo.ProtoReflect().SetUnknown(d)
+	}
+
+	return pbTalkInfo
+}
+
+func TalkInfoRsDB2Pb4Servicer(talkInfos []*defs.TalkInfoR) []*customertalkpb.TalkInfo {
+	if talkInfos == nil {
+		return nil
+	}
+
+	rTalkInfos := make([]*customertalkpb.TalkInfo, 0, len(talkInfos))
+
+	for _, info := range talkInfos {
+		rTalkInfos = append(rTalkInfos, TalkInfoRDb2Pb4Servicer(info))
+	}
+
+	return rTalkInfos
+}
+
+//
+//
+//
+
+// appendStringMap appends m as the map<string, string> field num in the order of the keys.
+func appendStringMap(b []byte, num protowire.Number, m map[string]string) []byte {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var entry []byte
+		entry = protowire.AppendTag(entry, mapEntryKeyField, protowire.BytesType)
+		entry = protowire.AppendString(entry, key)
+		entry = protowire.AppendTag(entry, mapEntryValueField, protowire.BytesType)
+		entry = protowire.AppendString(entry, m[key])
+
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+
+	return b
+}
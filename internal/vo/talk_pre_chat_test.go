@@ -0,0 +1,96 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTalkCreateFormFromUnknown(t *testing.T) {
+	var d []byte
+
+	for _, kv := range [][2]string{{"email", "a@b.com"}, {"category", "退款"}} {
+		var entry []byte
+		entry = protowire.AppendTag(entry, mapEntryKeyField, protowire.BytesType)
+		entry = protowire.AppendString(entry, kv[0])
+		entry = protowire.AppendTag(entry, mapEntryValueField, protowire.BytesType)
+		entry = protowire.AppendString(entry, kv[1])
+
+		d = protowire.AppendTag(d, talkCreateRequestFormField, protowire.BytesType)
+		d = protowire.AppendBytes(d, entry)
+	}
+
+	request := &customertalkpb.TalkCreateRequest{Title: "订单问题"}
+	request.ProtoReflect().SetUnknown(d)
+
+	// survives the gateway re-encoding the request
+	b, err := proto.Marshal(request)
+	assert.Nil(t, err)
+
+	request = &customertalkpb.TalkCreateRequest{}
+	assert.Nil(t, proto.Unmarshal(b, request))
+
+	form, ok := TalkCreateFormFromUnknown(request)
+	assert.True(t, ok)
+	assert.EqualValues(t, map[string]string{"email": "a@b.com", "category": "退款"}, form)
+
+	form, ok = TalkCreateFormFromUnknown(&customertalkpb.TalkCreateRequest{Title: "x"})
+	assert.True(t, ok)
+	assert.Nil(t, form)
+
+	request = &customertalkpb.TalkCreateRequest{}
+	request.ProtoReflect().SetUnknown(protowire.AppendTag(nil, talkCreateRequestFormField, protowire.BytesType))
+
+	_, ok = TalkCreateFormFromUnknown(request)
+	assert.False(t, ok)
+}
+
+func TestTalkInfoRDb2Pb4Servicer(t *testing.T) {
+	pbTalkInfo := TalkInfoRDb2Pb4Servicer(&defs.TalkInfoR{
+		TalkID: "talk1",
+		TalkInfoW: defs.TalkInfoW{
+			Title:      "订单问题",
+			FormFields: map[string]string{"orderNumber": "A-1024", "email": "a@b.com"},
+			ClientContext: defs.TalkClientContext{
+				PageURL: "https://shop.example.com",
+				Locale:  "zh-CN",
+			},
+		},
+	})
+	assert.EqualValues(t, "talk1", pbTalkInfo.GetTalkId())
+
+	form := make(map[string]string)
+	clientContext := make(map[protowire.Number]string)
+
+	assert.True(t, consumeFields(pbTalkInfo.ProtoReflect().GetUnknown(), func(num protowire.Number, _ uint64, value []byte) {
+		switch num {
+		case talkInfoFormField:
+			var key, v string
+
+			consumeFields(value, func(num protowire.Number, _ uint64, b []byte) {
+				if num == mapEntryKeyField {
+					key = string(b)
+				} else {
+					v = string(b)
+				}
+			})
+
+			form[key] = v
+		case talkInfoClientContextField:
+			consumeFields(value, func(num protowire.Number, _ uint64, b []byte) {
+				clientContext[num] = string(b)
+			})
+		}
+	}))
+
+	assert.EqualValues(t, map[string]string{"orderNumber": "A-1024", "email": "a@b.com"}, form)
+	assert.EqualValues(t, map[protowire.Number]string{1: "https://shop.example.com", 3: "zh-CN"}, clientContext)
+
+	pbTalkInfo = TalkInfoRDb2Pb4Servicer(&defs.TalkInfoR{TalkID: "talk2"})
+	assert.Empty(t, pbTalkInfo.ProtoReflect().GetUnknown())
+	assert.Nil(t, TalkInfoRDb2Pb4Servicer(nil))
+}
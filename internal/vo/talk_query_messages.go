@@ -0,0 +1,98 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the TalkRequest and ServiceRequest oneof fields the history requests travel in,
+// they're carried as unknown fields until a proto release has them.
+const (
+	talkRequestQueryMessagesField    protowire.Number = 6
+	serviceRequestQueryMessagesField protowire.Number = 15
+)
+
+// QueryMessagesRequest mirrors
+//
+//	message TalkQueryMessagesRequest {
+//	  string message_id = 1; // empty for the end (or the start) of the talk
+//	  bool before = 2;
+//	  int64 count = 3;
+//	}
+//
+// and
+//
+//	message ServiceQueryMessagesRequest {
+//	  string talk_id = 1;
+//	  string message_id = 2;
+//	  bool before = 3;
+//	  int64 count = 4;
+//	}
+//
+// TalkID is empty for the customers, who query the talk of their stream.
+type QueryMessagesRequest struct {
+	TalkID    string
+	MessageID string
+	Before    bool
+	Count     int64
+}
+
+// TalkQueryMessagesRequestFromUnknown returns nil if request carries no well-formed history request.
+func TalkQueryMessagesRequestFromUnknown(request *customertalkpb.TalkRequest) *QueryMessagesRequest {
+	if request == nil {
+		return nil
+	}
+
+	value, ok := consumeBytesField(request.ProtoReflect().GetUnknown(), talkRequestQueryMessagesField)
+	if !ok {
+		return nil
+	}
+
+	queryRequest := &QueryMessagesRequest{}
+
+	if !consumeFields(value, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			queryRequest.MessageID = string(b)
+		case 2:
+			queryRequest.Before = v != 0
+		case 3:
+			queryRequest.Count = int64(v)
+		}
+	}) {
+		return nil
+	}
+
+	return queryRequest
+}
+
+// ServiceQueryMessagesRequestFromUnknown returns nil if request carries no well-formed history request.
+func ServiceQueryMessagesRequestFromUnknown(request *customertalkpb.ServiceRequest) *QueryMessagesRequest {
+	if request == nil {
+		return nil
+	}
+
+	value, ok := consumeBytesField(request.ProtoReflect().GetUnknown(), serviceRequestQueryMessagesField)
+	if !ok {
+		return nil
+	}
+
+	queryRequest := &QueryMessagesRequest{}
+
+	if !consumeFields(value, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			queryRequest.TalkID = string(b)
+		case 2:
+			queryRequest.MessageID = string(b)
+		case 3:
+			queryRequest.Before = v != 0
+		case 4:
+			queryRequest.Count = int64(v)
+		}
+	}) {
+		return nil
+	}
+
+	return queryRequest
+}
@@ -0,0 +1,55 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestQueryMessagesRequestFromUnknown(t *testing.T) {
+	var d []byte
+	d = protowire.AppendTag(d, 1, protowire.BytesType)
+	d = protowire.AppendString(d, "message1")
+	d = protowire.AppendTag(d, 2, protowire.VarintType)
+	d = protowire.AppendVarint(d, protowire.EncodeBool(true))
+	d = protowire.AppendTag(d, 3, protowire.VarintType)
+	d = protowire.AppendVarint(d, 30)
+
+	b := protowire.AppendTag(nil, talkRequestQueryMessagesField, protowire.BytesType)
+	b = protowire.AppendBytes(b, d)
+
+	talkRequest := &customertalkpb.TalkRequest{}
+	talkRequest.ProtoReflect().SetUnknown(b)
+
+	b, err := proto.Marshal(talkRequest)
+	assert.Nil(t, err)
+
+	talkRequest = &customertalkpb.TalkRequest{}
+	assert.Nil(t, proto.Unmarshal(b, talkRequest))
+
+	assert.EqualValues(t, &QueryMessagesRequest{MessageID: "message1", Before: true, Count: 30},
+		TalkQueryMessagesRequestFromUnknown(talkRequest))
+	assert.Nil(t, TalkQueryMessagesRequestFromUnknown(&customertalkpb.TalkRequest{}))
+
+	d = protowire.AppendTag(nil, 1, protowire.BytesType)
+	d = protowire.AppendString(d, "talk1")
+	d = protowire.AppendTag(d, 4, protowire.VarintType)
+	d = protowire.AppendVarint(d, 10)
+
+	b = protowire.AppendTag(nil, serviceRequestQueryMessagesField, protowire.BytesType)
+	b = protowire.AppendBytes(b, d)
+
+	serviceRequest := &customertalkpb.ServiceRequest{}
+	serviceRequest.ProtoReflect().SetUnknown(b)
+
+	assert.EqualValues(t, &QueryMessagesRequest{TalkID: "talk1", Count: 10},
+		ServiceQueryMessagesRequestFromUnknown(serviceRequest))
+	assert.Nil(t, ServiceQueryMessagesRequestFromUnknown(&customertalkpb.ServiceRequest{}))
+
+	serviceRequest.ProtoReflect().SetUnknown(protowire.AppendTag(nil, serviceRequestQueryMessagesField,
+		protowire.BytesType))
+	assert.Nil(t, ServiceQueryMessagesRequestFromUnknown(serviceRequest))
+}
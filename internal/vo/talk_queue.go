@@ -0,0 +1,82 @@
+package vo
+
+import (
+	"time"
+
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the TalkResponse oneof fields newer customer clients read the queue events from,
+// they're carried as unknown fields until a proto release has them.
+const (
+	talkResponseQueueStatusField    protowire.Number = 7
+	talkResponseServicerJoinedField protowire.Number = 8
+)
+
+// the TalkResponse oneof fields for the servicers leaving the talk.
+const (
+	talkResponseServicerLeftField        protowire.Number = 11
+	talkResponseServicerTransferredField protowire.Number = 12
+)
+
+// TalkQueueStatusResponse encodes
+//
+//	message TalkQueueStatus {
+//	  uint32 position = 1; // 1 for the next talk to be assigned
+//	  uint32 estimated_wait_seconds = 2; // 0 if there's no estimate yet
+//	}
+func TalkQueueStatusResponse(position int, estimatedWait time.Duration) *customertalkpb.TalkResponse {
+	var status []byte
+	status = protowire.AppendTag(status, 1, protowire.VarintType)
+	status = protowire.AppendVarint(status, uint64(position))
+
+	if seconds := uint64(estimatedWait.Round(time.Second) / time.Second); seconds > 0 {
+		status = protowire.AppendTag(status, 2, protowire.VarintType)
+		status = protowire.AppendVarint(status, seconds)
+	}
+
+	return talkResponseWithUnknown(talkResponseQueueStatusField, status)
+}
+
+// TalkServicerJoinedResponse encodes
+//
+//	message TalkServicerJoined {
+//	  uint64 servicer_id = 1;
+//	}
+func TalkServicerJoinedResponse(servicerID uint64) *customertalkpb.TalkResponse {
+	var joined []byte
+	joined = protowire.AppendTag(joined, 1, protowire.VarintType)
+	joined = protowire.AppendVarint(joined, servicerID)
+
+	return talkResponseWithUnknown(talkResponseServicerJoinedField, joined)
+}
+
+// TalkServicerLeftResponse encodes
+//
+//	message TalkServicerLeft {
+//	  uint64 servicer_id = 1;
+//	}
+func TalkServicerLeftResponse(servicerID uint64) *customertalkpb.TalkResponse {
+	var left []byte
+	left = protowire.AppendTag(left, 1, protowire.VarintType)
+	left = protowire.AppendVarint(left, servicerID)
+
+	return talkResponseWithUnknown(talkResponseServicerLeftField, left)
+}
+
+// TalkServicerTransferredResponse encodes
+//
+//	message TalkServicerTransferred {
+//	  uint64 from_servicer_id = 1;
+//	  uint64 to_servicer_id = 2;
+//	}
+func TalkServicerTransferredResponse(fromServicerID, toServicerID uint64) *customertalkpb.TalkResponse {
+	var transferred []byte
+	transferred = protowire.AppendTag(transferred, 1, protowire.VarintType)
+	transferred = protowire.AppendVarint(transferred, fromServicerID)
+	transferred = protowire.AppendTag(transferred, 2, protowire.VarintType)
+	transferred = protowire.AppendVarint(transferred, toServicerID)
+
+	return talkResponseWithUnknown(talkResponseServicerTransferredField, transferred)
+}
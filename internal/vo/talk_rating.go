@@ -0,0 +1,66 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the TalkRequest and TalkResponse oneof fields the rating messages travel in,
+// they're carried as unknown fields until a proto release has them.
+const (
+	talkRequestRateField          protowire.Number = 5
+	talkResponseRatingPromptField protowire.Number = 9
+)
+
+// TalkRateRequest mirrors
+//
+//	message TalkRateRequest {
+//	  uint32 score = 1; // 1 to 5
+//	  string comment = 2;
+//	}
+type TalkRateRequest struct {
+	Score   int
+	Comment string
+}
+
+// TalkRateRequestFromUnknown returns nil if request carries no well-formed rate request.
+func TalkRateRequestFromUnknown(request *customertalkpb.TalkRequest) *TalkRateRequest {
+	if request == nil {
+		return nil
+	}
+
+	value, ok := consumeBytesField(request.ProtoReflect().GetUnknown(), talkRequestRateField)
+	if !ok {
+		return nil
+	}
+
+	rateRequest := &TalkRateRequest{}
+
+	if !consumeFields(value, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			rateRequest.Score = int(v)
+		case 2:
+			rateRequest.Comment = string(b)
+		}
+	}) {
+		return nil
+	}
+
+	return rateRequest
+}
+
+// TalkRatingPromptResponse encodes
+//
+//	message TalkRatingPrompt {
+//	  string talk_id = 1;
+//	}
+//
+// it asks the customer to rate the closed talk.
+func TalkRatingPromptResponse(talkID string) *customertalkpb.TalkResponse {
+	var prompt []byte
+	prompt = protowire.AppendTag(prompt, 1, protowire.BytesType)
+	prompt = protowire.AppendString(prompt, talkID)
+
+	return talkResponseWithUnknown(talkResponseRatingPromptField, prompt)
+}
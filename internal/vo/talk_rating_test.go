@@ -0,0 +1,40 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestTalkRateRequestFromUnknown(t *testing.T) {
+	var rate []byte
+	rate = protowire.AppendTag(rate, 1, protowire.VarintType)
+	rate = protowire.AppendVarint(rate, 4)
+	rate = protowire.AppendTag(rate, 2, protowire.BytesType)
+	rate = protowire.AppendString(rate, "很耐心")
+
+	d := protowire.AppendTag(nil, talkRequestRateField, protowire.BytesType)
+	d = protowire.AppendBytes(d, rate)
+
+	request := &customertalkpb.TalkRequest{}
+	request.ProtoReflect().SetUnknown(d)
+
+	assert.EqualValues(t, &TalkRateRequest{Score: 4, Comment: "很耐心"}, TalkRateRequestFromUnknown(request))
+
+	assert.Nil(t, TalkRateRequestFromUnknown(&customertalkpb.TalkRequest{}))
+	assert.Nil(t, TalkRateRequestFromUnknown(nil))
+
+	resp := TalkRatingPromptResponse("talk1")
+	assert.Nil(t, resp.GetTalk())
+
+	d = resp.ProtoReflect().GetUnknown()
+	num, _, n := protowire.ConsumeTag(d)
+	assert.EqualValues(t, talkResponseRatingPromptField, num)
+
+	prompt, _ := protowire.ConsumeBytes(d[n:])
+	_, _, n = protowire.ConsumeTag(prompt)
+	talkID, _ := protowire.ConsumeBytes(prompt[n:])
+	assert.EqualValues(t, "talk1", string(talkID))
+}
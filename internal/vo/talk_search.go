@@ -0,0 +1,123 @@
+package vo
+
+import (
+	"encoding/base64"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sbasestarter/customer-service-be/gens/talksearchpb"
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+)
+
+func TalkSearchResultDB2Pb(talkInfo *defs.TalkInfoR) *talksearchpb.TalkSearchResult {
+	if talkInfo == nil {
+		return nil
+	}
+
+	result := &talksearchpb.TalkSearchResult{
+		TalkId:          talkInfo.TalkID,
+		Status:          talksearchpb.TalkSearchStatus(talkInfo.Status),
+		Title:           talkInfo.Title,
+		StartAt:         talkInfo.StartAt,
+		FinishedAt:      talkInfo.FinishedAt,
+		CreatorId:       talkInfo.CreatorID,
+		CreatorUserName: talkInfo.CreatorUserName,
+		ServicerId:      talkInfo.ServiceID,
+		Tags:            talkInfo.Tags,
+		Category:        talkInfo.Category,
+		Disposition:     talkInfo.Disposition,
+		RatingScore:     int32(talkInfo.RatingScore),
+	}
+
+	keys := make([]string, 0, len(talkInfo.CustomFields))
+	for key := range talkInfo.CustomFields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		result.CustomFields = append(result.CustomFields, &talksearchpb.TalkSearchCustomField{
+			Key:   key,
+			Value: talkInfo.CustomFields[key],
+		})
+	}
+
+	return result
+}
+
+func TalkSearchResultsDB2Pb(talkInfos []*defs.TalkInfoR) []*talksearchpb.TalkSearchResult {
+	results := make([]*talksearchpb.TalkSearchResult, 0, len(talkInfos))
+
+	for _, talkInfo := range talkInfos {
+		results = append(results, TalkSearchResultDB2Pb(talkInfo))
+	}
+
+	return results
+}
+
+// TalkSearchStatusesPb2Db ok is false if any of statuses is unknown.
+func TalkSearchStatusesPb2Db(statuses []talksearchpb.TalkSearchStatus) (dbStatuses []defs.TalkStatus, ok bool) {
+	for _, status := range statuses {
+		if status <= talksearchpb.TalkSearchStatus_TALK_SEARCH_STATUS_UNSPECIFIED ||
+			status > talksearchpb.TalkSearchStatus_TALK_SEARCH_STATUS_ABANDONED {
+			return
+		}
+
+		dbStatuses = append(dbStatuses, defs.TalkStatus(status))
+	}
+
+	ok = true
+
+	return
+}
+
+func TalkSortPb2Db(talkSort talksearchpb.TalkSearchSort) defs.TalkSort {
+	if talkSort == talksearchpb.TalkSearchSort_TALK_SEARCH_SORT_START_AT_ASC {
+		return defs.TalkSortStartAtAsc
+	}
+
+	return defs.TalkSortStartAtDesc
+}
+
+// TalkCursorDB2Pb the cursor is opaque to the clients.
+func TalkCursorDB2Pb(cursor *defs.TalkCursor) string {
+	if cursor == nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(cursor.StartAt, 10) + ":" + cursor.TalkID))
+}
+
+// TalkCursorPb2Db cursor nil for an empty s, ok is false if s isn't made by TalkCursorDB2Pb.
+func TalkCursorPb2Db(s string) (cursor *defs.TalkCursor, ok bool) {
+	if s == "" {
+		ok = true
+
+		return
+	}
+
+	d, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return
+	}
+
+	startAt, talkID, found := strings.Cut(string(d), ":")
+	if !found || talkID == "" {
+		return
+	}
+
+	cursor = &defs.TalkCursor{TalkID: talkID}
+
+	cursor.StartAt, err = strconv.ParseInt(startAt, 10, 64)
+	if err != nil {
+		cursor = nil
+
+		return
+	}
+
+	ok = true
+
+	return
+}
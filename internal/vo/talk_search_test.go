@@ -0,0 +1,23 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTalkCursor(t *testing.T) {
+	cursor, ok := TalkCursorPb2Db(TalkCursorDB2Pb(&defs.TalkCursor{StartAt: 1700000000, TalkID: "65a1b2c3d4e5f60718293a4b"}))
+	assert.True(t, ok)
+	assert.EqualValues(t, &defs.TalkCursor{StartAt: 1700000000, TalkID: "65a1b2c3d4e5f60718293a4b"}, cursor)
+
+	cursor, ok = TalkCursorPb2Db("")
+	assert.True(t, ok)
+	assert.Nil(t, cursor)
+
+	for _, s := range []string{"!", "MTcwMDAwMDAwMA", "eDo2NWExYg"} {
+		_, ok = TalkCursorPb2Db(s)
+		assert.False(t, ok, s)
+	}
+}
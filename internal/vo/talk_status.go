@@ -0,0 +1,45 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// the TalkInfo field newer clients read the lifecycle status from, next to the opened/closed status
+// older clients know, it's carried as an unknown field until a proto release has it.
+const talkInfoLifecycleStatusField protowire.Number = 12
+
+// pbTalkLifecycleStatuses mirrors
+//
+//	enum TalkLifecycleStatus {
+//	  TALK_LIFECYCLE_STATUS_UNSPECIFIED = 0;
+//	  TALK_LIFECYCLE_STATUS_QUEUED = 1;
+//	  TALK_LIFECYCLE_STATUS_ASSIGNED = 2;
+//	  TALK_LIFECYCLE_STATUS_WAITING_ON_CUSTOMER = 3;
+//	  TALK_LIFECYCLE_STATUS_RESOLVED = 4;
+//	  TALK_LIFECYCLE_STATUS_CLOSED = 5;
+//	  TALK_LIFECYCLE_STATUS_ABANDONED = 6;
+//	}
+var pbTalkLifecycleStatuses = map[defs.TalkStatus]uint64{
+	defs.TalkStatusQueued:            1,
+	defs.TalkStatusAssigned:          2,
+	defs.TalkStatusWaitingOnCustomer: 3,
+	defs.TalkStatusResolved:          4,
+	defs.TalkStatusClosed:            5,
+	defs.TalkStatusAbandoned:         6,
+}
+
+// setTalkInfoLifecycleStatus sets
+//
+//	TalkLifecycleStatus lifecycle_status = 12;
+//
+// of TalkInfo.
+func setTalkInfoLifecycleStatus(pbTalkInfo *customertalkpb.TalkInfo, status defs.TalkStatus) {
+	v, ok := pbTalkLifecycleStatuses[status]
+	if !ok {
+		return
+	}
+
+	appendUnknownVarint(pbTalkInfo.ProtoReflect(), talkInfoLifecycleStatusField, v)
+}
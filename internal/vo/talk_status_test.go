@@ -0,0 +1,38 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/sbasestarter/customer-service-be/internal/defs"
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestTalkInfoLifecycleStatus(t *testing.T) {
+	lifecycleStatus := func(pbTalkInfo *customertalkpb.TalkInfo) (status uint64) {
+		assert.True(t, consumeFields(pbTalkInfo.ProtoReflect().GetUnknown(), func(num protowire.Number, v uint64,
+			b []byte) {
+			if num == talkInfoLifecycleStatusField && b == nil {
+				status = v
+			}
+		}))
+
+		return
+	}
+
+	talkInfo := &defs.TalkInfoR{TalkInfoW: defs.TalkInfoW{Status: defs.TalkStatusResolved, Tags: []string{"vip"}}}
+
+	pbTalkInfo := TalkInfoRDb2Pb(talkInfo)
+	assert.EqualValues(t, customertalkpb.TalkStatus_TALK_STATUS_CLOSED, pbTalkInfo.GetStatus())
+	assert.EqualValues(t, 4, lifecycleStatus(pbTalkInfo))
+
+	talkInfo.Status = defs.TalkStatusWaitingOnCustomer
+
+	pbTalkInfo = TalkInfoRDb2Pb4Servicer(talkInfo)
+	assert.EqualValues(t, customertalkpb.TalkStatus_TALK_STATUS_OPENED, pbTalkInfo.GetStatus())
+	assert.EqualValues(t, 3, lifecycleStatus(pbTalkInfo))
+
+	talkInfo.Status = defs.TalkStatusNone
+	assert.EqualValues(t, 0, lifecycleStatus(TalkInfoRDb2Pb(talkInfo)))
+}
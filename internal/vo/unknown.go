@@ -0,0 +1,97 @@
+package vo
+
+import (
+	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The fields newer clients and servers exchange ahead of a proto release are read from and written to the
+// unknown fields of the released messages, every such field goes through the helpers below.
+
+// consumeFields calls do with the varint or the bytes value of every field in b, ok is false if b is malformed.
+// value is nil for a varint and never nil for a bytes field, even an empty one.
+func consumeFields(b []byte, do func(num protowire.Number, v uint64, value []byte)) (ok bool) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return
+		}
+
+		b = b[n:]
+
+		switch typ {
+		case protowire.VarintType:
+			var v uint64
+
+			if v, n = protowire.ConsumeVarint(b); n < 0 {
+				return
+			}
+
+			do(num, v, nil)
+		case protowire.BytesType:
+			var value []byte
+
+			if value, n = protowire.ConsumeBytes(b); n < 0 {
+				return
+			}
+
+			if value == nil {
+				value = []byte{}
+			}
+
+			do(num, 0, value)
+		default:
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return
+			}
+		}
+
+		b = b[n:]
+	}
+
+	ok = true
+
+	return
+}
+
+// consumeBytesField returns the first bytes field target in b, ok is false if there is none or b is malformed.
+func consumeBytesField(b []byte, target protowire.Number) (value []byte, ok bool) {
+	if !consumeFields(b, func(num protowire.Number, _ uint64, fieldValue []byte) {
+		if num == target && fieldValue != nil && !ok {
+			value, ok = fieldValue, true
+		}
+	}) {
+		return nil, false
+	}
+
+	return
+}
+
+func appendUnknownVarint(m protoreflect.Message, num protowire.Number, v uint64) {
+	d := protowire.AppendTag(m.GetUnknown(), num, protowire.VarintType)
+	d = protowire.AppendVarint(d, v)
+
+	m.SetUnknown(d)
+}
+
+func appendUnknownBytes(m protoreflect.Message, num protowire.Number, value []byte) {
+	d := protowire.AppendTag(m.GetUnknown(), num, protowire.BytesType)
+	d = protowire.AppendBytes(d, value)
+
+	m.SetUnknown(d)
+}
+
+func talkResponseWithUnknown(num protowire.Number, value []byte) *customertalkpb.TalkResponse {
+	resp := &customertalkpb.TalkResponse{}
+	appendUnknownBytes(resp.ProtoReflect(), num, value)
+
+	return resp
+}
+
+func serviceResponseWithUnknown(num protowire.Number, value []byte) *customertalkpb.ServiceResponse {
+	resp := &customertalkpb.ServiceResponse{}
+	appendUnknownBytes(resp.ProtoReflect(), num, value)
+
+	return resp
+}
@@ -0,0 +1,40 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestConsumeBytesField(t *testing.T) {
+	var d []byte
+	d = protowire.AppendTag(d, 1, protowire.VarintType)
+	d = protowire.AppendVarint(d, 7)
+	d = protowire.AppendTag(d, 2, protowire.BytesType)
+	d = protowire.AppendBytes(d, nil)
+	d = protowire.AppendTag(d, 3, protowire.Fixed32Type)
+	d = protowire.AppendFixed32(d, 9)
+	d = protowire.AppendTag(d, 4, protowire.BytesType)
+	d = protowire.AppendString(d, "first")
+	d = protowire.AppendTag(d, 4, protowire.BytesType)
+	d = protowire.AppendString(d, "last")
+
+	// an empty message is there
+	value, ok := consumeBytesField(d, 2)
+	assert.True(t, ok)
+	assert.EqualValues(t, 0, len(value))
+
+	value, ok = consumeBytesField(d, 4)
+	assert.True(t, ok)
+	assert.EqualValues(t, "first", string(value))
+
+	_, ok = consumeBytesField(d, 1)
+	assert.False(t, ok)
+
+	_, ok = consumeBytesField(d, 5)
+	assert.False(t, ok)
+
+	_, ok = consumeBytesField(d[:len(d)-1], 2)
+	assert.False(t, ok)
+}
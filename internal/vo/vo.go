@@ -6,14 +6,15 @@ import (
 	"github.com/sbasestarter/customer-service-proto/gens/customertalkpb"
 )
 
-func TaskStatusMapPb2Db(status customertalkpb.TalkStatus) defs.TalkStatus {
+// TaskStatusMapPb2Db returns the statuses the protocol status stands for, the protocol only knows opened and closed.
+func TaskStatusMapPb2Db(status customertalkpb.TalkStatus) []defs.TalkStatus {
 	switch status {
 	case customertalkpb.TalkStatus_TALK_STATUS_OPENED:
-		return defs.TalkStatusOpened
+		return defs.TalkStatusesOpened
 	case customertalkpb.TalkStatus_TALK_STATUS_CLOSED:
-		return defs.TalkStatusClosed
+		return defs.TalkStatusesClosed
 	default:
-		return defs.TalkStatusNone
+		return []defs.TalkStatus{defs.TalkStatusNone}
 	}
 }
 
@@ -25,17 +26,18 @@ func TaskStatusesMapPb2Db(statuses []customertalkpb.TalkStatus) []defs.TalkStatu
 	rStatues := make([]defs.TalkStatus, 0, len(statuses))
 
 	for _, talkStatus := range statuses {
-		rStatues = append(rStatues, TaskStatusMapPb2Db(talkStatus))
+		rStatues = append(rStatues, TaskStatusMapPb2Db(talkStatus)...)
 	}
 
 	return rStatues
 }
 
+// TaskStatusMapDB2Pb returns the status older clients know, TalkInfoRDb2Pb adds the lifecycle status next to it.
 func TaskStatusMapDB2Pb(status defs.TalkStatus) customertalkpb.TalkStatus {
 	switch status {
-	case defs.TalkStatusOpened:
+	case defs.TalkStatusQueued, defs.TalkStatusAssigned, defs.TalkStatusWaitingOnCustomer:
 		return customertalkpb.TalkStatus_TALK_STATUS_OPENED
-	case defs.TalkStatusClosed:
+	case defs.TalkStatusResolved, defs.TalkStatusClosed, defs.TalkStatusAbandoned:
 		return customertalkpb.TalkStatus_TALK_STATUS_CLOSED
 	default:
 		return customertalkpb.TalkStatus_TALK_STATUS_UNSPECIFIED
@@ -47,7 +49,7 @@ func TalkInfoRDb2Pb(talkInfo *defs.TalkInfoR) *customertalkpb.TalkInfo {
 		return nil
 	}
 
-	return &customertalkpb.TalkInfo{
+	pbTalkInfo := &customertalkpb.TalkInfo{
 		TalkId:       talkInfo.TalkID,
 		Status:       TaskStatusMapDB2Pb(talkInfo.Status),
 		Title:        talkInfo.Title,
@@ -55,6 +57,10 @@ func TalkInfoRDb2Pb(talkInfo *defs.TalkInfoR) *customertalkpb.TalkInfo {
 		FinishedAt:   uint64(talkInfo.FinishedAt),
 		CustomerName: talkInfo.CreatorUserName,
 	}
+
+	setTalkInfoLifecycleStatus(pbTalkInfo, talkInfo.Status)
+
+	return pbTalkInfo
 }
 
 func TalkInfoRsDB2Pb(talkInfos []*defs.TalkInfoR) []*customertalkpb.TalkInfo {
@@ -91,16 +97,30 @@ func TalkMessageWPb2Db(message *customertalkpb.TalkMessageW) *defs.TalkMessageW
 	return dbMessage
 }
 
-func TalkMessageDB2Pb4Customer(message *defs.TalkMessageW) *customertalkpb.TalkMessage {
+// TalkMessageDB2Pb4Customer shows the servicers by their profiles.
+func TalkMessageDB2Pb4Customer(message *defs.TalkMessageW, profiles defs.ServicerProfiles) *customertalkpb.TalkMessage {
 	pbMessage := talkMessageDB2Pb(message)
-	if pbMessage != nil {
-		if pbMessage.CustomerMessage {
-			pbMessage.User = "您"
-		} else {
-			pbMessage.User = "客服"
-		}
+	if pbMessage == nil {
+		return nil
 	}
 
+	if pbMessage.CustomerMessage {
+		pbMessage.User = "您"
+
+		return pbMessage
+	}
+
+	profile := profiles.GetServicerProfile(message.SenderID)
+
+	if message.Type == defs.TalkMessageTypeSystem {
+		setSystemMessage(pbMessage, message, profile.Name)
+
+		return pbMessage
+	}
+
+	pbMessage.User = profile.Name
+	setTalkMessageAvatar(pbMessage, profile.Avatar)
+
 	return pbMessage
 }
 
@@ -108,6 +128,15 @@ func TalkMessageDB2Pb4Servicer(message *defs.TalkMessageW) *customertalkpb.TalkM
 	pbMessage := talkMessageDB2Pb(message)
 	if pbMessage != nil {
 		pbMessage.User = fmt.Sprintf("%s[%d]", pbMessage.User, message.SenderID)
+
+		if message.Type == defs.TalkMessageTypeSystem {
+			servicerName := pbMessage.User
+
+			setSystemMessage(pbMessage, message, servicerName)
+			setTalkTransferDetail(pbMessage, message, servicerName)
+		} else if message.Type == defs.TalkMessageTypeNote {
+			setTalkMessageNote(pbMessage)
+		}
 	}
 
 	return pbMessage
@@ -124,8 +153,10 @@ func talkMessageDB2Pb(message *defs.TalkMessageW) *customertalkpb.TalkMessage {
 		User:            message.SenderUserName,
 	}
 
+	setTalkMessageSeq(pbMessage, message.Seq)
+
 	switch message.Type {
-	case defs.TalkMessageTypeText:
+	case defs.TalkMessageTypeText, defs.TalkMessageTypeNote:
 		pbMessage.Message = &customertalkpb.TalkMessage_Text{
 			Text: message.Text,
 		}
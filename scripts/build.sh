@@ -8,6 +8,8 @@ GOARCH=amd64 GOOS=linux go build -ldflags "-s -w" -o $dest/customerserver cmd/cu
 GOARCH=amd64 GOOS=linux go build -ldflags "-s -w" -o $dest/customeruserserver cmd/customeruserserver/main.go
 GOARCH=amd64 GOOS=linux go build -ldflags "-s -w" -o $dest/servicerserver cmd/servicerserver/main.go
 GOARCH=amd64 GOOS=linux go build -ldflags "-s -w" -o $dest/serviceruserserver cmd/serviceruserserver/main.go
+GOARCH=amd64 GOOS=linux go build -ldflags "-s -w" -o $dest/migratetalkmessages cmd/migratetalkmessages/main.go
+GOARCH=amd64 GOOS=linux go build -ldflags "-s -w" -o $dest/exporttalks cmd/exporttalks/main.go
 
 GOARCH=amd64 GOOS=linux go build -ldflags "-s -w" -o $dest/wscustomer cmd/ws-be/customer/main.go
 GOARCH=amd64 GOOS=linux go build -ldflags "-s -w" -o $dest/wsservicer cmd/ws-be/servicer/main.go